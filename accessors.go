@@ -0,0 +1,164 @@
+package squirrel
+
+import (
+	"strconv"
+)
+
+// Accessors let middlewares (see StatementBuilderType.WithMiddleware) inspect
+// a query without parsing its SQL. Slices are returned as copies, so changing
+// them doesn't change the builder; use the builder methods to rewrite it.
+
+// limitValue returns the number set by Limit/LimitParam or Offset/OffsetParam.
+func limitValue(s Sqlizer) (uint64, bool) {
+	p, ok := s.(*part)
+	if !ok {
+		return 0, false
+	}
+	pred, _ := p.pred.(string)
+	if pred == "?" && len(p.args) == 1 {
+		n, ok := p.args[0].(uint64)
+		return n, ok
+	}
+	n, err := strconv.ParseUint(pred, 10, 64)
+	return n, err == nil
+}
+
+// GetPlaceholderFormat returns the PlaceholderFormat of the query.
+func (b SelectBuilder) GetPlaceholderFormat() PlaceholderFormat {
+	return b.data.PlaceholderFormat
+}
+
+// GetColumns returns the result columns of the query.
+func (b SelectBuilder) GetColumns() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.Columns...)
+}
+
+// GetFrom returns the FROM clause of the query, or nil if it isn't set.
+func (b SelectBuilder) GetFrom() Sqlizer {
+	return b.data.From
+}
+
+// GetJoins returns the JOIN clauses of the query.
+func (b SelectBuilder) GetJoins() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.Joins...)
+}
+
+// GetWhereParts returns the WHERE conditions of the query, which are joined
+// with AND.
+//
+// Ex:
+//     requireTenant := func(b interface{}) interface{} {
+//         if sb, ok := b.(sq.SelectBuilder); ok {
+//             for _, w := range sb.GetWhereParts() {
+//                 if sql, _, _ := w.ToSql(); strings.Contains(sql, "tenant_id") {
+//                     return sb
+//                 }
+//             }
+//             return sb.Where(sq.Eq{"tenant_id": tenantID})
+//         }
+//         return b
+//     }
+func (b SelectBuilder) GetWhereParts() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.WhereParts...)
+}
+
+// GetGroupBys returns the GROUP BY expressions of the query.
+func (b SelectBuilder) GetGroupBys() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.GroupBys...)
+}
+
+// GetHavingParts returns the HAVING conditions of the query.
+func (b SelectBuilder) GetHavingParts() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.HavingParts...)
+}
+
+// GetOrderByParts returns the ORDER BY expressions of the query.
+func (b SelectBuilder) GetOrderByParts() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.OrderByParts...)
+}
+
+// GetLimit returns the limit set by Limit or LimitParam. ok is false if
+// there is no limit.
+func (b SelectBuilder) GetLimit() (limit uint64, ok bool) {
+	return limitValue(b.data.Limit)
+}
+
+// GetOffset returns the offset set by Offset or OffsetParam. ok is false if
+// there is no offset.
+func (b SelectBuilder) GetOffset() (offset uint64, ok bool) {
+	return limitValue(b.data.Offset)
+}
+
+// GetPlaceholderFormat returns the PlaceholderFormat of the query.
+func (b InsertBuilder) GetPlaceholderFormat() PlaceholderFormat {
+	return b.data.PlaceholderFormat
+}
+
+// GetTable returns the table set by Into.
+func (b InsertBuilder) GetTable() string {
+	return b.data.Into
+}
+
+// GetColumns returns the columns set by Columns.
+func (b InsertBuilder) GetColumns() []string {
+	return append([]string(nil), b.data.Columns...)
+}
+
+// GetValues returns the rows of values set by Values.
+func (b InsertBuilder) GetValues() [][]interface{} {
+	return append([][]interface{}(nil), b.data.Values...)
+}
+
+// GetPlaceholderFormat returns the PlaceholderFormat of the query.
+func (b UpdateBuilder) GetPlaceholderFormat() PlaceholderFormat {
+	return b.data.PlaceholderFormat
+}
+
+// GetTable returns the table to update.
+func (b UpdateBuilder) GetTable() string {
+	return b.data.Table
+}
+
+// GetSetMap returns the values set by Set and SetMap, by column.
+func (b UpdateBuilder) GetSetMap() map[string]interface{} {
+	set := make(map[string]interface{}, len(b.data.SetClauses))
+	for _, c := range b.data.SetClauses {
+		set[c.column] = c.value
+	}
+	return set
+}
+
+// GetFrom returns the FROM clause of the query, or nil if it isn't set.
+func (b UpdateBuilder) GetFrom() Sqlizer {
+	return b.data.From
+}
+
+// GetWhereParts returns the WHERE conditions of the query.
+func (b UpdateBuilder) GetWhereParts() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.WhereParts...)
+}
+
+// GetLimit returns the limit of the query. ok is false if there is no limit.
+func (b UpdateBuilder) GetLimit() (limit uint64, ok bool) {
+	return limitValue(b.data.Limit)
+}
+
+// GetPlaceholderFormat returns the PlaceholderFormat of the query.
+func (b DeleteBuilder) GetPlaceholderFormat() PlaceholderFormat {
+	return b.data.PlaceholderFormat
+}
+
+// GetTable returns the table set by From.
+func (b DeleteBuilder) GetTable() string {
+	return b.data.From
+}
+
+// GetWhereParts returns the WHERE conditions of the query.
+func (b DeleteBuilder) GetWhereParts() []Sqlizer {
+	return append([]Sqlizer(nil), b.data.WhereParts...)
+}
+
+// GetLimit returns the limit of the query. ok is false if there is no limit.
+func (b DeleteBuilder) GetLimit() (limit uint64, ok bool) {
+	return limitValue(b.data.Limit)
+}
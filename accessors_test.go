@@ -0,0 +1,98 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderAccessors(t *testing.T) {
+	b := Select("a", "b").
+		From("t").
+		Join("u USING (id)").
+		Where(Eq{"c": 1}).
+		GroupBy("a").
+		Having("COUNT(*) > ?", 1).
+		OrderBy("a").
+		Limit(10).
+		OffsetParam(20).
+		PlaceholderFormat(Dollar)
+
+	assert.Len(t, b.GetColumns(), 2)
+	from, _, _ := b.GetFrom().ToSql()
+	assert.Equal(t, "t", from)
+	assert.Len(t, b.GetJoins(), 1)
+	where, args, _ := b.GetWhereParts()[0].ToSql()
+	assert.Equal(t, "c = ?", where)
+	assert.Equal(t, []interface{}{1}, args)
+	assert.Len(t, b.GetGroupBys(), 1)
+	assert.Len(t, b.GetHavingParts(), 1)
+	assert.Len(t, b.GetOrderByParts(), 1)
+	assert.Equal(t, Dollar, b.GetPlaceholderFormat())
+
+	limit, ok := b.GetLimit()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), limit)
+	offset, ok := b.GetOffset()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(20), offset)
+
+	_, ok = b.RemoveLimit().GetLimit()
+	assert.False(t, ok)
+}
+
+func TestSelectBuilderAccessorsCopy(t *testing.T) {
+	b := Select("a").Where("b = 1")
+	b.GetColumns()[0] = Expr("c")
+	b.GetWhereParts()[0] = Expr("d = 1")
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a WHERE b = 1", sql)
+}
+
+func TestSelectBuilderAccessorsMiddleware(t *testing.T) {
+	capLimit := func(b interface{}) interface{} {
+		sb := b.(SelectBuilder)
+		if limit, ok := sb.GetLimit(); !ok || limit > 100 {
+			return sb.Limit(100)
+		}
+		return sb
+	}
+	sb := StatementBuilder.WithMiddleware(capLimit)
+
+	sql, _, err := sb.Select("*").From("t").Limit(1000).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t LIMIT 100", sql)
+
+	sql, _, err = sb.Select("*").From("t").Limit(5).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t LIMIT 5", sql)
+}
+
+func TestInsertBuilderAccessors(t *testing.T) {
+	b := Insert("t").Columns("a", "b").Values(1, 2).Values(3, 4)
+	assert.Equal(t, "t", b.GetTable())
+	assert.Equal(t, []string{"a", "b"}, b.GetColumns())
+	assert.Equal(t, [][]interface{}{{1, 2}, {3, 4}}, b.GetValues())
+	assert.Equal(t, Question, b.GetPlaceholderFormat())
+}
+
+func TestUpdateBuilderAccessors(t *testing.T) {
+	b := Update("t").Set("a", 1).Set("b", 2).Where("c = ?", 3).LimitParam(5)
+	assert.Equal(t, "t", b.GetTable())
+	assert.Equal(t, map[string]interface{}{"a": 1, "b": 2}, b.GetSetMap())
+	assert.Nil(t, b.GetFrom())
+	assert.Len(t, b.GetWhereParts(), 1)
+	limit, ok := b.GetLimit()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(5), limit)
+}
+
+func TestDeleteBuilderAccessors(t *testing.T) {
+	b := Delete("t").Where("a = ?", 1)
+	assert.Equal(t, "t", b.GetTable())
+	assert.Len(t, b.GetWhereParts(), 1)
+	_, ok := b.GetLimit()
+	assert.False(t, ok)
+}
@@ -0,0 +1,175 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+)
+
+type batchData struct {
+	PlaceholderFormat PlaceholderFormat
+	RunWith           BaseRunner
+	Pragmas           []pragma
+	Statements        []Sqlizer
+}
+
+func (d *batchData) Exec() (sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	return ExecWith(d.RunWith, d)
+}
+
+func (d *batchData) ToSql() (sqlStr string, args []interface{}, err error) {
+	sqlStr, args, err = d.toSqlRaw()
+	if err != nil {
+		return
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	if err != nil {
+		return
+	}
+	sqlStr = pragmasToSql(d.Pragmas) + sqlStr
+	return
+}
+
+func (d *batchData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if len(d.Statements) == 0 {
+		err = builderError("batch", "no statements")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	for i, s := range d.Statements {
+		stmtSql, stmtArgs, err := nestedToSql(s)
+		if err != nil {
+			return "", nil, clauseError("batch", fmt.Sprintf("statement %d", i), err)
+		}
+		if i > 0 {
+			sql.WriteString("; ")
+		}
+		sql.WriteString(stmtSql)
+		args = append(args, stmtArgs...)
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// BatchBuilder builds several statements into a single SQL text separated by
+// semicolons, with the args of all of them, to be sent in one round trip,
+// e.g. as a YDB transaction:
+//     Batch(
+//         Update("accounts").Set("balance", Expr("balance - ?", 10)).Where(Eq{"id": 1}),
+//         Update("accounts").Set("balance", Expr("balance + ?", 10)).Where(Eq{"id": 2}),
+//     ).PlaceholderFormat(DollarP)
+//     // UPDATE accounts SET balance = balance - $p1 WHERE id = $p2;
+//     // UPDATE accounts SET balance = balance + $p3 WHERE id = $p4
+//
+// The statements are rendered without their own placeholder formats and
+// pragmas; the placeholders are numbered across the whole batch with the
+// PlaceholderFormat of the batch.
+//
+// Not every driver accepts more than one statement per query; MySQL for one
+// needs multiStatements=true in its DSN.
+type BatchBuilder struct {
+	data batchData
+}
+
+// Batch returns a new BatchBuilder with the given statements.
+func Batch(statements ...Sqlizer) BatchBuilder {
+	return StatementBuilder.Batch(statements...)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// batch.
+func (b BatchBuilder) PlaceholderFormat(f PlaceholderFormat) BatchBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b BatchBuilder) RunWith(runner BaseRunner) BatchBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the batch with the Runner set by RunWith.
+func (b BatchBuilder) Exec() (sql.Result, error) {
+	data := b.data
+	return data.Exec()
+}
+
+// ToSql builds the batch into a SQL string and bound args.
+func (b BatchBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the batch into a SQL string and bound args.
+// It panics if there are any errors.
+func (b BatchBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// When calls fn with b and returns its result if cond is true, and returns b
+// otherwise. See SelectBuilder.When.
+func (b BatchBuilder) When(cond bool, fn func(BatchBuilder) BatchBuilder) BatchBuilder {
+	if !cond {
+		return b
+	}
+	return fn(b)
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement in front of the batch.
+//
+// See SelectBuilder.Pragma.
+func (b BatchBuilder) Pragma(name, value string) BatchBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Add adds statements to the end of the batch.
+func (b BatchBuilder) Add(statements ...Sqlizer) BatchBuilder {
+	b.data.Statements = appendSqlizers(b.data.Statements, statements...)
+	return b
+}
+
+// WithNamed adds a YQL named expression, "$name = expr", to the end of the
+// batch. Later statements refer to it by name:
+//     Batch().
+//         WithNamed("$ids", Select("id").From("users").Where(Eq{"active": true})).
+//         Add(Update("orders").Set("flagged", true).Where("user_id IN $ids"))
+//     // $ids = SELECT id FROM users WHERE active = ?;
+//     // UPDATE orders SET flagged = ? WHERE user_id IN $ids
+func (b BatchBuilder) WithNamed(name string, expr Sqlizer) BatchBuilder {
+	return b.Add(yqlNamedExpr{name: name, expr: expr})
+}
+
+// yqlNamedExpr is a YQL "$name = expr" named expression, followed by a semicolon
+// if it is a statement prefix.
+type yqlNamedExpr struct {
+	name       string
+	expr       Sqlizer
+	terminated bool
+}
+
+func (e yqlNamedExpr) ToSql() (string, []interface{}, error) {
+	if len(e.name) < 2 || e.name[0] != '$' || !isIdent(e.name[1:]) {
+		return "", nil, fmt.Errorf("invalid named expression name %q", e.name)
+	}
+	sql, args, err := nestedToSql(e.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	sql = e.name + " = " + sql
+	if e.terminated {
+		sql += ";"
+	}
+	return sql, args, nil
+}
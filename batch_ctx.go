@@ -0,0 +1,26 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+func (d *batchData) ExecContext(ctx context.Context) (sql.Result, error) {
+	if d.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := d.RunWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return ExecContextWith(ctx, ctxRunner, d)
+}
+
+// ExecContext builds and ExecContexts the batch with the Runner set by
+// RunWith.
+func (b BatchBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	data := b.data
+	return data.ExecContext(ctx)
+}
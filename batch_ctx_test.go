@@ -0,0 +1,19 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchBuilderContextRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := Batch(Delete("a"), Delete("b")).RunWith(db).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a; DELETE FROM b", db.LastExecSql)
+
+	_, err = Batch(Delete("a")).ExecContext(ctx)
+	assert.Equal(t, RunnerNotSet, err)
+}
@@ -0,0 +1,95 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchBuilderToSql(t *testing.T) {
+	b := Batch(
+		Update("accounts").Set("balance", Expr("balance - ?", 10)).Where(Eq{"id": 1}),
+		Update("accounts").Set("balance", Expr("balance + ?", 10)).Where(Eq{"id": 2}).PlaceholderFormat(Dollar),
+	).Add(
+		Insert("transfers").Columns("src", "dst").Values(1, 2),
+	).PlaceholderFormat(DollarP).Pragma("TablePathPrefix", "/Root/db")
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := `PRAGMA TablePathPrefix("/Root/db"); ` +
+		"UPDATE accounts SET balance = balance - $p1 WHERE id = $p2; " +
+		"UPDATE accounts SET balance = balance + $p3 WHERE id = $p4; " +
+		"INSERT INTO transfers (src,dst) VALUES ($p5,$p6)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{10, 1, 10, 2, 1, 2}, args)
+}
+
+func TestBatchBuilderToSqlErr(t *testing.T) {
+	_, _, err := Batch().ToSql()
+	assert.EqualError(t, err, "batch builder: no statements")
+
+	_, _, err = Batch(Select("a").From("t"), Insert("t")).ToSql()
+	assert.EqualError(t, err, "batch builder: statement 1: insert builder: no values or select")
+}
+
+func TestBatchBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := Batch(Delete("a"), Delete("b")).RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a; DELETE FROM b", db.LastExecSql)
+
+	_, err = Batch(Delete("a")).Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
+
+func TestStatementBuilderBatch(t *testing.T) {
+	sb := StatementBuilder.PlaceholderFormat(Dollar)
+	sql, _, err := sb.Batch(sb.Delete("a").Where("x = ?", 1), sb.Delete("b").Where("y = ?", 2)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a WHERE x = $1; DELETE FROM b WHERE y = $2", sql)
+}
+
+func TestBatchBuilderWithNamed(t *testing.T) {
+	sql, args, err := Batch().
+		WithNamed("$ids", Select("id").From("users").Where(Eq{"active": true})).
+		Add(Update("orders").Set("flagged", true).Where("user_id IN $ids")).
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"$ids = SELECT id FROM users WHERE active = $p1; UPDATE orders SET flagged = $p2 WHERE user_id IN $ids",
+		sql)
+	assert.Equal(t, []interface{}{true, true}, args)
+
+	_, _, err = Batch().WithNamed("ids", Expr("1")).ToSql()
+	assert.EqualError(t, err, `batch builder: statement 0: invalid named expression name "ids"`)
+}
+
+func TestWithNamed(t *testing.T) {
+	ids := Select("id").From("users").Where(Eq{"active": true})
+
+	sql, args, err := Select("*").From("orders").
+		WithNamed("$ids", ids).
+		Where("user_id IN $ids AND total > ?", 10).
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "$ids = SELECT id FROM users WHERE active = $p1; SELECT * FROM orders WHERE user_id IN $ids AND total > $p2", sql)
+	assert.Equal(t, []interface{}{true, 10}, args)
+
+	sql, _, err = Delete("orders").WithNamed("$ids", ids).Where("user_id IN $ids").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "$ids = SELECT id FROM users WHERE active = ?; DELETE FROM orders WHERE user_id IN $ids", sql)
+
+	sql, _, err = Update("orders").WithNamed("$n", Expr("?", 1)).Set("n", Expr("$n")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "$n = ?; UPDATE orders SET n = $n", sql)
+
+	sql, _, err = Insert("archive").WithNamed("$ids", ids).Select(Select("*").From("orders").Where("user_id IN $ids")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "$ids = SELECT id FROM users WHERE active = ?; INSERT INTO archive SELECT * FROM orders WHERE user_id IN $ids", sql)
+
+	_, _, err = Select("*").From("t").WithNamed("$1x", ids).ToSql()
+	assert.EqualError(t, err, `select builder: prefix: invalid named expression name "$1x"`)
+}
@@ -3,14 +3,8 @@ package squirrel
 import (
 	"bytes"
 	"errors"
-
-	"github.com/lann/builder"
 )
 
-func init() {
-	builder.Register(CaseBuilder{}, caseData{})
-}
-
 // sqlizerBuffer is a helper that allows to write many Sqlizers one by one
 // without constant checks for errors that may come from Sqlizer
 type sqlizerBuffer struct {
@@ -52,6 +46,15 @@ func newWhenPart(when interface{}, then interface{}) whenPart {
 	return whenPart{newPart(when), newPart(then)}
 }
 
+// WhenThen is a "WHEN ... THEN ..." pair of a CASE construct, see CaseWhen.
+//
+// As with CaseBuilder.When, When and Then are each either a string of SQL or
+// a Sqlizer, e.g. Eq or another CaseBuilder.
+type WhenThen struct {
+	When interface{}
+	Then interface{}
+}
+
 // caseData holds all the data required to build a CASE SQL construct
 type caseData struct {
 	What      Sqlizer
@@ -91,12 +94,46 @@ func (d *caseData) ToSql() (sqlStr string, args []interface{}, err error) {
 	return sql.ToSql()
 }
 
+// forDialect returns d with its expressions written for the database dialect,
+// and whether that differs from d.
+func (d caseData) forDialect(dialect dialectKind, explicit bool) (caseData, bool) {
+	changed := false
+	if d.What != nil {
+		if what, ok := partForDialect(d.What, dialect, explicit); ok {
+			d.What, changed = what, true
+		}
+	}
+	var whenParts []whenPart
+	for i, p := range d.WhenParts {
+		when, wok := partForDialect(p.when, dialect, explicit)
+		then, tok := partForDialect(p.then, dialect, explicit)
+		if !wok && !tok {
+			continue
+		}
+		if whenParts == nil {
+			whenParts = append([]whenPart(nil), d.WhenParts...)
+		}
+		whenParts[i] = whenPart{when: when, then: then}
+	}
+	if whenParts != nil {
+		d.WhenParts, changed = whenParts, true
+	}
+	if d.Else != nil {
+		if els, ok := partForDialect(d.Else, dialect, explicit); ok {
+			d.Else, changed = els, true
+		}
+	}
+	return d, changed
+}
+
 // CaseBuilder builds SQL CASE construct which could be used as parts of queries.
-type CaseBuilder builder.Builder
+type CaseBuilder struct {
+	data caseData
+}
 
 // ToSql builds the query into a SQL string and bound args.
 func (b CaseBuilder) ToSql() (string, []interface{}, error) {
-	data := builder.GetStruct(b).(caseData)
+	data := b.data
 	return data.ToSql()
 }
 
@@ -112,17 +149,22 @@ func (b CaseBuilder) MustSql() (string, []interface{}) {
 
 // what sets optional value for CASE construct "CASE [value] ..."
 func (b CaseBuilder) what(expr interface{}) CaseBuilder {
-	return builder.Set(b, "What", newPart(expr)).(CaseBuilder)
+	b.data.What = newPart(expr)
+	return b
 }
 
 // When adds "WHEN ... THEN ..." part to CASE construct
 func (b CaseBuilder) When(when interface{}, then interface{}) CaseBuilder {
 	// TODO: performance hint: replace slice of WhenPart with just slice of parts
 	// where even indices of the slice belong to "when"s and odd indices belong to "then"s
-	return builder.Append(b, "WhenParts", newWhenPart(when, then)).(CaseBuilder)
+	whens := b.data.WhenParts
+	b.data.WhenParts = append(whens[:len(whens):len(whens)], newWhenPart(when, then))
+	return b
 }
 
-// What sets optional "ELSE ..." part for CASE construct
+// Else sets optional "ELSE ..." part for CASE construct. expr is either a
+// string of SQL or a Sqlizer, e.g. Expr("?", value) or a nested CaseBuilder.
 func (b CaseBuilder) Else(expr interface{}) CaseBuilder {
-	return builder.Set(b, "Else", newPart(expr)).(CaseBuilder)
+	b.data.Else = newPart(expr)
+	return b
 }
@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -137,7 +138,18 @@ func TestCaseWithNoWhenClause(t *testing.T) {
 
 	assert.Error(t, err)
 
-	assert.Equal(t, "case expression must contain at lease one WHEN clause", err.Error())
+	assert.Equal(t, "select builder: SELECT: case expression must contain at lease one WHEN clause", err.Error())
+}
+
+func TestCaseWithNoWhenClauseUnwrapped(t *testing.T) {
+	_, _, err := Case("something").Else("42").ToSql()
+	assert.EqualError(t, err, "case expression must contain at lease one WHEN clause")
+
+	_, _, err = Select().Column(Case("something").Else("42")).From("table").ToSql()
+	var builderErr *BuilderError
+	assert.True(t, errors.As(err, &builderErr))
+	assert.Equal(t, "SELECT", builderErr.Clause)
+	assert.EqualError(t, builderErr.Err, "case expression must contain at lease one WHEN clause")
 }
 
 func TestCaseBuilderMustSql(t *testing.T) {
@@ -148,3 +160,33 @@ func TestCaseBuilderMustSql(t *testing.T) {
 	}()
 	Case("").MustSql()
 }
+
+func TestCaseWhen(t *testing.T) {
+	caseStmt := CaseWhen(
+		WhenThen{Lt{"age": 13}, Expr("?", "child")},
+		WhenThen{And{Lt{"age": 20}, Eq{"student": true}}, Expr("?", "teen")},
+	).Else(Expr("?", "adult"))
+
+	sql, args, err := Select().Column(Alias(caseStmt, "category")).From("people").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT (CASE WHEN age < ? THEN ? WHEN (age < ? AND student = ?) THEN ? ELSE ? END) "+
+		"AS category FROM people", sql)
+	assert.Equal(t, []interface{}{13, "child", 20, true, "teen", "adult"}, args)
+
+	_, _, err = CaseWhen().ToSql()
+	assert.Error(t, err)
+}
+
+func TestCaseNested(t *testing.T) {
+	inner := Case("kind").
+		When(Expr("?", "a"), Expr("?", 1)).
+		Else(Expr("?", 2))
+	caseStmt := CaseWhen(WhenThen{Eq{"active": true}, inner}).
+		Else(Case().When(Expr("x > ?", 0), "3").Else("4"))
+
+	sql, args, err := Select().Column(caseStmt).From("table").PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT CASE WHEN active = $1 THEN CASE kind WHEN $2 THEN $3 ELSE $4 END "+
+		"ELSE CASE WHEN x > $5 THEN 3 ELSE 4 END END FROM table", sql)
+	assert.Equal(t, []interface{}{true, "a", 1, 2, 0}, args)
+}
@@ -0,0 +1,468 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ddlExec is shared by the Exec methods of the scheme builders.
+func ddlExec(runner BaseRunner, s Sqlizer) (sql.Result, error) {
+	if runner == nil {
+		return nil, RunnerNotSet
+	}
+	return ExecWith(runner, s)
+}
+
+// finalizeDDL replaces placeholders and prepends comments and pragmas, like
+// the DML builders do in ToSql.
+func finalizeDDL(f PlaceholderFormat, comments []string, pragmas []pragma, sqlStr string, args []interface{}) (string, []interface{}, error) {
+	sqlStr, args, err := replacePlaceholders(f, sqlStr, args)
+	if err != nil {
+		return "", nil, err
+	}
+	return commentsToSql(comments) + pragmasToSql(pragmas) + sqlStr, args, nil
+}
+
+// columnDef renders "name type constraints..." in a CREATE or ALTER TABLE
+func columnDef(name, typ string, constraints []string) string {
+	return strings.Join(append([]string{name, typ}, constraints...), " ")
+}
+
+// CREATE TABLE
+
+type createTableData struct {
+	PlaceholderFormat PlaceholderFormat
+	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
+	Table             string
+	IfNotExists       bool
+	Columns           []string
+	PrimaryKey        []string
+	Indexes           []Sqlizer
+	Settings          []string
+	Suffixes          []Sqlizer
+}
+
+func (d *createTableData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Table) == 0 {
+		err = builderError("create table", "no table")
+		return
+	}
+	if len(d.Columns) == 0 {
+		err = builderError("create table", "no columns")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("CREATE TABLE ")
+	if d.IfNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	sql.WriteString(d.Table)
+
+	defs := append([]string{}, d.Columns...)
+	if len(d.PrimaryKey) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(d.PrimaryKey, ", ")))
+	}
+
+	sql.WriteString(" (")
+	sql.WriteString(strings.Join(defs, ", "))
+	if len(d.Indexes) > 0 {
+		sql.WriteString(", ")
+		args, err = appendToSql(d.Indexes, sql, ", ", args)
+		if err != nil {
+			err = clauseError("create table", "INDEX", err)
+			return
+		}
+	}
+	sql.WriteString(")")
+
+	if len(d.Settings) > 0 {
+		sql.WriteString(" WITH (")
+		sql.WriteString(strings.Join(d.Settings, ", "))
+		sql.WriteString(")")
+	}
+
+	if len(d.Suffixes) > 0 {
+		sql.WriteString(" ")
+		args, err = appendToSql(d.Suffixes, sql, " ", args)
+		if err != nil {
+			err = clauseError("create table", "suffix", err)
+			return
+		}
+	}
+
+	sqlStr, args, err = finalizeDDL(d.PlaceholderFormat, d.Comments, d.Pragmas, sql.String(), args)
+	return
+}
+
+// CreateTableBuilder builds CREATE TABLE statements.
+type CreateTableBuilder struct {
+	data createTableData
+	builderOptions
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b CreateTableBuilder) PlaceholderFormat(f PlaceholderFormat) CreateTableBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CreateTableBuilder) RunWith(runner BaseRunner) CreateTableBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CreateTableBuilder) Exec() (sql.Result, error) {
+	data := b.data
+	return ddlExec(data.RunWith, &data)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CreateTableBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CreateTableBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query.
+//
+// See SelectBuilder.Pragma.
+func (b CreateTableBuilder) Pragma(name, value string) CreateTableBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b CreateTableBuilder) Comment(comment string) CreateTableBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
+// Table sets the table to be created.
+func (b CreateTableBuilder) Table(table string) CreateTableBuilder {
+	b.data.Table = b.qualifyTable(table)
+	return b
+}
+
+// IfNotExists adds IF NOT EXISTS to the query.
+func (b CreateTableBuilder) IfNotExists() CreateTableBuilder {
+	b.data.IfNotExists = true
+	return b
+}
+
+// Column adds a column definition to the table, e.g.
+// Column("id", "Uint64", "NOT NULL").
+func (b CreateTableBuilder) Column(name, typ string, constraints ...string) CreateTableBuilder {
+	b.data.Columns = appendStrings(b.data.Columns, columnDef(name, typ, constraints))
+	return b
+}
+
+// PrimaryKey sets the PRIMARY KEY columns of the table.
+func (b CreateTableBuilder) PrimaryKey(columns ...string) CreateTableBuilder {
+	b.data.PrimaryKey = columns
+	return b
+}
+
+// Index adds a YQL secondary index definition to the table,
+// "INDEX name GLOBAL ON (columns)".
+//
+// Use IndexDef for covering or async indexes.
+func (b CreateTableBuilder) Index(name string, columns ...string) CreateTableBuilder {
+	return b.IndexDef(TableIndex(name).On(columns...))
+}
+
+// IndexDef adds a YQL secondary index definition built with TableIndex to the
+// table.
+func (b CreateTableBuilder) IndexDef(index TableIndexBuilder) CreateTableBuilder {
+	b.data.Indexes = appendSqlizers(b.data.Indexes, index)
+	return b
+}
+
+// With adds a "setting = value" entry to the WITH clause of the table, e.g.
+// With("AUTO_PARTITIONING_BY_SIZE", "ENABLED") or With("fillfactor", "70").
+func (b CreateTableBuilder) With(setting, value string) CreateTableBuilder {
+	b.data.Settings = appendStrings(b.data.Settings, fmt.Sprintf("%s = %s", setting, value))
+	return b
+}
+
+// TTL sets a YDB time to live on the table: rows expire interval (an ISO 8601
+// duration such as "P30D") after the time stored in column.
+func (b CreateTableBuilder) TTL(interval, column string) CreateTableBuilder {
+	return b.With("TTL", fmt.Sprintf(`Interval("%s") ON %s`, interval, column))
+}
+
+// Suffix adds an expression to the end of the query
+func (b CreateTableBuilder) Suffix(sql string, args ...interface{}) CreateTableBuilder {
+	b.data.Suffixes = appendSqlizers(b.data.Suffixes, Expr(sql, args...))
+	return b
+}
+
+// ALTER TABLE
+
+type alterTableData struct {
+	PlaceholderFormat PlaceholderFormat
+	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
+	Table             string
+	Actions           []Sqlizer
+}
+
+func (d *alterTableData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Table) == 0 {
+		err = builderError("alter table", "no table")
+		return
+	}
+	if len(d.Actions) == 0 {
+		err = builderError("alter table", "no actions")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("ALTER TABLE ")
+	sql.WriteString(d.Table)
+	sql.WriteString(" ")
+
+	args, err = appendToSql(d.Actions, sql, ", ", args)
+	if err != nil {
+		err = clauseError("alter table", "ALTER TABLE", err)
+		return
+	}
+
+	sqlStr, args, err = finalizeDDL(d.PlaceholderFormat, d.Comments, d.Pragmas, sql.String(), args)
+	return
+}
+
+// AlterTableBuilder builds ALTER TABLE statements.
+type AlterTableBuilder struct {
+	data alterTableData
+	builderOptions
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b AlterTableBuilder) PlaceholderFormat(f PlaceholderFormat) AlterTableBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b AlterTableBuilder) RunWith(runner BaseRunner) AlterTableBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b AlterTableBuilder) Exec() (sql.Result, error) {
+	data := b.data
+	return ddlExec(data.RunWith, &data)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b AlterTableBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b AlterTableBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query.
+//
+// See SelectBuilder.Pragma.
+func (b AlterTableBuilder) Pragma(name, value string) AlterTableBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b AlterTableBuilder) Comment(comment string) AlterTableBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
+// Table sets the table to be altered.
+func (b AlterTableBuilder) Table(table string) AlterTableBuilder {
+	b.data.Table = b.qualifyTable(table)
+	return b
+}
+
+// Action adds a raw action to the query, e.g.
+// Action("ALTER COLUMN a SET DEFAULT ?", 0).
+func (b AlterTableBuilder) Action(sql string, args ...interface{}) AlterTableBuilder {
+	b.data.Actions = appendSqlizers(b.data.Actions, Expr(sql, args...))
+	return b
+}
+
+// AddColumn adds an "ADD COLUMN name type constraints..." action.
+func (b AlterTableBuilder) AddColumn(name, typ string, constraints ...string) AlterTableBuilder {
+	return b.Action("ADD COLUMN " + columnDef(name, typ, constraints))
+}
+
+// AddIndex adds an "ADD INDEX ..." action for a YQL secondary index built
+// with TableIndex.
+func (b AlterTableBuilder) AddIndex(index TableIndexBuilder) AlterTableBuilder {
+	b.data.Actions = appendSqlizers(b.data.Actions, ConcatExpr("ADD ", index))
+	return b
+}
+
+// DropIndex adds a "DROP INDEX name" action.
+func (b AlterTableBuilder) DropIndex(name string) AlterTableBuilder {
+	return b.Action("DROP INDEX " + name)
+}
+
+// DropColumn adds a "DROP COLUMN name" action.
+func (b AlterTableBuilder) DropColumn(name string) AlterTableBuilder {
+	return b.Action("DROP COLUMN " + name)
+}
+
+// Set adds a "SET (setting = value)" action, e.g. to change YDB table
+// settings such as AUTO_PARTITIONING_BY_LOAD.
+func (b AlterTableBuilder) Set(setting, value string) AlterTableBuilder {
+	return b.Action(fmt.Sprintf("SET (%s = %s)", setting, value))
+}
+
+// RenameTo adds a "RENAME TO name" action.
+func (b AlterTableBuilder) RenameTo(name string) AlterTableBuilder {
+	return b.Action("RENAME TO " + name)
+}
+
+// DROP TABLE
+
+type dropTableData struct {
+	PlaceholderFormat PlaceholderFormat
+	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
+	Table             string
+	IfExists          bool
+	Cascade           bool
+}
+
+func (d *dropTableData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Table) == 0 {
+		err = builderError("drop table", "no table")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("DROP TABLE ")
+	if d.IfExists {
+		sql.WriteString("IF EXISTS ")
+	}
+	sql.WriteString(d.Table)
+	if d.Cascade {
+		sql.WriteString(" CASCADE")
+	}
+
+	sqlStr, args, err = finalizeDDL(d.PlaceholderFormat, d.Comments, d.Pragmas, sql.String(), args)
+	return
+}
+
+// DropTableBuilder builds DROP TABLE statements.
+type DropTableBuilder struct {
+	data dropTableData
+	builderOptions
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b DropTableBuilder) PlaceholderFormat(f PlaceholderFormat) DropTableBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b DropTableBuilder) RunWith(runner BaseRunner) DropTableBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b DropTableBuilder) Exec() (sql.Result, error) {
+	data := b.data
+	return ddlExec(data.RunWith, &data)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b DropTableBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b DropTableBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query.
+//
+// See SelectBuilder.Pragma.
+func (b DropTableBuilder) Pragma(name, value string) DropTableBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b DropTableBuilder) Comment(comment string) DropTableBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
+// Table sets the table to be dropped.
+func (b DropTableBuilder) Table(table string) DropTableBuilder {
+	b.data.Table = b.qualifyTable(table)
+	return b
+}
+
+// IfExists adds IF EXISTS to the query.
+func (b DropTableBuilder) IfExists() DropTableBuilder {
+	b.data.IfExists = true
+	return b
+}
+
+// Cascade adds CASCADE to the query.
+func (b DropTableBuilder) Cascade() DropTableBuilder {
+	b.data.Cascade = true
+	return b
+}
@@ -0,0 +1,124 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTableBuilderToSql(t *testing.T) {
+	sql, args, err := CreateTable("series").
+		IfNotExists().
+		Column("series_id", "Uint64", "NOT NULL").
+		Column("title", "Utf8").
+		Column("release_date", "Date").
+		PrimaryKey("series_id").
+		Index("title_index", "title").
+		With("AUTO_PARTITIONING_BY_SIZE", "ENABLED").
+		TTL("P30D", "release_date").
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "CREATE TABLE IF NOT EXISTS series (" +
+		"series_id Uint64 NOT NULL, title Utf8, release_date Date, " +
+		"PRIMARY KEY (series_id), INDEX title_index GLOBAL ON (title)) " +
+		`WITH (AUTO_PARTITIONING_BY_SIZE = ENABLED, TTL = Interval("P30D") ON release_date)`
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+}
+
+func TestCreateTableBuilderSuffix(t *testing.T) {
+	sql, args, err := CreateTable("t").
+		Column("id", "INT").
+		Suffix("ENGINE = ?", "InnoDB").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE t (id INT) ENGINE = $1", sql)
+	assert.Equal(t, []interface{}{"InnoDB"}, args)
+}
+
+func TestCreateTableBuilderToSqlErr(t *testing.T) {
+	_, _, err := CreateTable("").Column("id", "INT").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = CreateTable("t").ToSql()
+	assert.Error(t, err)
+}
+
+func TestAlterTableBuilderToSql(t *testing.T) {
+	sql, args, err := AlterTable("series").
+		AddColumn("rating", "Uint8").
+		DropColumn("title").
+		Set("AUTO_PARTITIONING_BY_LOAD", "ENABLED").
+		Action("ALTER COLUMN rating SET DEFAULT ?", 0).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "ALTER TABLE series ADD COLUMN rating Uint8, DROP COLUMN title, " +
+		"SET (AUTO_PARTITIONING_BY_LOAD = ENABLED), ALTER COLUMN rating SET DEFAULT ?"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{0}, args)
+
+	sql, _, err = AlterTable("a").RenameTo("b").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE a RENAME TO b", sql)
+}
+
+func TestAlterTableBuilderToSqlErr(t *testing.T) {
+	_, _, err := AlterTable("t").ToSql()
+	assert.Error(t, err)
+}
+
+func TestDropTableBuilderToSql(t *testing.T) {
+	sql, _, err := DropTable("series").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DROP TABLE series", sql)
+
+	sql, _, err = DropTable("series").IfExists().Cascade().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DROP TABLE IF EXISTS series CASCADE", sql)
+
+	_, _, err = DropTable("").ToSql()
+	assert.Error(t, err)
+}
+
+func TestDDLBuilderMustSql(t *testing.T) {
+	assert.Panics(t, func() { CreateTable("").MustSql() })
+	assert.Panics(t, func() { AlterTable("").MustSql() })
+	assert.Panics(t, func() { DropTable("").MustSql() })
+}
+
+func TestDDLStatementBuilder(t *testing.T) {
+	sb := StatementBuilder.TablePathPrefix("/Root/db").Pragma("AnsiInForEmptyOrNullableItemsCollections", "")
+
+	sql, _, err := sb.DropTable("series").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "PRAGMA AnsiInForEmptyOrNullableItemsCollections; DROP TABLE `/Root/db/series`", sql)
+
+	sql, _, err = sb.CreateTable("series").Column("id", "Uint64").PrimaryKey("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "PRAGMA AnsiInForEmptyOrNullableItemsCollections; "+
+		"CREATE TABLE `/Root/db/series` (id Uint64, PRIMARY KEY (id))", sql)
+}
+
+func TestDDLBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+
+	_, err := CreateTable("t").Column("id", "INT").RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE t (id INT)", db.LastExecSql)
+
+	_, err = AlterTable("t").DropColumn("id").RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE t DROP COLUMN id", db.LastExecSql)
+
+	_, err = DropTable("t").RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "DROP TABLE t", db.LastExecSql)
+}
+
+func TestDDLBuilderNoRunner(t *testing.T) {
+	_, err := DropTable("t").Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
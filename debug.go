@@ -0,0 +1,133 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// placeholderFormatter is implemented by the builders, so that DebugSqlizer
+// can tell which placeholders and literals the rendered query uses.
+type placeholderFormatter interface {
+	placeholderFormat() PlaceholderFormat
+}
+
+func (b SelectBuilder) placeholderFormat() PlaceholderFormat { return b.data.PlaceholderFormat }
+func (b InsertBuilder) placeholderFormat() PlaceholderFormat { return b.data.PlaceholderFormat }
+func (b UpdateBuilder) placeholderFormat() PlaceholderFormat { return b.data.PlaceholderFormat }
+func (b DeleteBuilder) placeholderFormat() PlaceholderFormat { return b.data.PlaceholderFormat }
+
+// debugLiteral renders v as a SQL literal of dialect d.
+func debugLiteral(d dialectKind, v interface{}) string {
+	if named, ok := v.(sql.NamedArg); ok {
+		v = named.Value
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return fmt.Sprintf("[Value error: %s]", err)
+		}
+		v = value
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return debugText(d, v)
+	case bool:
+		if d == dialectSQLServer || d == dialectOracle {
+			if v {
+				return "1"
+			}
+			return "0"
+		}
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case []byte:
+		return debugBytes(d, v)
+	case time.Time:
+		return debugTime(d, v)
+	}
+
+	if d == dialectYQL {
+		// YQL does not convert string literals to numbers
+		switch reflect.ValueOf(v).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return fmt.Sprint(v)
+		}
+	}
+	return debugString(d, fmt.Sprint(v))
+}
+
+var yqlStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+func debugString(d dialectKind, s string) string {
+	if d == dialectYQL {
+		return "'" + yqlStringEscaper.Replace(s) + "'"
+	}
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+// debugText is debugString for string values, which SQL Server only keeps
+// intact outside of Latin-1 as N'...' literals.
+func debugText(d dialectKind, s string) string {
+	if d == dialectSQLServer {
+		return "N" + debugString(d, s)
+	}
+	return debugString(d, s)
+}
+
+func debugBytes(d dialectKind, b []byte) string {
+	h := hex.EncodeToString(b)
+	switch d {
+	case dialectPostgres:
+		return `'\x` + h + `'::bytea`
+	case dialectSQLServer:
+		return "0x" + h
+	case dialectOracle:
+		return "HEXTORAW('" + h + "')"
+	case dialectYQL:
+		buf := &strings.Builder{}
+		buf.WriteByte('\'')
+		for i := 0; i < len(h); i += 2 {
+			buf.WriteString(`\x`)
+			buf.WriteString(h[i : i+2])
+		}
+		buf.WriteByte('\'')
+		return buf.String()
+	}
+	return "X'" + h + "'"
+}
+
+// debugTime renders t as a timestamp literal. Dialects whose literal has no
+// offset get t in UTC, as drivers bind time.Time values.
+func debugTime(d dialectKind, t time.Time) string {
+	switch d {
+	case dialectPostgres:
+		return "'" + t.Format("2006-01-02 15:04:05.999999-07:00") + "'::timestamptz"
+	case dialectOracle:
+		return "TIMESTAMP '" + t.Format("2006-01-02 15:04:05.999999 -07:00") + "'"
+	case dialectYQL:
+		return `Timestamp("` + t.UTC().Format("2006-01-02T15:04:05.000000Z") + `")`
+	}
+	return "'" + t.UTC().Format("2006-01-02 15:04:05.999999") + "'"
+}
+
+// debugNamedArg returns the index of the sql.NamedArg called name in args.
+func debugNamedArg(args []interface{}, name string) int {
+	for i, arg := range args {
+		if named, ok := arg.(sql.NamedArg); ok && named.Name == name {
+			return i
+		}
+	}
+	return -1
+}
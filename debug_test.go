@@ -0,0 +1,54 @@
+package squirrel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugSqlizerPlaceholderFormats(t *testing.T) {
+	b := Select("*").From("t").Where("a = ? AND b = ?", "it's", nil)
+
+	assert.Equal(t, "SELECT * FROM t WHERE a = 'it''s' AND b = NULL",
+		DebugSqlizer(b.PlaceholderFormat(Dollar)))
+	assert.Equal(t, "SELECT * FROM t WHERE a = N'it''s' AND b = NULL",
+		DebugSqlizer(b.PlaceholderFormat(AtP)))
+	assert.Equal(t, "SELECT * FROM t WHERE a = 'it''s' AND b = NULL",
+		DebugSqlizer(b.PlaceholderFormat(Colon)))
+	assert.Equal(t, `SELECT * FROM t WHERE a = 'it\'s' AND b = NULL`,
+		DebugSqlizer(b.PlaceholderFormat(DollarP)))
+}
+
+func TestDebugSqlizerLiterals(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := Insert("t").Values(1, true, []byte("AB"), ts)
+
+	assert.Equal(t, "INSERT INTO t VALUES ('1',TRUE,X'4142','2020-01-02 03:04:05')",
+		DebugSqlizer(b))
+	assert.Equal(t, `INSERT INTO t VALUES ('1',TRUE,'\x4142'::bytea,'2020-01-02 03:04:05+00:00'::timestamptz)`,
+		DebugSqlizer(b.PlaceholderFormat(Dollar)))
+	assert.Equal(t, "INSERT INTO t VALUES ('1',1,0x4142,'2020-01-02 03:04:05')",
+		DebugSqlizer(b.PlaceholderFormat(AtP)))
+	assert.Equal(t, "INSERT INTO t VALUES ('1',1,HEXTORAW('4142'),TIMESTAMP '2020-01-02 03:04:05 +00:00')",
+		DebugSqlizer(b.PlaceholderFormat(Colon)))
+	assert.Equal(t, `INSERT INTO t VALUES (1,TRUE,'\x41\x42',Timestamp("2020-01-02T03:04:05.000000Z"))`,
+		DebugSqlizer(b.PlaceholderFormat(DollarP)))
+}
+
+func TestDebugSqlizerNamedPlaceholders(t *testing.T) {
+	b := Select("*").From("t").
+		Where(Expr("(a = :x OR b = :x)", NamedArgs{"x": 1})).
+		Where("c::text = ?", "s").
+		PlaceholderFormat(NamedPlaceholders(":"))
+
+	assert.Equal(t, "SELECT * FROM t WHERE (a = '1' OR b = '1') AND c::text = 's'", DebugSqlizer(b))
+}
+
+func TestDebugSqlizerNumberedErrors(t *testing.T) {
+	b := Select("*").From("t").Where("a = ?", 1).Suffix("AND b = $2").PlaceholderFormat(Dollar)
+	assert.Contains(t, DebugSqlizer(b), "[DebugSqlizer error: too many placeholders")
+
+	b = Select("*").From("t").Where("a = $1", 1, 2).PlaceholderFormat(Dollar)
+	assert.Contains(t, DebugSqlizer(b), "[DebugSqlizer error: not enough placeholders")
+}
@@ -1,23 +1,25 @@
 package squirrel
 
 import (
-	"bytes"
 	"database/sql"
-	"fmt"
 	"strings"
-
-	"github.com/lann/builder"
 )
 
 type deleteData struct {
 	PlaceholderFormat PlaceholderFormat
 	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
 	Prefixes          []Sqlizer
+	Targets           []string
 	From              string
+	Usings            []string
+	Joins             []Sqlizer
 	WhereParts        []Sqlizer
 	OrderBys          []string
-	Limit             string
-	Offset            string
+	Limit             Sqlizer
+	Offset            Sqlizer
+	Returning         []Sqlizer
 	Suffixes          []Sqlizer
 }
 
@@ -29,29 +31,83 @@ func (d *deleteData) Exec() (sql.Result, error) {
 }
 
 func (d *deleteData) ToSql() (sqlStr string, args []interface{}, err error) {
+	dialect := dialectKindOf(d.PlaceholderFormat)
+	// write expressions like ILike for the database
+	_, explicit := d.PlaceholderFormat.(dialectFormat)
+	written := d.forDialect(dialect, explicit)
+	d = &written
+
+	sqlStr, args, err = d.toSqlRaw()
+	if err != nil {
+		return
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	if err != nil {
+		return
+	}
+
+	sqlStr = commentsToSql(d.Comments) + pragmasToSql(d.Pragmas) + sqlStr
+	return
+}
+
+// forDialect returns d with its expressions, including those in subqueries,
+// written for the database dialect.
+func (d deleteData) forDialect(dialect dialectKind, explicit bool) deleteData {
+	d.Prefixes = forDialect(d.Prefixes, dialect, explicit)
+	d.Joins = forDialect(d.Joins, dialect, explicit)
+	d.WhereParts = forDialect(d.WhereParts, dialect, explicit)
+	d.Returning = forDialect(d.Returning, dialect, explicit)
+	d.Suffixes = forDialect(d.Suffixes, dialect, explicit)
+	return d
+}
+
+func (d *deleteData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 	if len(d.From) == 0 {
-		err = fmt.Errorf("delete statements must specify a From table")
+		err = builderError("delete", "no From table")
 		return
 	}
 
-	sql := &bytes.Buffer{}
+	sql := getBuffer()
+	defer putBuffer(sql)
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("delete", "prefix", err)
 			return
 		}
 
 		sql.WriteString(" ")
 	}
 
-	sql.WriteString("DELETE FROM ")
+	sql.WriteString("DELETE ")
+	if len(d.Targets) > 0 {
+		sql.WriteString(strings.Join(d.Targets, ", "))
+		sql.WriteString(" ")
+	}
+	sql.WriteString("FROM ")
 	sql.WriteString(d.From)
 
+	if len(d.Usings) > 0 {
+		sql.WriteString(" USING ")
+		sql.WriteString(strings.Join(d.Usings, ", "))
+	}
+
+	if len(d.Joins) > 0 {
+		sql.WriteString(" ")
+		args, err = appendToSql(d.Joins, sql, " ", args)
+		if err != nil {
+			err = clauseError("delete", "JOIN", err)
+			return
+		}
+	}
+
 	if len(d.WhereParts) > 0 {
 		sql.WriteString(" WHERE ")
 		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
 		if err != nil {
+			err = clauseError("delete", "WHERE", err)
 			return
 		}
 	}
@@ -61,35 +117,63 @@ func (d *deleteData) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(strings.Join(d.OrderBys, ", "))
 	}
 
-	if len(d.Limit) > 0 {
+	if d.Limit != nil {
 		sql.WriteString(" LIMIT ")
-		sql.WriteString(d.Limit)
+		args, err = appendToSql([]Sqlizer{d.Limit}, sql, "", args)
+		if err != nil {
+			err = clauseError("delete", "LIMIT", err)
+			return
+		}
 	}
 
-	if len(d.Offset) > 0 {
+	if d.Offset != nil {
 		sql.WriteString(" OFFSET ")
-		sql.WriteString(d.Offset)
+		args, err = appendToSql([]Sqlizer{d.Offset}, sql, "", args)
+		if err != nil {
+			err = clauseError("delete", "OFFSET", err)
+			return
+		}
+	}
+
+	if len(d.Returning) > 0 {
+		sql.WriteString(" RETURNING ")
+		args, err = appendToSql(d.Returning, sql, ", ", args)
+		if err != nil {
+			err = clauseError("delete", "RETURNING", err)
+			return
+		}
 	}
 
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
 		args, err = appendToSql(d.Suffixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("delete", "suffix", err)
 			return
 		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
 }
 
 // Builder
 
 // DeleteBuilder builds SQL DELETE statements.
-type DeleteBuilder builder.Builder
+type DeleteBuilder struct {
+	data deleteData
+	builderOptions
+}
 
-func init() {
-	builder.Register(DeleteBuilder{}, deleteData{})
+// build applies the middlewares set with WithMiddleware and returns the data
+// to render.
+func (b DeleteBuilder) build() deleteData {
+	if len(b.middlewares) > 0 {
+		mws := b.middlewares
+		b.middlewares = nil
+		b = applyMiddlewares(b, mws).(DeleteBuilder)
+	}
+	return b.data
 }
 
 // Format methods
@@ -97,19 +181,21 @@ func init() {
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b DeleteBuilder) PlaceholderFormat(f PlaceholderFormat) DeleteBuilder {
-	return builder.Set(b, "PlaceholderFormat", f).(DeleteBuilder)
+	b.data.PlaceholderFormat = f
+	return b
 }
 
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
 func (b DeleteBuilder) RunWith(runner BaseRunner) DeleteBuilder {
-	return setRunWith(b, runner).(DeleteBuilder)
+	b.data.RunWith = wrapRunner(runner)
+	return b
 }
 
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b DeleteBuilder) Exec() (sql.Result, error) {
-	data := builder.GetStruct(b).(deleteData)
+	data := b.build()
 	return data.Exec()
 }
 
@@ -117,10 +203,22 @@ func (b DeleteBuilder) Exec() (sql.Result, error) {
 
 // ToSql builds the query into a SQL string and bound args.
 func (b DeleteBuilder) ToSql() (string, []interface{}, error) {
-	data := builder.GetStruct(b).(deleteData)
+	data := b.build()
 	return data.ToSql()
 }
 
+func (b DeleteBuilder) toSqlRaw() (string, []interface{}, error) {
+	data := b.build()
+	return data.toSqlRaw()
+}
+
+// forDialect writes the expressions of b for the database d, when b is a
+// subquery.
+func (b DeleteBuilder) forDialect(d dialectKind, explicit bool) Sqlizer {
+	b.data = b.data.forDialect(d, explicit)
+	return b
+}
+
 // MustSql builds the query into a SQL string and bound args.
 // It panics if there are any errors.
 func (b DeleteBuilder) MustSql() (string, []interface{}) {
@@ -131,6 +229,55 @@ func (b DeleteBuilder) MustSql() (string, []interface{}) {
 	return sql, args
 }
 
+// When calls fn with b and returns its result if cond is true, and returns b
+// otherwise, to add optional clauses without breaking the chain:
+//     Delete("sessions").Where(Eq{"user_id": id}).
+//         When(keepCurrent, func(b DeleteBuilder) DeleteBuilder { return b.Where(NotEq{"id": current}) })
+func (b DeleteBuilder) When(cond bool, fn func(DeleteBuilder) DeleteBuilder) DeleteBuilder {
+	if !cond {
+		return b
+	}
+	return fn(b)
+}
+
+// ToSqlInterpolated builds the query into a SQL string with the args inlined
+// as literals.
+//
+// See SelectBuilder.ToSqlInterpolated.
+func (b DeleteBuilder) ToSqlInterpolated() (string, error) {
+	return InterpolateSqlizer(b)
+}
+
+// Freeze renders the query once and returns it as a FrozenQuery, which keeps
+// the Runner set by RunWith.
+//
+// See FrozenQuery.
+func (b DeleteBuilder) Freeze() FrozenQuery {
+	data := b.build()
+	q := Freeze(&data)
+	q.runWith = data.RunWith
+	return q
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query, e.g.
+// Pragma("TablePathPrefix", "/Root/db"). An empty value omits the parentheses.
+//
+// Pragmas are only rendered for the outermost query; those set on nested
+// builders are ignored.
+func (b DeleteBuilder) Pragma(name, value string) DeleteBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b DeleteBuilder) Comment(comment string) DeleteBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b DeleteBuilder) Prefix(sql string, args ...interface{}) DeleteBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -138,34 +285,128 @@ func (b DeleteBuilder) Prefix(sql string, args ...interface{}) DeleteBuilder {
 
 // PrefixExpr adds an expression to the very beginning of the query
 func (b DeleteBuilder) PrefixExpr(expr Sqlizer) DeleteBuilder {
-	return builder.Append(b, "Prefixes", expr).(DeleteBuilder)
+	b.data.Prefixes = appendSqlizers(b.data.Prefixes, expr)
+	return b
+}
+
+// WithNamed adds a YQL named expression, "$name = expr;", in front of the
+// query.
+//
+// See SelectBuilder.WithNamed.
+func (b DeleteBuilder) WithNamed(name string, expr Sqlizer) DeleteBuilder {
+	return b.PrefixExpr(yqlNamedExpr{name: name, expr: expr, terminated: true})
 }
 
 // From sets the table to be deleted from.
 func (b DeleteBuilder) From(from string) DeleteBuilder {
-	return builder.Set(b, "From", from).(DeleteBuilder)
+	b.data.From = b.qualifyTable(from)
+	return b
+}
+
+// Targets sets the tables to delete rows from in a MySQL multi-table delete,
+// "DELETE targets FROM table JOIN ...". Rows are deleted only from the
+// targets; the other tables are only used to match rows.
+//
+// Ex:
+//     Delete("orders o").Targets("o").Join("customers c ON c.id = o.customer_id").
+//         Where(Eq{"c.banned": true})
+//     // DELETE o FROM orders o JOIN customers c ON c.id = o.customer_id WHERE c.banned = ?
+func (b DeleteBuilder) Targets(tables ...string) DeleteBuilder {
+	b.data.Targets = appendStrings(b.data.Targets, tables...)
+	return b
+}
+
+// Using adds tables to the PostgreSQL USING clause of the query, which makes
+// their columns available to the WHERE clause.
+//
+// Ex:
+//     Delete("orders o").Using("customers c").Where("c.id = o.customer_id AND c.banned")
+//     // DELETE FROM orders o USING customers c WHERE c.id = o.customer_id AND c.banned
+func (b DeleteBuilder) Using(tables ...string) DeleteBuilder {
+	for _, table := range tables {
+		b.data.Usings = appendStrings(b.data.Usings, b.qualifyTable(table))
+	}
+	return b
+}
+
+// JoinClause adds a join clause to the query, after the FROM table and the
+// USING tables.
+func (b DeleteBuilder) JoinClause(pred interface{}, args ...interface{}) DeleteBuilder {
+	b.data.Joins = appendSqlizers(b.data.Joins, newPart(pred, args...))
+	return b
+}
+
+// Join adds a JOIN clause to the query.
+func (b DeleteBuilder) Join(join string, rest ...interface{}) DeleteBuilder {
+	return b.JoinClause("JOIN "+b.qualifyTable(join), rest...)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query.
+func (b DeleteBuilder) LeftJoin(join string, rest ...interface{}) DeleteBuilder {
+	return b.JoinClause("LEFT JOIN "+b.qualifyTable(join), rest...)
+}
+
+// InnerJoin adds a INNER JOIN clause to the query.
+func (b DeleteBuilder) InnerJoin(join string, rest ...interface{}) DeleteBuilder {
+	return b.JoinClause("INNER JOIN "+b.qualifyTable(join), rest...)
 }
 
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
 func (b DeleteBuilder) Where(pred interface{}, args ...interface{}) DeleteBuilder {
-	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(DeleteBuilder)
+	b.data.WhereParts = appendSqlizers(b.data.WhereParts, newWherePart(pred, args...))
+	return b
 }
 
 // OrderBy adds ORDER BY expressions to the query.
 func (b DeleteBuilder) OrderBy(orderBys ...string) DeleteBuilder {
-	return builder.Extend(b, "OrderBys", orderBys).(DeleteBuilder)
+	b.data.OrderBys = appendStrings(b.data.OrderBys, orderBys...)
+	return b
 }
 
 // Limit sets a LIMIT clause on the query.
 func (b DeleteBuilder) Limit(limit uint64) DeleteBuilder {
-	return builder.Set(b, "Limit", fmt.Sprintf("%d", limit)).(DeleteBuilder)
+	b.data.Limit = b.limitPart(limit)
+	return b
+}
+
+// LimitParam sets a LIMIT clause on the query with the limit bound as a
+// placeholder arg rather than inlined into the SQL.
+func (b DeleteBuilder) LimitParam(limit uint64) DeleteBuilder {
+	b.data.Limit = newPart("?", limit)
+	return b
 }
 
 // Offset sets a OFFSET clause on the query.
 func (b DeleteBuilder) Offset(offset uint64) DeleteBuilder {
-	return builder.Set(b, "Offset", fmt.Sprintf("%d", offset)).(DeleteBuilder)
+	b.data.Offset = b.limitPart(offset)
+	return b
+}
+
+// OffsetParam sets an OFFSET clause on the query with the offset bound as a
+// placeholder arg rather than inlined into the SQL.
+func (b DeleteBuilder) OffsetParam(offset uint64) DeleteBuilder {
+	b.data.Offset = newPart("?", offset)
+	return b
+}
+
+// Returning adds RETURNING expressions to the query.
+func (b DeleteBuilder) Returning(columns ...string) DeleteBuilder {
+	parts := make([]Sqlizer, 0, len(columns))
+	for _, str := range columns {
+		parts = append(parts, newPart(str))
+	}
+	b.data.Returning = appendSqlizers(b.data.Returning, parts...)
+	return b
+}
+
+// ReturningSelect adds a subquery to the RETURNING clause of the query.
+func (b DeleteBuilder) ReturningSelect(from SelectBuilder, alias string) DeleteBuilder {
+	// Prevent misnumbered parameters in nested selects (#183).
+	from = from.PlaceholderFormat(Question)
+	b.data.Returning = appendSqlizers(b.data.Returning, Alias(from, alias))
+	return b
 }
 
 // Suffix adds an expression to the end of the query
@@ -175,11 +416,12 @@ func (b DeleteBuilder) Suffix(sql string, args ...interface{}) DeleteBuilder {
 
 // SuffixExpr adds an expression to the end of the query
 func (b DeleteBuilder) SuffixExpr(expr Sqlizer) DeleteBuilder {
-	return builder.Append(b, "Suffixes", expr).(DeleteBuilder)
+	b.data.Suffixes = appendSqlizers(b.data.Suffixes, expr)
+	return b
 }
 
 func (b DeleteBuilder) Query() (*sql.Rows, error) {
-	data := builder.GetStruct(b).(deleteData)
+	data := b.build()
 	return data.Query()
 }
 
@@ -5,8 +5,6 @@ package squirrel
 import (
 	"context"
 	"database/sql"
-
-	"github.com/lann/builder"
 )
 
 func (d *deleteData) ExecContext(ctx context.Context) (sql.Result, error) {
@@ -47,19 +45,19 @@ func (d *deleteData) QueryRowContext(ctx context.Context) RowScanner {
 
 // ExecContext builds and ExecContexts the query with the Runner set by RunWith.
 func (b DeleteBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
-	data := builder.GetStruct(b).(deleteData)
+	data := b.build()
 	return data.ExecContext(ctx)
 }
 
 // QueryContext builds and QueryContexts the query with the Runner set by RunWith.
 func (b DeleteBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
-	data := builder.GetStruct(b).(deleteData)
+	data := b.build()
 	return data.QueryContext(ctx)
 }
 
 // QueryRowContext builds and QueryRowContexts the query with the Runner set by RunWith.
 func (b DeleteBuilder) QueryRowContext(ctx context.Context) RowScanner {
-	data := builder.GetStruct(b).(deleteData)
+	data := b.build()
 	return data.QueryRowContext(ctx)
 }
 
@@ -79,3 +79,70 @@ func TestDeleteWithQuery(t *testing.T) {
 
 	assert.Equal(t, expectedSql, db.LastQuerySql)
 }
+
+func TestDeleteBuilderLimitOffsetParam(t *testing.T) {
+	sql, args, err := Delete("t").Where("a = ?", 1).LimitParam(2).OffsetParam(3).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t WHERE a = ? LIMIT ? OFFSET ?", sql)
+	assert.Equal(t, []interface{}{1, uint64(2), uint64(3)}, args)
+}
+
+func TestDeleteBuilderReturning(t *testing.T) {
+	sql, args, err := Delete("a").Where("b = ?", 1).Returning("id", "path").Suffix("-- ?", 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM a WHERE b = ? RETURNING id, path -- ?", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestDeleteBuilderNestedPlaceholders(t *testing.T) {
+	del := Delete("t").Where("a = ?", 1).Returning("id").PlaceholderFormat(DollarP)
+	sql, args, err := Select("count(*)").
+		With("d", del).
+		From("d").
+		Where("id > ?", 2).
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "WITH d AS (DELETE FROM t WHERE a = $p1 RETURNING id) SELECT count(*) FROM d WHERE id > $p2", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestDeleteBuilderUsing(t *testing.T) {
+	sql, args, err := Delete("orders o").
+		Using("customers c", "regions r").
+		Where("c.id = o.customer_id AND r.id = c.region_id AND r.name = ?", "north").
+		Returning("o.id").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM orders o USING customers c, regions r "+
+		"WHERE c.id = o.customer_id AND r.id = c.region_id AND r.name = $1 RETURNING o.id", sql)
+	assert.Equal(t, []interface{}{"north"}, args)
+}
+
+func TestDeleteBuilderJoins(t *testing.T) {
+	sql, args, err := Delete("orders o").
+		Targets("o", "i").
+		Join("items i ON i.order_id = o.id").
+		LeftJoin("customers c ON c.id = o.customer_id AND c.region = ?", "north").
+		Where(Eq{"c.banned": true}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE o, i FROM orders o JOIN items i ON i.order_id = o.id "+
+		"LEFT JOIN customers c ON c.id = o.customer_id AND c.region = ? WHERE c.banned = ?", sql)
+	assert.Equal(t, []interface{}{"north", true}, args)
+
+	sql, _, err = StatementBuilder.TablePathPrefix("/Root").Delete("a").Using("b").InnerJoin("c ON c.id = b.id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `/Root/a` USING `/Root/b` INNER JOIN `/Root/c` ON c.id = b.id", sql)
+}
+
+func TestDeleteBuilderWhen(t *testing.T) {
+	sql, args, err := Delete("sessions").Where(Eq{"user_id": 1}).
+		When(true, func(b DeleteBuilder) DeleteBuilder { return b.Where(NotEq{"id": 7}) }).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM sessions WHERE user_id = ? AND id <> ?", sql)
+	assert.Equal(t, []interface{}{1, 7}, args)
+}
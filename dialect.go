@@ -0,0 +1,118 @@
+package squirrel
+
+// Dialect is the flavor of SQL a database speaks: its placeholder format,
+// how it quotes identifiers, and the syntax of statements that differ from
+// database to database, like InsertBuilder.Ignore and Upsert.
+//
+// Without a Dialect, builders infer the database from the PlaceholderFormat,
+// which can't tell MySQL from SQLite, as both use Question. Set one on a
+// StatementBuilderType to target a database explicitly:
+//     sb := StatementBuilder.Dialect(MySQL)
+//     sb.Insert("t").Columns("id").Values(1).Ignore()
+//     // INSERT IGNORE INTO `t` (`id`) VALUES (?)
+//     StatementBuilder.Dialect(SQLite).Insert("t").Columns("id").Values(1).Ignore()
+//     // INSERT OR IGNORE INTO "t" ("id") VALUES (?)
+type Dialect struct {
+	name        string
+	placeholder PlaceholderFormat
+	quoter      Quoter
+	kind        dialectKind
+}
+
+var (
+	// Postgres is the dialect of PostgreSQL.
+	Postgres = Dialect{name: "PostgreSQL", placeholder: Dollar, quoter: DoubleQuoteQuoter, kind: dialectPostgres}
+
+	// MySQL is the dialect of MySQL and MariaDB.
+	MySQL = Dialect{name: "MySQL", placeholder: Question, quoter: BacktickQuoter, kind: dialectMySQL}
+
+	// SQLite is the dialect of SQLite.
+	SQLite = Dialect{name: "SQLite", placeholder: Question, quoter: DoubleQuoteQuoter, kind: dialectSQLite}
+
+	// SQLServer is the dialect of Microsoft SQL Server.
+	SQLServer = Dialect{name: "SQL Server", placeholder: AtP, quoter: BracketQuoter, kind: dialectSQLServer}
+
+	// Oracle is the dialect of Oracle Database.
+	Oracle = Dialect{name: "Oracle", placeholder: Colon, quoter: DoubleQuoteQuoter, kind: dialectOracle}
+
+	// YDB is the YQL dialect of YDB.
+	YDB = Dialect{name: "YDB", placeholder: DollarP, quoter: BacktickQuoter, kind: dialectYQL}
+)
+
+// String returns the name of the database, e.g. "PostgreSQL".
+func (d Dialect) String() string {
+	return d.name
+}
+
+// PlaceholderFormat returns the PlaceholderFormat of the dialect. It carries
+// the dialect along, so builders given it with their PlaceholderFormat method
+// write their SQL for the dialect.
+func (d Dialect) PlaceholderFormat() PlaceholderFormat {
+	return dialectFormat{PlaceholderFormat: d.placeholder, dialect: d.kind}
+}
+
+// Quoter returns the Quoter of the dialect, for use with
+// StatementBuilderType.QuoteIdentifiers.
+func (d Dialect) Quoter() Quoter {
+	return d.quoter
+}
+
+// dialectFormat is a PlaceholderFormat that also tells which dialect the query
+// is written for.
+type dialectFormat struct {
+	PlaceholderFormat
+	dialect dialectKind
+}
+
+func (f dialectFormat) replacePlaceholdersArgs(sql string, args []interface{}) (string, []interface{}, error) {
+	return replacePlaceholders(f.PlaceholderFormat, sql, args)
+}
+
+func (f dialectFormat) debugPlaceholder() string {
+	if pd, ok := f.PlaceholderFormat.(placeholderDebugger); ok {
+		return pd.debugPlaceholder()
+	}
+	return "?"
+}
+
+// dialectKind is the flavor of SQL a query is written in: its literals,
+// quoting and the syntax of clauses that differ from database to database. It
+// is the Dialect set on the StatementBuilderType, if any, or else inferred
+// from the placeholder format of the query.
+type dialectKind int
+
+const (
+	dialectGeneric dialectKind = iota
+	dialectPostgres
+	dialectSQLServer
+	dialectOracle
+	dialectYQL
+	dialectMySQL
+	dialectSQLite
+)
+
+// dialectKindOf returns the dialectKind of queries with the format f.
+func dialectKindOf(f PlaceholderFormat) dialectKind {
+	switch f := f.(type) {
+	case dialectFormat:
+		return f.dialect
+	case dollarFormat:
+		return dialectPostgres
+	case atpFormat:
+		return dialectSQLServer
+	case colonFormat:
+		return dialectOracle
+	case dollarpFormat:
+		return dialectYQL
+	case namedFormat:
+		switch f.prefix {
+		case "@":
+			return dialectSQLServer
+		case ":":
+			return dialectOracle
+		case "$":
+			return dialectYQL
+		}
+	}
+	return dialectGeneric
+}
@@ -0,0 +1,118 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialectPlaceholderFormat(t *testing.T) {
+	tests := []struct {
+		d        Dialect
+		expected string
+	}{
+		{Postgres, `SELECT * FROM "t" WHERE a = $1 AND b = $2`},
+		{MySQL, "SELECT * FROM `t` WHERE a = ? AND b = ?"},
+		{SQLite, `SELECT * FROM "t" WHERE a = ? AND b = ?`},
+		{SQLServer, "SELECT * FROM [t] WHERE a = @p1 AND b = @p2"},
+		{Oracle, `SELECT * FROM "t" WHERE a = :1 AND b = :2`},
+		{YDB, "SELECT * FROM `t` WHERE a = $p1 AND b = $p2"},
+	}
+	for _, test := range tests {
+		sql, args, err := StatementBuilder.Dialect(test.d).
+			Select("*").From("t").Where("a = ?", 1).Where("b = ?", 2).
+			ToSql()
+		assert.NoError(t, err, test.d.String())
+		assert.Equal(t, test.expected, sql, test.d.String())
+		assert.Equal(t, []interface{}{1, 2}, args, test.d.String())
+	}
+}
+
+func TestDialectQuoter(t *testing.T) {
+	sql, _, err := StatementBuilder.Dialect(MySQL).QuoteIdentifiers(MySQL.Quoter()).
+		Select("order").From("group").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `order` FROM `group`", sql)
+
+	assert.Equal(t, "[a]", SQLServer.Quoter().QuoteIdent("a"))
+	assert.Equal(t, `"a"`, Postgres.Quoter().QuoteIdent("a"))
+}
+
+func TestDialectSetsQuoter(t *testing.T) {
+	sql, _, err := StatementBuilder.Dialect(MySQL).Select("order").From("group").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `order` FROM `group`", sql)
+
+	sql, _, err = StatementBuilder.QuoteIdentifiers(DoubleQuoteQuoter).Dialect(MySQL).
+		Select("order").From("group").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "order" FROM "group"`, sql)
+
+	sql, _, err = StatementBuilder.QuoteIdentifiers(nil).Dialect(MySQL).
+		Select("order").From("group").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT order FROM group", sql)
+}
+
+func TestDialectSyntax(t *testing.T) {
+	sql, _, err := StatementBuilder.Dialect(SQLite).Insert("t").Columns("id").Values(1).Ignore().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT OR IGNORE INTO "t" ("id") VALUES (?)`, sql)
+
+	sql, _, err = StatementBuilder.Dialect(MySQL).Insert("t").Columns("id").Values(1).Ignore().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT IGNORE INTO `t` (`id`) VALUES (?)", sql)
+
+	sql, _, err = StatementBuilder.Dialect(MySQL).Select("*").From("t").OrderByCol("a", Asc, NullsLast).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `t` ORDER BY CASE WHEN a IS NULL THEN 1 ELSE 0 END, a ASC", sql)
+
+	sql, _, err = StatementBuilder.Dialect(SQLite).Select("*").From("t").OrderByCol("a", Asc, NullsLast).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "t" ORDER BY a ASC NULLS LAST`, sql)
+
+	sql, err = StatementBuilder.Dialect(MySQL).Select("*").From("t").Where(Eq{"a": "it's"}).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `t` WHERE a = 'it''s'", sql)
+
+	// with NO_BACKSLASH_ESCAPES a backslash is a plain character, without it
+	// an escape, so strings holding one can't be written safely
+	_, err = StatementBuilder.Dialect(MySQL).Select("*").From("t").Where(Eq{"a": `\' OR 1=1 -- `}).ToSqlInterpolated()
+	assert.EqualError(t, err, "cannot interpolate a string containing a backslash for MySQL")
+
+	assert.Equal(t, "SELECT * FROM `t` WHERE a = 'it''s'",
+		DebugSqlizer(StatementBuilder.Dialect(MySQL).Select("*").From("t").Where(Eq{"a": "it's"})))
+}
+
+func TestDialectUpsert(t *testing.T) {
+	upsert := func(d Dialect) UpsertBuilder {
+		return StatementBuilder.Dialect(d).Upsert("t").Columns("id", "n").Values(1, 2)
+	}
+
+	sql, args, err := upsert(Postgres).ConflictKeys("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "t" ("id","n") VALUES ($1,$2) ON CONFLICT ("id") DO UPDATE SET "n" = EXCLUDED."n"`, sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = upsert(SQLite).ConflictKeys("id", "n").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "t" ("id","n") VALUES (?,?) ON CONFLICT ("id", "n") DO NOTHING`, sql)
+
+	sql, _, err = upsert(MySQL).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `t` (`id`,`n`) VALUES (?,?) ON DUPLICATE KEY UPDATE `id` = VALUES(`id`), `n` = VALUES(`n`)", sql)
+
+	sql, _, err = upsert(MySQL).OnDuplicateKeyUpdate(map[string]interface{}{"n": Expr("n + 1")}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `t` (`id`,`n`) VALUES (?,?) ON DUPLICATE KEY UPDATE n = n + 1", sql)
+
+	sql, _, err = upsert(YDB).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPSERT INTO `t` (`id`,`n`) VALUES ($p1,$p2)", sql)
+
+	_, _, err = upsert(Postgres).ToSql()
+	assert.EqualError(t, err, "insert builder: Upsert needs Columns and ConflictKeys on this database")
+
+	_, _, err = upsert(SQLServer).ToSql()
+	assert.EqualError(t, err, "insert builder: Upsert is not supported by this database; use Merge")
+}
@@ -0,0 +1,45 @@
+package squirrel
+
+import (
+	"errors"
+)
+
+// BuilderError is returned by ToSql when a builder can't render its query. It
+// records which builder and clause failed, so that errors from deeply
+// composed queries can be traced back, e.g.
+// "select builder: WHERE: cannot use null with like operators".
+//
+// Errors from subqueries are wrapped in turn:
+// "select builder: FROM: select builder: no result columns".
+type BuilderError struct {
+	// Builder is the kind of statement, e.g. "select" or "create table".
+	Builder string
+	// Clause is the clause that failed, e.g. "WHERE", or "" for errors about
+	// the statement as a whole.
+	Clause string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *BuilderError) Error() string {
+	msg := e.Builder + " builder: "
+	if e.Clause != "" {
+		msg += e.Clause + ": "
+	}
+	return msg + e.Err.Error()
+}
+
+// Unwrap returns the underlying error, for errors.Is and errors.As.
+func (e *BuilderError) Unwrap() error {
+	return e.Err
+}
+
+// builderError returns a BuilderError for a statement-level problem.
+func builderError(builder, msg string) error {
+	return &BuilderError{Builder: builder, Err: errors.New(msg)}
+}
+
+// clauseError wraps err, returned while rendering clause, in a BuilderError.
+func clauseError(builder, clause string, err error) error {
+	return &BuilderError{Builder: builder, Clause: clause, Err: err}
+}
@@ -0,0 +1,42 @@
+package squirrel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilderError(t *testing.T) {
+	_, _, err := Insert("").Values(1).ToSql()
+	assert.EqualError(t, err, "insert builder: no table")
+
+	var builderErr *BuilderError
+	assert.True(t, errors.As(err, &builderErr))
+	assert.Equal(t, "insert", builderErr.Builder)
+	assert.Equal(t, "", builderErr.Clause)
+}
+
+func TestBuilderErrorClause(t *testing.T) {
+	_, _, err := Select("*").From("t").Where(Like{"a": nil}).ToSql()
+	assert.EqualError(t, err, "select builder: WHERE: cannot use null with like operators")
+
+	var builderErr *BuilderError
+	assert.True(t, errors.As(err, &builderErr))
+	assert.Equal(t, "WHERE", builderErr.Clause)
+}
+
+func TestBuilderErrorNested(t *testing.T) {
+	sub := Select().From("u")
+	_, _, err := Update("t").Set("a", sub).ToSql()
+	assert.EqualError(t, err, "update builder: SET: select builder: no result columns")
+
+	_, _, err = Select("*").FromSelect(sub, "s").ToSql()
+	assert.EqualError(t, err, "select builder: FROM: select builder: no result columns")
+
+	var builderErr *BuilderError
+	assert.True(t, errors.As(err, &builderErr))
+	assert.Equal(t, "FROM", builderErr.Clause)
+	assert.True(t, errors.As(builderErr.Err, &builderErr))
+	assert.Equal(t, "", builderErr.Clause)
+}
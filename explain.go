@@ -0,0 +1,226 @@
+package squirrel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExplainOptions change the EXPLAIN statement written by Explain and
+// ExplainWith.
+type ExplainOptions struct {
+	// Analyze runs the query and reports its actual costs: EXPLAIN ANALYZE.
+	// Beware that it runs INSERT, UPDATE and DELETE statements too.
+	Analyze bool
+
+	// Format is the format of the plan, e.g. "JSON". It is left to the
+	// database if empty.
+	Format string
+}
+
+type explainData struct {
+	Query   Sqlizer
+	Options ExplainOptions
+	RunWith BaseRunner
+}
+
+func (d *explainData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if d.Query == nil {
+		err = builderError("explain", "no query")
+		return
+	}
+
+	var dialect dialectKind
+	if pf, ok := d.Query.(placeholderFormatter); ok {
+		dialect = dialectKindOf(pf.placeholderFormat())
+	}
+	prefix, err := explainSyntax(dialect, d.Options)
+	if err != nil {
+		return "", nil, builderError("explain", err.Error())
+	}
+
+	sqlStr, args, err = d.Query.ToSql()
+	if err != nil {
+		return
+	}
+	sqlStr = prefix + " " + sqlStr
+	return
+}
+
+// explainSyntax returns the EXPLAIN keywords written in front of the query
+// for dialect.
+func explainSyntax(dialect dialectKind, opts ExplainOptions) (string, error) {
+	format := strings.ToUpper(opts.Format)
+	if format != "" && !isIdent(format) {
+		return "", fmt.Errorf("invalid EXPLAIN format %q", opts.Format)
+	}
+
+	switch dialect {
+	case dialectMySQL:
+		if opts.Analyze && format != "" && format != "TREE" {
+			return "", fmt.Errorf("EXPLAIN ANALYZE only supports FORMAT=TREE on this database")
+		}
+		sql := "EXPLAIN"
+		if opts.Analyze {
+			sql += " ANALYZE"
+		}
+		if format != "" {
+			sql += " FORMAT=" + format
+		}
+		return sql, nil
+	case dialectSQLite:
+		if opts.Analyze || format != "" {
+			return "", fmt.Errorf("EXPLAIN options are not supported by this database")
+		}
+		return "EXPLAIN QUERY PLAN", nil
+	case dialectOracle:
+		if opts.Analyze || format != "" {
+			return "", fmt.Errorf("EXPLAIN options are not supported by this database")
+		}
+		return "EXPLAIN PLAN FOR", nil
+	case dialectSQLServer:
+		return "", fmt.Errorf("EXPLAIN is not supported by this database; use SET SHOWPLAN_XML ON")
+	case dialectYQL:
+		return "", fmt.Errorf("EXPLAIN is not supported by this database; use the explain query mode of the driver")
+	}
+
+	// PostgreSQL syntax, which most other databases accept in its short
+	// form.
+	var opt []string
+	if opts.Analyze {
+		opt = append(opt, "ANALYZE")
+	}
+	if format != "" {
+		opt = append(opt, "FORMAT "+format)
+	}
+	switch {
+	case len(opt) == 0:
+		return "EXPLAIN", nil
+	case format == "":
+		return "EXPLAIN ANALYZE", nil
+	default:
+		return "EXPLAIN (" + strings.Join(opt, ", ") + ")", nil
+	}
+}
+
+// ExplainBuilder builds an EXPLAIN statement for a query, in the syntax of
+// the database the query is written for (see Dialect):
+//     Explain(Select("*").From("users").Where(Eq{"id": 1}).PlaceholderFormat(Dollar)).Analyze()
+//     // EXPLAIN ANALYZE SELECT * FROM users WHERE id = $1
+//     Explain(StatementBuilder.Dialect(SQLite).Select("*").From("users"))
+//     // EXPLAIN QUERY PLAN SELECT * FROM "users"
+//     Explain(StatementBuilder.Dialect(Oracle).Select("*").From("users"))
+//     // EXPLAIN PLAN FOR SELECT * FROM "users"
+//
+// SQL Server and YDB have no EXPLAIN statement; ToSql returns an error for
+// them. With YDB, run the query in the explain query mode of its driver
+// instead.
+type ExplainBuilder struct {
+	data explainData
+}
+
+// Explain returns a new ExplainBuilder for query.
+func Explain(query Sqlizer) ExplainBuilder {
+	return ExplainBuilder{data: explainData{Query: query}}
+}
+
+// Analyze makes the statement run the query and report its actual costs.
+func (b ExplainBuilder) Analyze() ExplainBuilder {
+	b.data.Options.Analyze = true
+	return b
+}
+
+// Format sets the format of the plan, e.g. "JSON".
+func (b ExplainBuilder) Format(format string) ExplainBuilder {
+	b.data.Options.Format = format
+	return b
+}
+
+// Options sets all the options of the statement.
+func (b ExplainBuilder) Options(opts ExplainOptions) ExplainBuilder {
+	b.data.Options = opts
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Plan.
+func (b ExplainBuilder) RunWith(runner BaseRunner) ExplainBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// ToSql builds the statement into a SQL string and bound args.
+func (b ExplainBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the statement into a SQL string and bound args.
+// It panics if there are any errors.
+func (b ExplainBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Plan runs the statement with the Runner set by RunWith and returns the plan.
+// See ExplainWith.
+func (b ExplainBuilder) Plan() ([]string, error) {
+	if b.data.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	return explainPlan(b.data.RunWith, &b.data)
+}
+
+// ExplainWith Queries db with the EXPLAIN statement for s and returns the
+// plan, one string per row of the result with its columns separated by tabs.
+// A plan in a format like JSON comes back as a single row.
+//
+// Ex:
+//     plan, err := ExplainWith(db, Select("*").From("users").Where(Eq{"id": 1}),
+//         ExplainOptions{Analyze: true})
+//     fmt.Println(strings.Join(plan, "\n"))
+func ExplainWith(db Queryer, s Sqlizer, opts ExplainOptions) ([]string, error) {
+	return explainPlan(db, &explainData{Query: s, Options: opts})
+}
+
+func explainPlan(db Queryer, s Sqlizer) ([]string, error) {
+	rows, err := QueryWith(db, s)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	var plan []string
+	values := make([]interface{}, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	err = ForEachRow(rows, func(rows Rows) error {
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+		line := make([]string, len(values))
+		for i, v := range values {
+			switch v := v.(type) {
+			case nil:
+				line[i] = "NULL"
+			case []byte:
+				line[i] = string(v)
+			default:
+				line[i] = fmt.Sprint(v)
+			}
+		}
+		plan = append(plan, strings.Join(line, "\t"))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
@@ -0,0 +1,70 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainBuilderToSql(t *testing.T) {
+	q := Select("*").From("users").Where(Eq{"id": 1})
+
+	sql, args, err := Explain(q.PlaceholderFormat(Dollar)).Analyze().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN ANALYZE SELECT * FROM users WHERE id = $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = Explain(q.PlaceholderFormat(Dollar)).Analyze().Format("json").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN (ANALYZE, FORMAT JSON) SELECT * FROM users WHERE id = $1", sql)
+
+	sql, _, err = Explain(q).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN SELECT * FROM users WHERE id = ?", sql)
+
+	sql, _, err = Explain(q.PlaceholderFormat(MySQL.PlaceholderFormat())).Format("JSON").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "EXPLAIN FORMAT=JSON SELECT * FROM users WHERE id = ?", sql)
+
+	sql, _, err = Explain(StatementBuilder.Dialect(SQLite).Select("*").From("users")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `EXPLAIN QUERY PLAN SELECT * FROM "users"`, sql)
+
+	sql, _, err = Explain(StatementBuilder.Dialect(Oracle).Select("*").From("users")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `EXPLAIN PLAN FOR SELECT * FROM "users"`, sql)
+}
+
+func TestExplainBuilderToSqlErrors(t *testing.T) {
+	_, _, err := Explain(nil).ToSql()
+	assert.EqualError(t, err, "explain builder: no query")
+
+	_, _, err = Explain(Select("*").From("users")).Format("JSON; DROP TABLE users").ToSql()
+	assert.EqualError(t, err, `explain builder: invalid EXPLAIN format "JSON; DROP TABLE users"`)
+
+	_, _, err = Explain(StatementBuilder.Dialect(SQLite).Select("*").From("users")).Analyze().ToSql()
+	assert.EqualError(t, err, "explain builder: EXPLAIN options are not supported by this database")
+
+	_, _, err = Explain(Select("*").From("users").PlaceholderFormat(AtP)).ToSql()
+	assert.EqualError(t, err,
+		"explain builder: EXPLAIN is not supported by this database; use SET SHOWPLAN_XML ON")
+
+	_, _, err = Explain(Select("*").From("users").PlaceholderFormat(DollarP)).ToSql()
+	assert.Error(t, err)
+}
+
+func TestExplainWith(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	plan, err := ExplainWith(db, Select("n").From("numbers"), ExplainOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, plan)
+
+	plan, err = Explain(Select("n").From("numbers")).RunWith(db).Plan()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, plan)
+
+	_, err = Explain(Select("n").From("numbers")).Plan()
+	assert.Equal(t, RunnerNotSet, err)
+}
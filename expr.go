@@ -24,7 +24,20 @@ type expr struct {
 //
 // Ex:
 //     Expr("FROM_UNIXTIME(?)", t)
+//
+// If the only argument is a NamedArgs, the fragment uses ":name" parameters
+// instead of "?" placeholders:
+//     Expr("created BETWEEN :from AND :from + INTERVAL '1 day'", NamedArgs{"from": t})
+//
+// A parameter may be used any number of times; each use becomes a "?" with
+// its own copy of the arg. Colons in quoted strings and comments, and "::"
+// casts, are left as they are.
 func Expr(sql string, args ...interface{}) Sqlizer {
+	if len(args) == 1 {
+		if named, ok := args[0].(NamedArgs); ok {
+			return namedExpr{sql: sql, args: named}
+		}
+	}
 	return expr{sql: sql, args: args}
 }
 
@@ -61,7 +74,7 @@ func (e expr) ToSql() (sql string, args []interface{}, err error) {
 
 		if as, ok := ap[0].(Sqlizer); ok {
 			// sqlizer argument; expand it and append the result
-			isql, iargs, err = as.ToSql()
+			isql, iargs, err = nestedToSql(as)
 			buf.WriteString(sp[:i])
 			buf.WriteString(isql)
 			args = append(args, iargs...)
@@ -81,6 +94,121 @@ func (e expr) ToSql() (sql string, args []interface{}, err error) {
 	return buf.String(), append(args, ap...), err
 }
 
+// NamedArgs binds ":name" parameters of an Expr.
+//
+// The query is rendered with "?" placeholders and one arg per occurrence of
+// a parameter, so any PlaceholderFormat works. Use a NamedPlaceholders format to
+// pass them to the driver as database/sql.NamedArg instead, with a name used
+// more than once bound only once.
+type NamedArgs map[string]interface{}
+
+// namedArg is an arg bound by name with NamedArgs. It is replaced with its
+// value, or with a database/sql.NamedArg, when the query is rendered.
+type namedArg struct {
+	name  string
+	value interface{}
+}
+
+// Value implements driver.Valuer, so that args of an Expr rendered on its own
+// can still be passed to a driver positionally.
+func (a namedArg) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(a.value)
+}
+
+func (a namedArg) String() string {
+	return fmt.Sprint(a.value)
+}
+
+// positionalArgs replaces named args with their values.
+func positionalArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		if _, ok := arg.(namedArg); !ok {
+			continue
+		}
+		out := make([]interface{}, len(args))
+		copy(out, args[:i])
+		for j := i; j < len(args); j++ {
+			if a, ok := args[j].(namedArg); ok {
+				out[j] = a.value
+			} else {
+				out[j] = args[j]
+			}
+		}
+		return out
+	}
+	return args
+}
+
+type namedExpr struct {
+	sql  string
+	args NamedArgs
+}
+
+func (e namedExpr) ToSql() (sql string, args []interface{}, err error) {
+	buf := &bytes.Buffer{}
+	sp := e.sql
+	start := 0
+
+	for i := 0; i < len(sp); {
+		// parameters in quoted strings and comments are left alone
+		if end := (interpolator{}).skipQuoted(sp, i); end != i {
+			if end == -1 {
+				break
+			}
+			i = end
+			continue
+		}
+		if sp[i] != ':' || i == len(sp)-1 {
+			i++
+			continue
+		}
+		if sp[i+1] == ':' {
+			// "::" type cast; step past
+			i += 2
+			continue
+		}
+
+		j := i + 1
+		for j < len(sp) && isNameByte(sp[j], j == i+1) {
+			j++
+		}
+		if j == i+1 {
+			// lone colon
+			i++
+			continue
+		}
+
+		name := sp[i+1 : j]
+		value, ok := e.args[name]
+		if !ok {
+			return "", nil, fmt.Errorf("missing named arg %q", name)
+		}
+
+		buf.WriteString(sp[start:i])
+		if vs, ok := value.(Sqlizer); ok {
+			var vsql string
+			var vargs []interface{}
+			vsql, vargs, err = nestedToSql(vs)
+			if err != nil {
+				return "", nil, err
+			}
+			buf.WriteString(vsql)
+			args = append(args, vargs...)
+		} else {
+			buf.WriteString("?")
+			args = append(args, namedArg{name: name, value: value})
+		}
+		start, i = j, j
+	}
+
+	buf.WriteString(sp[start:])
+	return buf.String(), args, nil
+}
+
+func isNameByte(c byte, first bool) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || !first && '0' <= c && c <= '9'
+}
+
 type concatExpr []interface{}
 
 func (ce concatExpr) ToSql() (sql string, args []interface{}, err error) {
@@ -89,7 +217,7 @@ func (ce concatExpr) ToSql() (sql string, args []interface{}, err error) {
 		case string:
 			sql += p
 		case Sqlizer:
-			pSql, pArgs, err := p.ToSql()
+			pSql, pArgs, err := nestedToSql(p)
 			if err != nil {
 				return "", nil, err
 			}
@@ -121,18 +249,215 @@ type aliasExpr struct {
 // defined as complex expression like IF or CASE
 // Ex:
 //		.Column(Alias(caseStmt, "case_column"))
+//
+// expr is written in parentheses, followed by "AS alias", and its args are
+// kept, so Alias also names subqueries in SelectBuilder.FromExpr and
+// SelectBuilder.JoinExpr:
+//		.FromExpr(Alias(sub, "s")).JoinExpr(Alias(other, "o"), "o.id = s.id")
 func Alias(expr Sqlizer, alias string) aliasExpr {
 	return aliasExpr{expr, alias}
 }
 
 func (e aliasExpr) ToSql() (sql string, args []interface{}, err error) {
-	sql, args, err = e.expr.ToSql()
+	sql, args, err = nestedToSql(e.expr)
 	if err == nil {
 		sql = fmt.Sprintf("(%s) AS %s", sql, e.alias)
 	}
 	return
 }
 
+// cte is a single "alias AS (query)" entry of a WITH clause
+type cte struct {
+	alias string
+	query Sqlizer
+}
+
+func (c cte) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(c.query)
+	if err == nil {
+		sql = fmt.Sprintf("%s AS (%s)", c.alias, sql)
+	}
+	return
+}
+
+// setOp is a set operation like "UNION query" applied to a select
+type setOp struct {
+	op    string
+	query Sqlizer
+}
+
+func (s setOp) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(s.query)
+	if err == nil {
+		sql = fmt.Sprintf("%s %s", s.op, sql)
+	}
+	return
+}
+
+type existsExpr struct {
+	not   bool
+	query Sqlizer
+}
+
+// Exists wraps query, usually a SelectBuilder, in an EXISTS (...) predicate
+// for use with Where.
+//
+// Ex:
+//     Select("*").From("users u").Where(Exists(
+//         Select("1").From("orders o").Where("o.user_id = u.id")))
+//     // SELECT * FROM users u WHERE EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id)
+func Exists(query Sqlizer) Sqlizer {
+	return existsExpr{query: query}
+}
+
+// NotExists wraps query in a NOT EXISTS (...) predicate.
+func NotExists(query Sqlizer) Sqlizer {
+	return existsExpr{not: true, query: query}
+}
+
+func (e existsExpr) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(e.query)
+	if err != nil {
+		return
+	}
+	if e.not {
+		sql = fmt.Sprintf("NOT EXISTS (%s)", sql)
+	} else {
+		sql = fmt.Sprintf("EXISTS (%s)", sql)
+	}
+	return
+}
+
+type tuplesExpr struct {
+	columns []string
+	rows    [][]interface{}
+	not     bool
+}
+
+// InTuples is a composite key IN condition, e.g. for looking up rows by a
+// multi-column primary key.
+//
+// Ex:
+//     InTuples([]string{"a", "b"}, [][]interface{}{{1, "x"}, {2, "y"}})
+//     // (a,b) IN ((?,?),(?,?))
+//
+// Eq and NotEq produce the same condition for a key like "(a,b)" with a list
+// of rows as its value.
+func InTuples(columns []string, rows [][]interface{}) Sqlizer {
+	return tuplesExpr{columns: columns, rows: rows}
+}
+
+// NotInTuples is the NOT IN variant of InTuples.
+func NotInTuples(columns []string, rows [][]interface{}) Sqlizer {
+	return tuplesExpr{columns: columns, rows: rows, not: true}
+}
+
+func (t tuplesExpr) ToSql() (sql string, args []interface{}, err error) {
+	opr, emptyExpr := "IN", sqlFalse
+	if t.not {
+		opr, emptyExpr = "NOT IN", sqlTrue
+	}
+	for i, row := range t.rows {
+		if len(row) != len(t.columns) {
+			err = fmt.Errorf("tuple %d has %d values for %d columns", i, len(row), len(t.columns))
+			return
+		}
+	}
+	sql, args, err = tuplesToSql("("+strings.Join(t.columns, ",")+")", opr, reflect.ValueOf(t.rows))
+	if err == nil && sql == "" {
+		sql = emptyExpr
+	}
+	return
+}
+
+// tuplesToSql writes "key opr ((?,?),...)" with an arg for each value of
+// rows, a list of lists of the same length. It returns an empty sql for no
+// rows.
+func tuplesToSql(key, opr string, rows reflect.Value) (sql string, args []interface{}, err error) {
+	if rows.Len() == 0 {
+		return
+	}
+
+	tuples := make([]string, rows.Len())
+	width := -1
+	for i := range tuples {
+		row := reflect.ValueOf(rows.Index(i).Interface())
+		if !isListType(row.Interface()) {
+			err = fmt.Errorf("expected a list of values for tuple %d of %s, not %T", i, key, row.Interface())
+			return
+		}
+		if width == -1 {
+			width = row.Len()
+		} else if row.Len() != width {
+			err = fmt.Errorf("tuple %d of %s has %d values, expected %d", i, key, row.Len(), width)
+			return
+		}
+		for j := 0; j < row.Len(); j++ {
+			args = append(args, row.Index(j).Interface())
+		}
+		tuples[i] = "(" + Placeholders(width) + ")"
+	}
+	sql = fmt.Sprintf("%s %s (%s)", key, opr, strings.Join(tuples, ","))
+	return
+}
+
+// seekExpr is the keyset pagination condition of SeekAfter, i.e. "the row
+// comes after values in the order of columns"
+type seekExpr struct {
+	columns []string
+	desc    []bool
+	values  []interface{}
+}
+
+func (s seekExpr) ToSql() (sql string, args []interface{}, err error) {
+	if len(s.columns) != len(s.values) {
+		err = fmt.Errorf("seek after %d values on %d order columns", len(s.values), len(s.columns))
+		return
+	}
+	mixed := false
+	for _, desc := range s.desc {
+		mixed = mixed || desc != s.desc[0]
+	}
+	cmp := func(desc bool) string {
+		if desc {
+			return "<"
+		}
+		return ">"
+	}
+
+	if !mixed {
+		// (a, b) > (?, ?)
+		if len(s.columns) == 1 {
+			sql = fmt.Sprintf("%s %s ?", s.columns[0], cmp(s.desc[0]))
+		} else {
+			sql = fmt.Sprintf("(%s) %s (%s)",
+				strings.Join(s.columns, ", "), cmp(s.desc[0]), strings.Repeat(", ?", len(s.values))[2:])
+		}
+		args = s.values
+		return
+	}
+
+	// row value comparison only works in a single direction, so expand it to
+	// (a > ? OR (a = ? AND b < ?))
+	ors := make([]string, len(s.columns))
+	for i := range s.columns {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, s.columns[j]+" = ?")
+			args = append(args, s.values[j])
+		}
+		ands = append(ands, fmt.Sprintf("%s %s ?", s.columns[i], cmp(s.desc[i])))
+		args = append(args, s.values[i])
+		if len(ands) == 1 {
+			ors[i] = ands[0]
+		} else {
+			ors[i] = "(" + strings.Join(ands, " AND ") + ")"
+		}
+	}
+	sql = "(" + strings.Join(ors, " OR ") + ")"
+	return
+}
+
 // Eq is syntactic sugar for use with Where/Having/Set methods.
 type Eq map[string]interface{}
 
@@ -182,7 +507,21 @@ func (eq Eq) toSQL(useNotOpr bool) (sql string, args []interface{}, err error) {
 		if val == nil {
 			expr = fmt.Sprintf("%s %s NULL", key, nullOpr)
 		} else {
-			if isListType(val) {
+			if isTupleListType(val) {
+				// (a,b) IN ((?,?),(?,?))
+				var tupleArgs []interface{}
+				expr, tupleArgs, err = tuplesToSql(key, inOpr, reflect.ValueOf(val))
+				if err != nil {
+					return
+				}
+				if expr == "" {
+					expr = inEmptyExpr
+				}
+				args = append(args, tupleArgs...)
+				if args == nil {
+					args = []interface{}{}
+				}
+			} else if isListType(val) {
 				valVal := reflect.ValueOf(val)
 				if valVal.Len() == 0 {
 					expr = inEmptyExpr
@@ -268,6 +607,9 @@ func (nlk NotLike) ToSql() (sql string, args []interface{}, err error) {
 }
 
 // ILike is syntactic sugar for use with ILIKE conditions.
+//
+// In a query built with a Dialect other than Postgres, ILike is written as
+// "LOWER(col) LIKE LOWER(?)", since only PostgreSQL has ILIKE.
 // Ex:
 //    .Where(ILike{"name": "sq%"})
 type ILike Like
@@ -285,6 +627,212 @@ func (nilk NotILike) ToSql() (sql string, args []interface{}, err error) {
 	return Like(nilk).toSql("NOT ILIKE")
 }
 
+func (ilk ILike) forDialect(d dialectKind, explicit bool) Sqlizer {
+	if !explicit || d == dialectPostgres {
+		return ilk
+	}
+	return lowerLike{like: Like(ilk), opr: "LIKE"}
+}
+
+func (nilk NotILike) forDialect(d dialectKind, explicit bool) Sqlizer {
+	if !explicit || d == dialectPostgres {
+		return nilk
+	}
+	return lowerLike{like: Like(nilk), opr: "NOT LIKE"}
+}
+
+// lowerLike is the ILike fallback for databases without ILIKE, i.e.
+// "LOWER(col) LIKE LOWER(?)"
+type lowerLike struct {
+	like Like
+	opr  string
+}
+
+func (ll lowerLike) ToSql() (sql string, args []interface{}, err error) {
+	lowered := make(Like, len(ll.like))
+	for key, val := range ll.like {
+		lowered["LOWER("+key+")"] = val
+	}
+	sql, args, err = lowered.toSql(ll.opr)
+	if err == nil {
+		sql = strings.Replace(sql, ll.opr+" ?", ll.opr+" LOWER(?)", -1)
+	}
+	return
+}
+
+// dialectSqlizer is implemented by expressions that are written differently
+// for different databases, like ILike. explicit tells whether d was set with
+// a Dialect rather than inferred from the placeholder format.
+type dialectSqlizer interface {
+	forDialect(d dialectKind, explicit bool) Sqlizer
+}
+
+// forDialect returns parts with the dialectSqlizers among them, including
+// those nested in conditions and subqueries, written for the database d. It returns
+// parts itself if there are none.
+//
+// Builders do this in ToSql, with d inferred from the placeholder format.
+func forDialect(parts []Sqlizer, d dialectKind, explicit bool) []Sqlizer {
+	rewritten, _ := partsForDialect(parts, d, explicit)
+	return rewritten
+}
+
+func partsForDialect(parts []Sqlizer, d dialectKind, explicit bool) ([]Sqlizer, bool) {
+	var rewritten []Sqlizer
+	for i, p := range parts {
+		rp, ok := partForDialect(p, d, explicit)
+		if !ok {
+			if rewritten != nil {
+				rewritten[i] = p
+			}
+			continue
+		}
+		if rewritten == nil {
+			rewritten = make([]Sqlizer, len(parts))
+			copy(rewritten, parts[:i])
+		}
+		rewritten[i] = rp
+	}
+	if rewritten == nil {
+		return parts, false
+	}
+	return rewritten, true
+}
+
+// argsForDialect is partsForDialect for args, of which only the Sqlizers are
+// rewritten.
+func argsForDialect(args []interface{}, d dialectKind, explicit bool) ([]interface{}, bool) {
+	var rewritten []interface{}
+	for i, a := range args {
+		ra, ok := argForDialect(a, d, explicit)
+		if !ok {
+			if rewritten != nil {
+				rewritten[i] = a
+			}
+			continue
+		}
+		if rewritten == nil {
+			rewritten = make([]interface{}, len(args))
+			copy(rewritten, args[:i])
+		}
+		rewritten[i] = ra
+	}
+	if rewritten == nil {
+		return args, false
+	}
+	return rewritten, true
+}
+
+func argForDialect(a interface{}, d dialectKind, explicit bool) (interface{}, bool) {
+	if s, ok := a.(Sqlizer); ok {
+		return partForDialect(s, d, explicit)
+	}
+	return a, false
+}
+
+// partForDialect returns s written for the database d, and whether that
+// differs from s. It walks into every expression that holds other
+// Sqlizers, so that e.g. an ILike in a JOIN condition or a CTE is rewritten
+// too.
+func partForDialect(s Sqlizer, d dialectKind, explicit bool) (Sqlizer, bool) {
+	switch p := s.(type) {
+	case dialectSqlizer:
+		return p.forDialect(d, explicit), true
+	case And:
+		if rewritten, ok := partsForDialect(p, d, explicit); ok {
+			return And(rewritten), true
+		}
+	case Or:
+		if rewritten, ok := partsForDialect(p, d, explicit); ok {
+			return Or(rewritten), true
+		}
+	case expr:
+		if args, ok := argsForDialect(p.args, d, explicit); ok {
+			return expr{sql: p.sql, args: args}, true
+		}
+	case namedExpr:
+		var args NamedArgs
+		for name, a := range p.args {
+			if ra, ok := argForDialect(a, d, explicit); ok {
+				if args == nil {
+					args = make(NamedArgs, len(p.args))
+					for name, a := range p.args {
+						args[name] = a
+					}
+				}
+				args[name] = ra
+			}
+		}
+		if args != nil {
+			return namedExpr{sql: p.sql, args: args}, true
+		}
+	case concatExpr:
+		if rewritten, ok := argsForDialect(p, d, explicit); ok {
+			return concatExpr(rewritten), true
+		}
+	case arrayExpr:
+		if rewritten, ok := argsForDialect(p, d, explicit); ok {
+			return arrayExpr(rewritten), true
+		}
+	case aliasExpr:
+		if expr, ok := partForDialect(p.expr, d, explicit); ok {
+			return aliasExpr{expr: expr, alias: p.alias}, true
+		}
+	case cte:
+		if query, ok := partForDialect(p.query, d, explicit); ok {
+			return cte{alias: p.alias, query: query}, true
+		}
+	case existsExpr:
+		if query, ok := partForDialect(p.query, d, explicit); ok {
+			return existsExpr{not: p.not, query: query}, true
+		}
+	case setOp:
+		if query, ok := partForDialect(p.query, d, explicit); ok {
+			return setOp{op: p.op, query: query}, true
+		}
+	case yqlNamedExpr:
+		if expr, ok := partForDialect(p.expr, d, explicit); ok {
+			return yqlNamedExpr{name: p.name, expr: expr, terminated: p.terminated}, true
+		}
+	case notExpr:
+		if cond, ok := partForDialect(p.cond, d, explicit); ok {
+			return notExpr{cond: cond}, true
+		}
+	case funcExpr:
+		if args, ok := argsForDialect(p.args, d, explicit); ok {
+			p.args = args
+			return p, true
+		}
+	case funcCond:
+		f, fok := partForDialect(p.f, d, explicit)
+		value, vok := argForDialect(p.value, d, explicit)
+		if fok || vok {
+			return funcCond{f: f.(funcExpr), opr: p.opr, value: value}, true
+		}
+	case overExpr:
+		if fn, ok := partForDialect(p.fn, d, explicit); ok {
+			return overExpr{fn: fn, window: p.window}, true
+		}
+	case CaseBuilder:
+		if data, ok := p.data.forDialect(d, explicit); ok {
+			return CaseBuilder{data: data}, true
+		}
+	case *wherePart:
+		if pred, ok := p.pred.(Sqlizer); ok {
+			if rp, ok := partForDialect(pred, d, explicit); ok {
+				return &wherePart{pred: rp, args: p.args}, true
+			}
+		}
+	case *part:
+		if pred, ok := p.pred.(Sqlizer); ok {
+			if rp, ok := partForDialect(pred, d, explicit); ok {
+				return &part{pred: rp, args: p.args}, true
+			}
+		}
+	}
+	return s, false
+}
+
 // Lt is syntactic sugar for use with Where/Having/Set methods.
 // Ex:
 //     .Where(Lt{"id": 1})
@@ -364,6 +912,156 @@ func (gtOrEq GtOrEq) ToSql() (sql string, args []interface{}, err error) {
 	return Lt(gtOrEq).toSql(true, true)
 }
 
+// Between is syntactic sugar for use with BETWEEN conditions. Each value is
+// a list of the low and the high bound, e.g. a [2]interface{}.
+// Ex:
+//     .Where(Between{"age": [2]interface{}{18, 65}})
+//     // age BETWEEN ? AND ?
+type Between map[string]interface{}
+
+func (bt Between) toSql(opr string) (sql string, args []interface{}, err error) {
+	var exprs []string
+	for _, key := range getSortedKeys(bt) {
+		val := bt[key]
+		if !isListType(val) || reflect.ValueOf(val).Len() != 2 {
+			err = fmt.Errorf("expected low and high bounds for %s, not %#v", key, val)
+			return
+		}
+		bounds := reflect.ValueOf(val)
+		for i := 0; i < 2; i++ {
+			bound := bounds.Index(i).Interface()
+			if bound == nil {
+				err = fmt.Errorf("cannot use null with between operators")
+				return
+			}
+			args = append(args, bound)
+		}
+		exprs = append(exprs, fmt.Sprintf("%s %s ? AND ?", key, opr))
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
+func (bt Between) ToSql() (sql string, args []interface{}, err error) {
+	return bt.toSql("BETWEEN")
+}
+
+// NotBetween is syntactic sugar for use with NOT BETWEEN conditions.
+// Ex:
+//     .Where(NotBetween{"age": [2]interface{}{18, 65}})
+type NotBetween Between
+
+func (nbt NotBetween) ToSql() (sql string, args []interface{}, err error) {
+	return Between(nbt).toSql("NOT BETWEEN")
+}
+
+type arrayExpr []interface{}
+
+// Array is a PostgreSQL array constructor with an arg for each value, e.g.
+// ARRAY[?,?,?]. Sqlizer values are written in place.
+// Ex:
+//     Insert("posts").Columns("tags").Values(Array("go", "sql"))
+func Array(values ...interface{}) Sqlizer {
+	return arrayExpr(values)
+}
+
+func (a arrayExpr) ToSql() (sql string, args []interface{}, err error) {
+	if len(a) == 0 {
+		// ARRAY[] needs a cast; the literal takes the type of the column
+		sql = "'{}'"
+		return
+	}
+
+	elems := make([]string, len(a))
+	for i, v := range a {
+		if s, ok := v.(Sqlizer); ok {
+			var elemArgs []interface{}
+			elems[i], elemArgs, err = nestedToSql(s)
+			if err != nil {
+				return
+			}
+			args = append(args, elemArgs...)
+		} else {
+			elems[i] = "?"
+			args = append(args, v)
+		}
+	}
+	sql = "ARRAY[" + strings.Join(elems, ",") + "]"
+	return
+}
+
+// ArrayContains is syntactic sugar for use with the PostgreSQL array @>
+// operator: the column contains all of the values.
+//
+// A slice or array value is written with Array; other values, e.g. a
+// driver.Valuer like pq.Array, are bound as a single arg.
+// Ex:
+//     .Where(ArrayContains{"tags": []string{"go", "sql"}})
+//     // tags @> ARRAY[?,?]
+type ArrayContains map[string]interface{}
+
+func (ac ArrayContains) ToSql() (sql string, args []interface{}, err error) {
+	return arrayOpToSql(ac, "@>")
+}
+
+// ArrayContainedBy is syntactic sugar for use with the PostgreSQL array <@
+// operator: all the elements of the column are among the values.
+// Ex:
+//     .Where(ArrayContainedBy{"tags": []string{"go", "sql"}})
+type ArrayContainedBy map[string]interface{}
+
+func (acb ArrayContainedBy) ToSql() (sql string, args []interface{}, err error) {
+	return arrayOpToSql(acb, "<@")
+}
+
+// ArrayOverlap is syntactic sugar for use with the PostgreSQL array &&
+// operator: the column and the values have an element in common.
+// Ex:
+//     .Where(ArrayOverlap{"tags": []string{"go", "sql"}})
+type ArrayOverlap map[string]interface{}
+
+func (ao ArrayOverlap) ToSql() (sql string, args []interface{}, err error) {
+	return arrayOpToSql(ao, "&&")
+}
+
+func arrayOpToSql(m map[string]interface{}, opr string) (sql string, args []interface{}, err error) {
+	var exprs []string
+	for _, key := range getSortedKeys(m) {
+		val := m[key]
+		if val == nil {
+			err = fmt.Errorf("cannot use null with array operators")
+			return
+		}
+
+		var rhs Sqlizer
+		switch v := val.(type) {
+		case Sqlizer:
+			rhs = v
+		default:
+			if isListType(val) {
+				valVal := reflect.ValueOf(val)
+				values := make([]interface{}, valVal.Len())
+				for i := range values {
+					values[i] = valVal.Index(i).Interface()
+				}
+				rhs = Array(values...)
+			} else {
+				rhs = Expr("?", val)
+			}
+		}
+
+		rhsSql, rhsArgs, rhsErr := nestedToSql(rhs)
+		if rhsErr != nil {
+			err = rhsErr
+			return
+		}
+		exprs = append(exprs, fmt.Sprintf("%s %s %s", key, opr, rhsSql))
+		args = append(args, rhsArgs...)
+	}
+	sql = strings.Join(exprs, " AND ")
+	return
+}
+
 type conj []Sqlizer
 
 func (c conj) join(sep, defaultExpr string) (sql string, args []interface{}, err error) {
@@ -410,6 +1108,21 @@ func getSortedKeys(exp map[string]interface{}) []string {
 	return sortedKeys
 }
 
+// isTupleListType tells whether val is a list of lists, e.g. the
+// [][]interface{} rows of a composite key.
+func isTupleListType(val interface{}) bool {
+	if !isListType(val) {
+		return false
+	}
+	elemType := reflect.TypeOf(val).Elem()
+	if elemType.Kind() == reflect.Interface {
+		v := reflect.ValueOf(val)
+		return v.Len() > 0 && isListType(v.Index(0).Interface())
+	}
+	return (elemType.Kind() == reflect.Array || elemType.Kind() == reflect.Slice) &&
+		elemType.Elem().Kind() != reflect.Uint8
+}
+
 func isListType(val interface{}) bool {
 	if driver.IsValue(val) {
 		return false
@@ -7,6 +7,55 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestNamedExpr(t *testing.T) {
+	sqlStr, args, err := Expr("a = :x AND b::text = :y_2 AND c = :x AND d = ':'", NamedArgs{"x": 1, "y_2": "s"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a = ? AND b::text = ? AND c = ? AND d = ':'", sqlStr)
+	assert.Equal(t, []interface{}{
+		namedArg{name: "x", value: 1},
+		namedArg{name: "y_2", value: "s"},
+		namedArg{name: "x", value: 1},
+	}, args)
+
+	v, err := args[0].(namedArg).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), v)
+}
+
+func TestNamedExprRepeatedAndQuoted(t *testing.T) {
+	sqlStr, args, err := Expr(
+		"price BETWEEN :min AND :max AND discount < :min AND t > '10:30' AND note <> 'it''s :min' /* :max */",
+		NamedArgs{"min": 5, "max": 10},
+	).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "price BETWEEN ? AND ? AND discount < ? AND t > '10:30' AND note <> 'it''s :min' /* :max */", sqlStr)
+	assert.Equal(t, []interface{}{
+		namedArg{name: "min", value: 5},
+		namedArg{name: "max", value: 10},
+		namedArg{name: "min", value: 5},
+	}, args)
+
+	sqlStr, args, err = Select("*").From("products").
+		Where(Expr("price BETWEEN :min AND :max AND discount < :min", NamedArgs{"min": 5, "max": 10})).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM products WHERE price BETWEEN $1 AND $2 AND discount < $3", sqlStr)
+	assert.Equal(t, []interface{}{5, 10, 5}, args)
+}
+
+func TestNamedExprSqlizer(t *testing.T) {
+	sqlStr, args, err := Expr("a IN (:sub)", NamedArgs{"sub": Select("id").From("t").Where(Eq{"b": 2})}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a IN (SELECT id FROM t WHERE b = ?)", sqlStr)
+	assert.Equal(t, []interface{}{2}, args)
+}
+
+func TestNamedExprMissing(t *testing.T) {
+	_, _, err := Expr("a = :x", NamedArgs{"y": 1}).ToSql()
+	assert.EqualError(t, err, `missing named arg "x"`)
+}
+
 func TestConcatExpr(t *testing.T) {
 	b := ConcatExpr("COALESCE(name,", Expr("CONCAT(?,' ',?)", "f", "l"), ")")
 	sql, args, err := b.ToSql()
@@ -462,3 +511,211 @@ func ExampleEq() {
 		"company": 20,
 	})
 }
+
+func TestExprNestedDollarP(t *testing.T) {
+	sub := Select("a").From("b").Where("c = ?", 1).PlaceholderFormat(DollarP)
+	sql, args, err := Select("*").
+		Where(Expr("x = ? AND y IN (?)", 2, sub)).
+		Column(Alias(sub, "s")).
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT *, (SELECT a FROM b WHERE c = $p1) AS s " +
+		"WHERE x = $p2 AND y IN (SELECT a FROM b WHERE c = $p3)"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 1}, args)
+}
+
+func TestExists(t *testing.T) {
+	orders := Select("1").From("orders o").Where("o.user_id = u.id AND o.total > ?", 100)
+
+	sql, args, err := Select("*").
+		From("users u").
+		Where(Eq{"u.active": true}).
+		Where(Exists(orders)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users u WHERE u.active = $1 AND "+
+		"EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.total > $2)", sql)
+	assert.Equal(t, []interface{}{true, 100}, args)
+
+	sql, args, err = NotExists(orders).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.total > ?)", sql)
+	assert.Equal(t, []interface{}{100}, args)
+
+	_, _, err = Exists(Select()).ToSql()
+	assert.Error(t, err)
+}
+
+func TestInTuples(t *testing.T) {
+	rows := [][]interface{}{{1, "x"}, {2, "y"}}
+
+	sql, args, err := InTuples([]string{"a", "b"}, rows).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a,b) IN ((?,?),(?,?))", sql)
+	assert.Equal(t, []interface{}{1, "x", 2, "y"}, args)
+
+	sql, args, err = NotInTuples([]string{"a", "b"}, rows).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a,b) NOT IN ((?,?),(?,?))", sql)
+	assert.Equal(t, []interface{}{1, "x", 2, "y"}, args)
+
+	sql, _, err = InTuples([]string{"a", "b"}, nil).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sqlFalse, sql)
+
+	_, _, err = InTuples([]string{"a", "b"}, [][]interface{}{{1}}).ToSql()
+	assert.Error(t, err)
+}
+
+func TestEqTuples(t *testing.T) {
+	sql, args, err := Eq{"(a,b)": [][]interface{}{{1, "x"}, {2, "y"}}, "c": 3}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a,b) IN ((?,?),(?,?)) AND c = ?", sql)
+	assert.Equal(t, []interface{}{1, "x", 2, "y", 3}, args)
+
+	sql, args, err = NotEq{"(a,b)": [][2]int{{1, 2}}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(a,b) NOT IN ((?,?))", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = Eq{"(a,b)": [][]interface{}{}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, sqlFalse, sql)
+
+	_, _, err = Eq{"(a,b)": []interface{}{[]int{1, 2}, []int{3}}}.ToSql()
+	assert.Error(t, err)
+
+	// [][]byte is a list of values, not of tuples
+	sql, _, err = Eq{"a": [][]byte{[]byte("x")}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "a IN (?)", sql)
+}
+
+func TestBetween(t *testing.T) {
+	sql, args, err := Between{"age": [2]interface{}{18, 65}, "created": []string{"a", "z"}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "age BETWEEN ? AND ? AND created BETWEEN ? AND ?", sql)
+	assert.Equal(t, []interface{}{18, 65, "a", "z"}, args)
+
+	sql, args, err = NotBetween{"age": [2]int{18, 65}}.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "age NOT BETWEEN ? AND ?", sql)
+	assert.Equal(t, []interface{}{18, 65}, args)
+
+	_, _, err = Between{"age": 18}.ToSql()
+	assert.Error(t, err)
+	_, _, err = Between{"age": []int{1, 2, 3}}.ToSql()
+	assert.Error(t, err)
+	_, _, err = Between{"age": [2]interface{}{nil, 2}}.ToSql()
+	assert.Error(t, err)
+}
+
+func TestILikeFallback(t *testing.T) {
+	b := Select("*").From("users").
+		Where(ILike{"name": "sq%"}).
+		Where(Or{NotILike{"email": "%@example.com"}, Eq{"admin": true}})
+
+	// without a Dialect, ILIKE is left as written
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE name ILIKE ? AND (email NOT ILIKE ? OR admin = ?)", sql)
+	assert.Equal(t, []interface{}{"sq%", "%@example.com", true}, args)
+
+	sql, _, err = b.PlaceholderFormat(DollarP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE name ILIKE $p1 AND (email NOT ILIKE $p2 OR admin = $p3)", sql)
+
+	sql, args, err = b.PlaceholderFormat(MySQL.PlaceholderFormat()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE LOWER(name) LIKE LOWER(?) AND "+
+		"(LOWER(email) NOT LIKE LOWER(?) OR admin = ?)", sql)
+	assert.Equal(t, []interface{}{"sq%", "%@example.com", true}, args)
+
+	sql, _, err = b.PlaceholderFormat(Postgres.PlaceholderFormat()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE name ILIKE $1 AND (email NOT ILIKE $2 OR admin = $3)", sql)
+
+	sql, _, err = StatementBuilder.Dialect(SQLite).QuoteIdentifiers(nil).
+		Update("users").Set("a", 1).Where(ILike{"name": "sq%"}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET a = ? WHERE LOWER(name) LIKE LOWER(?)", sql)
+
+	sql, _, err = Delete("users").Where(ILike{"name": "sq%"}).PlaceholderFormat(YDB.PlaceholderFormat()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE LOWER(name) LIKE LOWER($p1)", sql)
+}
+
+func TestILikeFallbackNested(t *testing.T) {
+	sb := StatementBuilder.Dialect(MySQL).QuoteIdentifiers(nil)
+	name := ILike{"u.name": "sq%"}
+
+	sql, args, err := sb.Select("*").From("orders o").
+		JoinExpr(Alias(sb.Select("id", "name").From("users"), "u"), "u.id = o.user_id AND ?", name).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM orders o JOIN (SELECT id, name FROM users) AS u "+
+		"ON u.id = o.user_id AND LOWER(u.name) LIKE LOWER(?)", sql)
+	assert.Equal(t, []interface{}{"sq%"}, args)
+
+	sql, _, err = sb.Select("*").
+		With("u", sb.Select("id").From("users").Where(Or{name, Eq{"admin": true}})).
+		From("u").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "WITH u AS (SELECT id FROM users WHERE (LOWER(u.name) LIKE LOWER(?) OR admin = ?)) "+
+		"SELECT * FROM u", sql)
+
+	sql, _, err = sb.Select().
+		Column(Alias(Case().When(name, "1").Else("0"), "m")).
+		From("users u").
+		Where(notExpr{Expr("? OR ?", name, Eq{"admin": true})}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT (CASE WHEN LOWER(u.name) LIKE LOWER(?) THEN 1 ELSE 0 END) AS m FROM users u "+
+		"WHERE NOT (LOWER(u.name) LIKE LOWER(?) OR admin = ?)", sql)
+
+	sql, _, err = sb.Update("users u").
+		Set("flagged", Case().When(name, "1").Else("0")).
+		Suffix("RETURNING ?", NotILike{"u.name": "x%"}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users u SET flagged = CASE WHEN LOWER(u.name) LIKE LOWER(?) THEN 1 ELSE 0 END "+
+		"RETURNING LOWER(u.name) NOT LIKE LOWER(?)", sql)
+}
+
+func TestArray(t *testing.T) {
+	sql, args, err := Array("go", Expr("lower(?)", "SQL"), 3).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ARRAY[?,lower(?),?]", sql)
+	assert.Equal(t, []interface{}{"go", "SQL", 3}, args)
+
+	sql, args, err = Array().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "'{}'", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = Insert("posts").Columns("tags").Values(Array("a", "b")).PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO posts (tags) VALUES (ARRAY[$1,$2])", sql)
+	assert.Equal(t, []interface{}{"a", "b"}, args)
+}
+
+func TestArrayOperators(t *testing.T) {
+	sqlStr, args, err := Select("id").From("posts").
+		Where(ArrayContains{"tags": []string{"go", "sql"}}).
+		Where(ArrayContainedBy{"langs": Array("en", "de")}).
+		Where(ArrayOverlap{"ids": []int{1}, "owners": sql.NullString{String: "{7}", Valid: true}}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM posts WHERE tags @> ARRAY[$1,$2] AND langs <@ ARRAY[$3,$4] "+
+		"AND ids && ARRAY[$5] AND owners && $6", sqlStr)
+	assert.Equal(t, []interface{}{"go", "sql", "en", "de", 1, sql.NullString{String: "{7}", Valid: true}}, args)
+
+	_, _, err = ArrayContains{"tags": nil}.ToSql()
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package squirrel
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Filter is a declarative condition, e.g. decoded from the JSON filter of a
+// search API. Exactly one of And, Or, Not and Field is set:
+//     {"or": [
+//         {"field": "status", "op": "eq", "value": "open"},
+//         {"and": [
+//             {"field": "age", "op": "gte", "value": 18},
+//             {"not": {"field": "name", "op": "like", "value": "test%"}}
+//         ]}
+//     ]}
+//
+// A Filter is turned into a Sqlizer by FilterSchema.Build, which only lets
+// through the fields and operators of the schema.
+type Filter struct {
+	And []Filter `json:"and,omitempty"`
+	Or  []Filter `json:"or,omitempty"`
+	Not *Filter  `json:"not,omitempty"`
+
+	Field string      `json:"field,omitempty"`
+	Op    string      `json:"op,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// FilterOp builds the condition of an operator for expr, the SQL of a field,
+// and the value of the Filter.
+type FilterOp func(expr string, value interface{}) (Sqlizer, error)
+
+// DefaultFilterOps are the operators of a FilterSchema without Ops:
+//     eq, ne             expr = value, expr <> value (IS [NOT] NULL for null)
+//     lt, lte, gt, gte   expr < value, ...
+//     in, nin            expr [NOT] IN (values...), value is a list
+//     like, nlike        expr [NOT] LIKE value, value is a string
+//     between            expr BETWEEN low AND high, value is [low, high]
+var DefaultFilterOps = map[string]FilterOp{
+	"eq":      scalarFilterOp(func(expr string, v interface{}) Sqlizer { return Eq{expr: v} }),
+	"ne":      scalarFilterOp(func(expr string, v interface{}) Sqlizer { return NotEq{expr: v} }),
+	"lt":      scalarFilterOp(func(expr string, v interface{}) Sqlizer { return Lt{expr: v} }),
+	"lte":     scalarFilterOp(func(expr string, v interface{}) Sqlizer { return LtOrEq{expr: v} }),
+	"gt":      scalarFilterOp(func(expr string, v interface{}) Sqlizer { return Gt{expr: v} }),
+	"gte":     scalarFilterOp(func(expr string, v interface{}) Sqlizer { return GtOrEq{expr: v} }),
+	"in":      listFilterOp(func(expr string, v interface{}) Sqlizer { return Eq{expr: v} }),
+	"nin":     listFilterOp(func(expr string, v interface{}) Sqlizer { return NotEq{expr: v} }),
+	"like":    stringFilterOp(func(expr string, v interface{}) Sqlizer { return Like{expr: v} }),
+	"nlike":   stringFilterOp(func(expr string, v interface{}) Sqlizer { return NotLike{expr: v} }),
+	"between": func(expr string, v interface{}) (Sqlizer, error) { return Between{expr: v}, nil },
+}
+
+func scalarFilterOp(build func(expr string, value interface{}) Sqlizer) FilterOp {
+	return func(expr string, value interface{}) (Sqlizer, error) {
+		if isListType(value) {
+			return nil, fmt.Errorf("expected a single value, not %#v", value)
+		}
+		return build(expr, value), nil
+	}
+}
+
+func listFilterOp(build func(expr string, value interface{}) Sqlizer) FilterOp {
+	return func(expr string, value interface{}) (Sqlizer, error) {
+		if !isListType(value) {
+			return nil, fmt.Errorf("expected a list of values, not %#v", value)
+		}
+		return build(expr, value), nil
+	}
+}
+
+func stringFilterOp(build func(expr string, value interface{}) Sqlizer) FilterOp {
+	return func(expr string, value interface{}) (Sqlizer, error) {
+		if _, ok := value.(string); !ok {
+			return nil, fmt.Errorf("expected a string, not %#v", value)
+		}
+		return build(expr, value), nil
+	}
+}
+
+// FilterSchema turns Filters into Sqlizers, allowing only the fields and
+// operators it lists:
+//     schema := FilterSchema{Fields: map[string]string{
+//         "status": "t.status",
+//         "age":    "u.age",
+//     }}
+//     var f Filter
+//     if err := json.NewDecoder(r.Body).Decode(&f); err != nil { ... }
+//     cond, err := schema.Build(f)
+//     if err != nil { ... } // unknown field, operator or bad value
+//     Select("*").From("users u").Join("tickets t ON t.user_id = u.id").Where(cond)
+//
+// Values are always bound as args, and field names never reach the SQL, so
+// the result is safe to run whatever the Filter holds.
+type FilterSchema struct {
+	// Fields maps the field names a Filter may use to their SQL, e.g. a
+	// column name.
+	Fields map[string]string
+
+	// Ops maps the operator names a Filter may use to their conditions. It is
+	// DefaultFilterOps if nil.
+	Ops map[string]FilterOp
+
+	// MaxDepth is the deepest nesting of And, Or and Not allowed, 8 if zero.
+	MaxDepth int
+}
+
+// Build returns the condition of f. The errors tell the path of the invalid
+// part of f, e.g. `filter or[1].and[0]: unknown field "agee"`.
+func (s FilterSchema) Build(f Filter) (Sqlizer, error) {
+	maxDepth := s.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 8
+	}
+	ops := s.Ops
+	if ops == nil {
+		ops = DefaultFilterOps
+	}
+	return s.build(f, ops, "", maxDepth)
+}
+
+func (s FilterSchema) build(f Filter, ops map[string]FilterOp, path string, depth int) (Sqlizer, error) {
+	fail := func(format string, a ...interface{}) error {
+		msg := fmt.Sprintf(format, a...)
+		if path == "" {
+			return fmt.Errorf("filter: %s", msg)
+		}
+		return fmt.Errorf("filter %s: %s", path, msg)
+	}
+
+	set := 0
+	for _, ok := range []bool{f.And != nil, f.Or != nil, f.Not != nil, f.Field != ""} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fail("expected exactly one of and, or, not and field")
+	}
+	if (f.And != nil || f.Or != nil || f.Not != nil) && depth == 0 {
+		return nil, fail("nested too deep")
+	}
+
+	switch {
+	case f.And != nil:
+		conds, err := s.buildAll(f.And, ops, path, "and", depth-1)
+		return And(conds), err
+	case f.Or != nil:
+		conds, err := s.buildAll(f.Or, ops, path, "or", depth-1)
+		return Or(conds), err
+	case f.Not != nil:
+		cond, err := s.build(*f.Not, ops, subFilterPath(path, "not"), depth-1)
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{cond}, nil
+	}
+
+	expr, ok := s.Fields[f.Field]
+	if !ok {
+		return nil, fail("unknown field %q", f.Field)
+	}
+	op, ok := ops[f.Op]
+	if !ok {
+		return nil, fail("unknown operator %q", f.Op)
+	}
+	cond, err := op(expr, f.Value)
+	if err != nil {
+		return nil, fail("%s %s: %v", f.Field, f.Op, err)
+	}
+	// render it once, so that bad values fail here rather than in ToSql
+	if _, _, err := cond.ToSql(); err != nil {
+		return nil, fail("%s %s: %v", f.Field, f.Op, err)
+	}
+	return cond, nil
+}
+
+func (s FilterSchema) buildAll(filters []Filter, ops map[string]FilterOp, path, name string, depth int) ([]Sqlizer, error) {
+	conds := make([]Sqlizer, len(filters))
+	for i, f := range filters {
+		cond, err := s.build(f, ops, subFilterPath(path, name+"["+strconv.Itoa(i)+"]"), depth)
+		if err != nil {
+			return nil, err
+		}
+		conds[i] = cond
+	}
+	return conds, nil
+}
+
+func subFilterPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// notExpr negates a condition: NOT (cond)
+type notExpr struct {
+	cond Sqlizer
+}
+
+func (n notExpr) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = nestedToSql(n.cond)
+	if err == nil {
+		sql = "NOT (" + sql + ")"
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package squirrel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFilterSchema = FilterSchema{Fields: map[string]string{
+	"status": "t.status",
+	"age":    "u.age",
+	"name":   "u.name",
+}}
+
+func TestFilterSchemaBuild(t *testing.T) {
+	var f Filter
+	err := json.Unmarshal([]byte(`{"or": [
+		{"field": "status", "op": "in", "value": ["open", "new"]},
+		{"and": [
+			{"field": "age", "op": "between", "value": [18, 65]},
+			{"not": {"field": "name", "op": "like", "value": "test%"}},
+			{"field": "status", "op": "ne", "value": null}
+		]}
+	]}`), &f)
+	assert.NoError(t, err)
+
+	cond, err := testFilterSchema.Build(f)
+	assert.NoError(t, err)
+
+	sql, args, err := Select("*").From("users u").Where(cond).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users u WHERE (t.status IN (?,?) OR "+
+		"(u.age BETWEEN ? AND ? AND NOT (u.name LIKE ?) AND t.status IS NOT NULL))", sql)
+	assert.Equal(t, []interface{}{"open", "new", float64(18), float64(65), "test%"}, args)
+}
+
+func TestFilterSchemaBuildErrors(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    string
+	}{
+		{`{}`, "filter: expected exactly one of and, or, not and field"},
+		{`{"field": "age", "op": "eq", "value": 1, "or": []}`, "filter: expected exactly one of and, or, not and field"},
+		{`{"field": "password", "op": "eq", "value": "x"}`, `filter: unknown field "password"`},
+		{`{"or": [{"field": "age", "op": "eq", "value": 1}, {"and": [{"field": "age", "op": "~", "value": 1}]}]}`,
+			`filter or[1].and[0]: unknown operator "~"`},
+		{`{"not": {"field": "age", "op": "eq", "value": [1, 2]}}`, "filter not: age eq: expected a single value, not []interface {}{1, 2}"},
+		{`{"field": "age", "op": "in", "value": 1}`, "filter: age in: expected a list of values, not 1"},
+		{`{"field": "name", "op": "like", "value": 1}`, "filter: name like: expected a string, not 1"},
+		{`{"field": "age", "op": "between", "value": [1]}`, "filter: age between: expected low and high bounds for u.age, not []interface {}{1}"},
+		{`{"field": "age", "op": "gt", "value": null}`, "filter: age gt: cannot use null with less than or greater than operators"},
+	}
+	for _, test := range tests {
+		var f Filter
+		assert.NoError(t, json.Unmarshal([]byte(test.filter), &f), test.filter)
+		_, err := testFilterSchema.Build(f)
+		assert.EqualError(t, err, test.err, test.filter)
+	}
+}
+
+func TestFilterSchemaMaxDepth(t *testing.T) {
+	f := Filter{Field: "age", Op: "eq", Value: 1}
+	for i := 0; i < 3; i++ {
+		inner := f
+		f = Filter{Not: &inner}
+	}
+
+	schema := testFilterSchema
+	schema.MaxDepth = 3
+	_, err := schema.Build(f)
+	assert.NoError(t, err)
+
+	schema.MaxDepth = 2
+	_, err = schema.Build(f)
+	assert.EqualError(t, err, "filter not.not: nested too deep")
+}
+
+func TestFilterSchemaOps(t *testing.T) {
+	schema := FilterSchema{
+		Fields: map[string]string{"tags": "tags"},
+		Ops: map[string]FilterOp{
+			"has": func(expr string, value interface{}) (Sqlizer, error) {
+				return ArrayContains{expr: Array(value)}, nil
+			},
+		},
+	}
+	cond, err := schema.Build(Filter{Field: "tags", Op: "has", Value: "go"})
+	assert.NoError(t, err)
+	sql, args, err := cond.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "tags @> ARRAY[?]", sql)
+	assert.Equal(t, []interface{}{"go"}, args)
+
+	_, err = schema.Build(Filter{Field: "tags", Op: "eq", Value: "go"})
+	assert.EqualError(t, err, `filter: unknown operator "eq"`)
+}
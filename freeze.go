@@ -0,0 +1,93 @@
+package squirrel
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// FrozenQuery is a query whose SQL has been rendered once, by Freeze, and is
+// reused as is. Only its args can be changed, with Bind.
+//
+// It suits hot paths that run the same query shape on every request, skipping
+// the cost of building and rendering it again.
+//
+// Ex:
+//     byID := Select("*").From("users").Where(Eq{"id": 0}).PlaceholderFormat(Dollar).Freeze()
+//     // ... for each request:
+//     err := byID.Bind(id).RunWith(db).Scan(&user.ID, &user.Name)
+type FrozenQuery struct {
+	sql     string
+	args    []interface{}
+	err     error
+	runWith BaseRunner
+}
+
+// Freeze renders s and returns the result as a FrozenQuery. An error from
+// s.ToSql is returned when the FrozenQuery is used.
+func Freeze(s Sqlizer) FrozenQuery {
+	sql, args, err := s.ToSql()
+	return FrozenQuery{sql: sql, args: args, err: err}
+}
+
+// Bind returns a copy of q with args replacing the args it was rendered
+// with. There must be as many args as the rendered query has.
+func (q FrozenQuery) Bind(args ...interface{}) FrozenQuery {
+	if q.err == nil && len(args) != len(q.args) {
+		q.err = fmt.Errorf("frozen query has %d args, %d bound", len(q.args), len(args))
+	}
+	q.args = args
+	return q
+}
+
+// ToSql returns the frozen SQL string and the bound args.
+func (q FrozenQuery) ToSql() (string, []interface{}, error) {
+	return q.sql, q.args, q.err
+}
+
+// MustSql returns the frozen SQL string and the bound args.
+// It panics if there are any errors.
+func (q FrozenQuery) MustSql() (string, []interface{}) {
+	if q.err != nil {
+		panic(q.err)
+	}
+	return q.sql, q.args
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (q FrozenQuery) RunWith(runner BaseRunner) FrozenQuery {
+	q.runWith = wrapRunner(runner)
+	return q
+}
+
+// Exec Execs the query with the Runner set by RunWith.
+func (q FrozenQuery) Exec() (sql.Result, error) {
+	if q.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	return ExecWith(q.runWith, q)
+}
+
+// Query Querys the query with the Runner set by RunWith.
+func (q FrozenQuery) Query() (*sql.Rows, error) {
+	if q.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	return QueryWith(q.runWith, q)
+}
+
+// QueryRow QueryRows the query with the Runner set by RunWith.
+func (q FrozenQuery) QueryRow() RowScanner {
+	if q.runWith == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	queryRower, ok := q.runWith.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return QueryRowWith(queryRower, q)
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (q FrozenQuery) Scan(dest ...interface{}) error {
+	return q.QueryRow().Scan(dest...)
+}
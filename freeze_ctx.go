@@ -0,0 +1,52 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ExecContext ExecContexts the query with the Runner set by RunWith.
+func (q FrozenQuery) ExecContext(ctx context.Context) (sql.Result, error) {
+	if q.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := q.runWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return ExecContextWith(ctx, ctxRunner, q)
+}
+
+// QueryContext QueryContexts the query with the Runner set by RunWith.
+func (q FrozenQuery) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	if q.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := q.runWith.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return QueryContextWith(ctx, ctxRunner, q)
+}
+
+// QueryRowContext QueryRowContexts the query with the Runner set by RunWith.
+func (q FrozenQuery) QueryRowContext(ctx context.Context) RowScanner {
+	if q.runWith == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	queryRower, ok := q.runWith.(QueryRowerContext)
+	if !ok {
+		if _, ok := q.runWith.(QueryerContext); !ok {
+			return &Row{err: RunnerNotQueryRunner}
+		}
+		return &Row{err: NoContextSupport}
+	}
+	return QueryRowContextWith(ctx, queryRower, q)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (q FrozenQuery) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return q.QueryRowContext(ctx).Scan(dest...)
+}
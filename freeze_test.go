@@ -0,0 +1,65 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFreeze(t *testing.T) {
+	q := Select("*").From("users").Where(Eq{"id": 0}).PlaceholderFormat(Dollar).Freeze()
+
+	sql, args, err := q.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1", sql)
+	assert.Equal(t, []interface{}{0}, args)
+
+	sql, args, err = q.Bind(42).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1", sql)
+	assert.Equal(t, []interface{}{42}, args)
+
+	// Bind does not change q itself
+	_, args, _ = q.ToSql()
+	assert.Equal(t, []interface{}{0}, args)
+}
+
+func TestFreezeBindErr(t *testing.T) {
+	q := Update("t").Set("a", 1).Where("b = ?", 2).Freeze()
+
+	_, _, err := q.Bind(1).ToSql()
+	assert.EqualError(t, err, "frozen query has 2 args, 1 bound")
+
+	assert.Panics(t, func() { q.Bind().MustSql() })
+}
+
+func TestFreezeToSqlErr(t *testing.T) {
+	q := Select().Freeze()
+	_, _, err := q.ToSql()
+	assert.Error(t, err)
+
+	_, _, err = Freeze(Delete("")).ToSql()
+	assert.Error(t, err)
+}
+
+func TestFreezeRunners(t *testing.T) {
+	db := &DBStub{}
+	q := Insert("t").Columns("a").Values(0).RunWith(db).Freeze()
+
+	_, err := q.Bind(5).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (a) VALUES (?)", db.LastExecSql)
+	assert.Equal(t, []interface{}{5}, db.LastExecArgs)
+
+	_, err = Freeze(Expr("SELECT 1")).Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
+
+func BenchmarkFrozenQuery(b *testing.B) {
+	q := Select("id", "name").From("users").Where(Eq{"id": 0}).PlaceholderFormat(Dollar).Freeze()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = q.Bind(i).ToSql()
+	}
+}
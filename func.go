@@ -0,0 +1,184 @@
+package squirrel
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// funcExpr is a SQL function call, see Coalesce and Count.
+type funcExpr struct {
+	name     string
+	args     []interface{}
+	distinct bool
+}
+
+// Coalesce is the first of exprs that isn't NULL: COALESCE(exprs...).
+//
+// Like the other function helpers (NullIf, Count, Sum, Min, Max and Avg), it
+// takes strings as SQL, like column names, and Sqlizers as nested
+// expressions; any other value is bound as an arg. Bind a string with
+// Expr("?", s).
+//
+// Ex:
+//     Select("id").Column(Coalesce("nickname", "name", Expr("?", "anonymous")).As("display_name"))
+//     // SELECT id, COALESCE(nickname, name, ?) AS display_name
+func Coalesce(exprs ...interface{}) funcExpr {
+	return funcExpr{name: "COALESCE", args: exprs}
+}
+
+// NullIf is NULL if a equals b, and a otherwise: NULLIF(a, b). See Coalesce.
+//
+// Ex:
+//     Select().Column(Expr("total / ?", NullIf("quantity", 0)))
+//     // SELECT total / NULLIF(quantity, ?)
+func NullIf(a, b interface{}) funcExpr {
+	return funcExpr{name: "NULLIF", args: []interface{}{a, b}}
+}
+
+// Count is the COUNT(expr) aggregate; use "*" to count rows. See Coalesce.
+//
+// Ex:
+//     Select("team_id").Column(Count("*").As("members")).
+//         From("users").
+//         GroupBy("team_id").
+//         Having(Count("*").Gt(5))
+//     // SELECT team_id, COUNT(*) AS members FROM users GROUP BY team_id HAVING COUNT(*) > ?
+func Count(expr interface{}) funcExpr {
+	return funcExpr{name: "COUNT", args: []interface{}{expr}}
+}
+
+// Sum is the SUM(expr) aggregate. See Coalesce.
+func Sum(expr interface{}) funcExpr {
+	return funcExpr{name: "SUM", args: []interface{}{expr}}
+}
+
+// Min is the MIN(expr) aggregate. See Coalesce.
+func Min(expr interface{}) funcExpr {
+	return funcExpr{name: "MIN", args: []interface{}{expr}}
+}
+
+// Max is the MAX(expr) aggregate. See Coalesce.
+func Max(expr interface{}) funcExpr {
+	return funcExpr{name: "MAX", args: []interface{}{expr}}
+}
+
+// Avg is the AVG(expr) aggregate. See Coalesce.
+func Avg(expr interface{}) funcExpr {
+	return funcExpr{name: "AVG", args: []interface{}{expr}}
+}
+
+// Distinct makes an aggregate take only distinct values, e.g.
+// COUNT(DISTINCT user_id).
+func (f funcExpr) Distinct() funcExpr {
+	f.distinct = true
+	return f
+}
+
+func (f funcExpr) ToSql() (sql string, args []interface{}, err error) {
+	if len(f.args) == 0 {
+		err = fmt.Errorf("%s requires at least one argument", f.name)
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(f.name)
+	buf.WriteByte('(')
+	if f.distinct {
+		buf.WriteString("DISTINCT ")
+	}
+	for i, arg := range f.args {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		switch a := arg.(type) {
+		case string:
+			buf.WriteString(a)
+		case Sqlizer:
+			aSql, aArgs, err := nestedToSql(a)
+			if err != nil {
+				return "", nil, err
+			}
+			buf.WriteString(aSql)
+			args = append(args, aArgs...)
+		default:
+			buf.WriteByte('?')
+			args = append(args, a)
+		}
+	}
+	buf.WriteByte(')')
+	sql = buf.String()
+	return
+}
+
+// As names the result of f, for use as a column: expr AS alias.
+func (f funcExpr) As(alias string) Sqlizer {
+	return ConcatExpr(f, " AS "+alias)
+}
+
+// Eq is the condition that f equals value, or IS NULL if value is nil.
+func (f funcExpr) Eq(value interface{}) Sqlizer {
+	return funcCond{f: f, opr: "=", value: value}
+}
+
+// NotEq is the condition that f doesn't equal value, or IS NOT NULL if value
+// is nil.
+func (f funcExpr) NotEq(value interface{}) Sqlizer {
+	return funcCond{f: f, opr: "<>", value: value}
+}
+
+// Lt is the condition that f is less than value.
+func (f funcExpr) Lt(value interface{}) Sqlizer {
+	return funcCond{f: f, opr: "<", value: value}
+}
+
+// LtOrEq is the condition that f is less than or equal to value.
+func (f funcExpr) LtOrEq(value interface{}) Sqlizer {
+	return funcCond{f: f, opr: "<=", value: value}
+}
+
+// Gt is the condition that f is greater than value.
+func (f funcExpr) Gt(value interface{}) Sqlizer {
+	return funcCond{f: f, opr: ">", value: value}
+}
+
+// GtOrEq is the condition that f is greater than or equal to value.
+func (f funcExpr) GtOrEq(value interface{}) Sqlizer {
+	return funcCond{f: f, opr: ">=", value: value}
+}
+
+// funcCond compares a function call with a value
+type funcCond struct {
+	f     funcExpr
+	opr   string
+	value interface{}
+}
+
+func (c funcCond) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = c.f.ToSql()
+	if err != nil {
+		return
+	}
+
+	switch v := c.value.(type) {
+	case nil:
+		switch c.opr {
+		case "=":
+			sql += " IS NULL"
+		case "<>":
+			sql += " IS NOT NULL"
+		default:
+			return "", nil, fmt.Errorf("cannot use null with less than or greater than operators")
+		}
+	case Sqlizer:
+		vSql, vArgs, vErr := nestedToSql(v)
+		if vErr != nil {
+			return "", nil, vErr
+		}
+		sql = fmt.Sprintf("%s %s %s", sql, c.opr, vSql)
+		args = append(args, vArgs...)
+	default:
+		sql = fmt.Sprintf("%s %s ?", sql, c.opr)
+		args = append(args, v)
+	}
+	return
+}
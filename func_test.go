@@ -0,0 +1,49 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalesceAndNullIf(t *testing.T) {
+	sql, args, err := Select("id").
+		Column(Coalesce("nickname", "name", Expr("?", "anonymous")).As("display_name")).
+		Column(Expr("total / ?", NullIf("quantity", 0))).
+		From("orders").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT id, COALESCE(nickname, name, ?) AS display_name, total / NULLIF(quantity, ?) FROM orders",
+		sql)
+	assert.Equal(t, []interface{}{"anonymous", 0}, args)
+
+	_, _, err = Coalesce().ToSql()
+	assert.EqualError(t, err, "COALESCE requires at least one argument")
+}
+
+func TestAggregates(t *testing.T) {
+	sql, args, err := Select("team_id").
+		Column(Count("*").As("members")).
+		Column(Count("country").Distinct()).
+		Column(Sum(Coalesce("salary", 0)).As("payroll")).
+		Column(Min("age")).
+		Column(Max("age")).
+		Column(Avg("age")).
+		From("users").
+		GroupBy("team_id").
+		Having(Count("*").Gt(5)).
+		Having(Max("age").LtOrEq(Expr("(SELECT max_age FROM limits)"))).
+		Having(Min("deleted_at").Eq(nil)).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT team_id, COUNT(*) AS members, COUNT(DISTINCT country), "+
+		"SUM(COALESCE(salary, $1)) AS payroll, MIN(age), MAX(age), AVG(age) "+
+		"FROM users GROUP BY team_id "+
+		"HAVING COUNT(*) > $2 AND MAX(age) <= (SELECT max_age FROM limits) AND MIN(deleted_at) IS NULL", sql)
+	assert.Equal(t, []interface{}{0, 5}, args)
+
+	_, _, err = Count("*").Gt(nil).ToSql()
+	assert.EqualError(t, err, "cannot use null with less than or greater than operators")
+}
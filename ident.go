@@ -0,0 +1,148 @@
+package squirrel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Ident is a table or column name, possibly dotted like "u.name", that is
+// checked to be a plain identifier when rendered. Use it for names that come
+// from user input, e.g. a sort field from a query string, so that they can't
+// smuggle SQL into the query:
+//     Select("*").From("users").OrderBySafe(Ident(r.URL.Query().Get("sort")), Asc)
+//
+// Rendering an Ident that isn't a plain identifier returns an error. Builders
+// created from a StatementBuilderType with QuoteIdentifiers also quote it.
+type Ident string
+
+// ToSql implements Sqlizer.
+func (i Ident) ToSql() (string, []interface{}, error) {
+	if !i.valid() {
+		return "", nil, fmt.Errorf("invalid identifier %q", string(i))
+	}
+	return string(i), nil, nil
+}
+
+func (i Ident) valid() bool {
+	for _, part := range strings.Split(string(i), ".") {
+		if !isIdent(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// quotedIdent is an Ident quoted with q when rendered.
+type quotedIdent struct {
+	ident Ident
+	q     Quoter
+}
+
+func (qi quotedIdent) ToSql() (string, []interface{}, error) {
+	if !qi.ident.valid() {
+		return qi.ident.ToSql()
+	}
+	quoted, _ := quoteName(qi.q, string(qi.ident))
+	return quoted, nil, nil
+}
+
+// ident returns i as a Sqlizer quoted with the Quoter set by
+// StatementBuilderType.QuoteIdentifiers, if any.
+func (o builderOptions) ident(i Ident) Sqlizer {
+	if o.quoter == nil {
+		return i
+	}
+	return quotedIdent{ident: i, q: o.quoter}
+}
+
+// Direction is the sort direction of an ORDER BY expression.
+type Direction int
+
+const (
+	// Asc sorts in ascending order.
+	Asc Direction = iota
+	// Desc sorts in descending order.
+	Desc
+)
+
+// ParseDirection parses "asc" or "desc", in any case, into a Direction.
+func ParseDirection(s string) (Direction, error) {
+	switch strings.ToUpper(s) {
+	case "ASC":
+		return Asc, nil
+	case "DESC":
+		return Desc, nil
+	}
+	return Asc, fmt.Errorf("invalid sort direction %q", s)
+}
+
+func (d Direction) String() string {
+	if d == Desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// Nulls is where NULLs go in the order of an ORDER BY expression.
+type Nulls int
+
+const (
+	// NullsDefault leaves NULLs where the database puts them.
+	NullsDefault Nulls = iota
+	// NullsFirst sorts NULLs before other values.
+	NullsFirst
+	// NullsLast sorts NULLs after other values.
+	NullsLast
+)
+
+// orderTerm is an "expr ASC|DESC [NULLS FIRST|LAST]" item of an ORDER BY
+// clause.
+type orderTerm struct {
+	expr  Sqlizer
+	dir   Direction
+	nulls Nulls
+	// caseNulls is set for databases without NULLS FIRST/LAST, where the
+	// NULLs are sorted by a CASE expression instead.
+	caseNulls bool
+}
+
+func (t orderTerm) ToSql() (string, []interface{}, error) {
+	if t.dir != Asc && t.dir != Desc {
+		return "", nil, fmt.Errorf("invalid sort direction %d", int(t.dir))
+	}
+	sql, args, err := nestedToSql(t.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	sql += " " + t.dir.String()
+
+	switch t.nulls {
+	case NullsDefault:
+		return sql, args, nil
+	case NullsFirst, NullsLast:
+	default:
+		return "", nil, fmt.Errorf("invalid nulls order %d", int(t.nulls))
+	}
+	if !t.caseNulls {
+		if t.nulls == NullsFirst {
+			return sql + " NULLS FIRST", args, nil
+		}
+		return sql + " NULLS LAST", args, nil
+	}
+
+	exprSql, exprArgs, _ := nestedToSql(t.expr)
+	first, rest := "0", "1"
+	if t.nulls == NullsLast {
+		first, rest = rest, first
+	}
+	caseSql := fmt.Sprintf("CASE WHEN %s IS NULL THEN %s ELSE %s END, ", exprSql, first, rest)
+	return caseSql + sql, append(exprArgs, args...), nil
+}
+
+// forDialect sorts the NULLs with a CASE expression on SQL Server, MySQL and
+// YDB, which don't support NULLS FIRST/LAST.
+func (t orderTerm) forDialect(d dialectKind, explicit bool) Sqlizer {
+	t.caseNulls = d == dialectSQLServer || d == dialectMySQL || d == dialectYQL
+	t.expr, _ = partForDialect(t.expr, d, explicit)
+	return t
+}
@@ -0,0 +1,88 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdent(t *testing.T) {
+	sql, args, err := Ident("u.name").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "u.name", sql)
+	assert.Nil(t, args)
+
+	for _, bad := range []string{"", "name; DROP TABLE users", "a..b", "name DESC", "1a", "(SELECT 1)", "a.*"} {
+		_, _, err := Ident(bad).ToSql()
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	dir, err := ParseDirection("desc")
+	assert.NoError(t, err)
+	assert.Equal(t, Desc, dir)
+
+	dir, err = ParseDirection("ASC")
+	assert.NoError(t, err)
+	assert.Equal(t, Asc, dir)
+
+	_, err = ParseDirection("ASC; DROP TABLE users")
+	assert.EqualError(t, err, `invalid sort direction "ASC; DROP TABLE users"`)
+}
+
+func TestSelectBuilderOrderBySafe(t *testing.T) {
+	sql, _, err := Select("*").From("users").
+		OrderBySafe("name", Asc).
+		OrderBySafe("u.created_at", Desc).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users ORDER BY name ASC, u.created_at DESC", sql)
+
+	_, _, err = Select("*").From("users").OrderBySafe("name; DROP TABLE users", Asc).ToSql()
+	assert.EqualError(t, err, `select builder: ORDER BY: invalid identifier "name; DROP TABLE users"`)
+
+	_, _, err = Select("*").From("users").OrderBySafe("name", Direction(7)).ToSql()
+	assert.EqualError(t, err, "select builder: ORDER BY: invalid sort direction 7")
+}
+
+func TestSelectBuilderIdentColumnsAndGroupBy(t *testing.T) {
+	sb := StatementBuilder.QuoteIdentifiers(DoubleQuoteQuoter)
+	sql, _, err := sb.Select().
+		Column(Ident("group")).
+		Column("COUNT(*)").
+		From("t").
+		GroupByIdent("group").
+		OrderBySafe("group", Desc).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "group", COUNT(*) FROM "t" GROUP BY "group" ORDER BY "group" DESC`, sql)
+
+	_, _, err = Select().Column(Ident("a)")).ToSql()
+	assert.EqualError(t, err, `select builder: SELECT: invalid identifier "a)"`)
+
+	_, _, err = sb.Select("a").From("t").GroupByIdent("a b").ToSql()
+	assert.EqualError(t, err, `select builder: GROUP BY: invalid identifier "a b"`)
+}
+
+func TestSelectBuilderOrderByCol(t *testing.T) {
+	sql, _, err := Select("*").From("users").
+		OrderByCol("last_login", Desc, NullsLast).
+		OrderByCol("name", Asc).
+		OrderByCol("nick", Asc, NullsFirst).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users ORDER BY last_login DESC NULLS LAST, name ASC, nick ASC NULLS FIRST", sql)
+
+	sql, _, err = Select("*").From("users").
+		OrderByCol("last_login", Desc, NullsLast).
+		PlaceholderFormat(AtP).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"SELECT * FROM users ORDER BY CASE WHEN last_login IS NULL THEN 1 ELSE 0 END, last_login DESC",
+		sql)
+
+	_, _, err = Select("*").From("users").OrderByCol("name", Asc, Nulls(5)).ToSql()
+	assert.EqualError(t, err, "select builder: ORDER BY: invalid nulls order 5")
+}
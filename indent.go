@@ -0,0 +1,180 @@
+package squirrel
+
+import (
+	"strings"
+)
+
+// indentClauses are the keywords that start a new line in IndentSql. Longer
+// phrases come first, so that e.g. "LEFT JOIN" is not split before "JOIN".
+var indentClauses = []string{
+	"ON DUPLICATE KEY UPDATE",
+	"LEFT OUTER JOIN",
+	"RIGHT OUTER JOIN",
+	"FULL OUTER JOIN",
+	"INSERT INTO",
+	"REPLACE INTO",
+	"UPSERT INTO",
+	"DELETE FROM",
+	"LEFT JOIN",
+	"RIGHT JOIN",
+	"INNER JOIN",
+	"CROSS JOIN",
+	"FULL JOIN",
+	"UNION ALL",
+	"GROUP BY",
+	"ORDER BY",
+	"INTERSECT",
+	"RETURNING",
+	"EXCEPT",
+	"SELECT",
+	"UPDATE",
+	"VALUES",
+	"HAVING",
+	"WINDOW",
+	"OFFSET",
+	"UNION",
+	"WHERE",
+	"LIMIT",
+	"USING",
+	"FROM",
+	"JOIN",
+	"WITH",
+	"SET",
+}
+
+// IndentSql formats a SQL statement for reading: each clause (SELECT, FROM,
+// JOIN, WHERE, ...) starts on a new line, and subqueries are indented by two
+// spaces.
+//
+// Ex:
+//     IndentSql("SELECT a FROM t WHERE b IN (SELECT b FROM u) ORDER BY a")
+//     // SELECT a
+//     // FROM t
+//     // WHERE b IN (
+//     //   SELECT b
+//     //   FROM u
+//     // )
+//     // ORDER BY a
+//
+// Quoted strings and identifiers are left untouched, as are parentheses that
+// don't hold a subquery, e.g. function calls.
+func IndentSql(sql string) string {
+	buf := &strings.Builder{}
+	buf.Grow(len(sql) + len(sql)/8)
+
+	// subquery[i] tells whether the i-th open parenthesis holds a subquery
+	var subquery []bool
+	level := 0
+	newline := func() {
+		buf.WriteByte('\n')
+		buf.WriteString(strings.Repeat("  ", level))
+	}
+	// clauses break lines at the top level and directly inside subqueries
+	breaking := func() bool {
+		return len(subquery) == 0 || subquery[len(subquery)-1]
+	}
+
+	// atStart is set where a statement starts, so that a leading phrase like
+	// "DELETE FROM" is kept on one line
+	atStart := true
+	for i := 0; i < len(sql); i++ {
+		if atStart {
+			atStart = false
+			if clause := matchClause(sql[i:]); clause != "" {
+				buf.WriteString(sql[i : i+len(clause)])
+				i += len(clause) - 1
+				continue
+			}
+		}
+
+		c := sql[i]
+		switch c {
+		case '\'', '"', '`':
+			end := strings.IndexByte(sql[i+1:], c)
+			if end == -1 {
+				buf.WriteString(sql[i:])
+				return buf.String()
+			}
+			buf.WriteString(sql[i : i+end+2])
+			i += end + 1
+			continue
+
+		case '(':
+			buf.WriteByte(c)
+			rest := strings.TrimLeft(sql[i+1:], " ")
+			sub := startsWithClause(rest, "SELECT") || startsWithClause(rest, "WITH")
+			subquery = append(subquery, sub)
+			if sub {
+				level++
+				newline()
+				i += len(sql[i+1:]) - len(rest)
+				atStart = true
+			}
+			continue
+
+		case ')':
+			if len(subquery) > 0 {
+				if subquery[len(subquery)-1] {
+					level--
+					newline()
+				}
+				subquery = subquery[:len(subquery)-1]
+			}
+			buf.WriteByte(c)
+			continue
+
+		case ';':
+			// statement separator, e.g. after YQL pragmas
+			buf.WriteByte(c)
+			if len(subquery) == 0 && i+1 < len(sql) && sql[i+1] == ' ' {
+				newline()
+				i++
+				atStart = true
+			}
+			continue
+
+		case ' ':
+			if breaking() {
+				if clause := matchClause(sql[i+1:]); clause != "" {
+					newline()
+					buf.WriteString(sql[i+1 : i+1+len(clause)])
+					i += len(clause)
+					continue
+				}
+			}
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
+}
+
+// matchClause returns the clause keyword s starts with, if any.
+func matchClause(s string) string {
+	for _, clause := range indentClauses {
+		if startsWithClause(s, clause) {
+			return clause
+		}
+	}
+	return ""
+}
+
+// startsWithClause tells whether s starts with the keyword clause, in any case,
+// followed by a space or the end of s.
+func startsWithClause(s, clause string) bool {
+	if len(s) < len(clause) || !strings.EqualFold(s[:len(clause)], clause) {
+		return false
+	}
+	return len(s) == len(clause) || s[len(clause)] == ' '
+}
+
+// ToSqlIndented calls ToSql on s and formats the SQL with IndentSql.
+//
+// The result is meant for logs and for comparing generated queries in tests;
+// it runs the same as the unformatted SQL.
+func ToSqlIndented(s Sqlizer) (string, []interface{}, error) {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return IndentSql(sql), args, nil
+}
@@ -0,0 +1,55 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndentSql(t *testing.T) {
+	sql, args, err := ToSqlIndented(Select("a", "COUNT(*)").
+		From("t").
+		LeftJoin("u ON u.id = t.u_id").
+		Where(Expr("b IN (?) AND c = 'x where y'", Select("b").From("v").Where("d = ?", 1))).
+		GroupBy("a").
+		OrderBy("a").
+		Limit(10))
+	assert.NoError(t, err)
+
+	expected := "SELECT a, COUNT(*)\n" +
+		"FROM t\n" +
+		"LEFT JOIN u ON u.id = t.u_id\n" +
+		"WHERE b IN (\n" +
+		"  SELECT b\n" +
+		"  FROM v\n" +
+		"  WHERE d = ?\n" +
+		") AND c = 'x where y'\n" +
+		"GROUP BY a\n" +
+		"ORDER BY a\n" +
+		"LIMIT 10"
+	assert.Equal(t, expected, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestIndentSqlStatements(t *testing.T) {
+	assert.Equal(t,
+		"INSERT INTO t (a,b)\nVALUES (?,?)\nON DUPLICATE KEY UPDATE b = VALUES(b)",
+		IndentSql("INSERT INTO t (a,b) VALUES (?,?) ON DUPLICATE KEY UPDATE b = VALUES(b)"))
+
+	assert.Equal(t,
+		"UPDATE t\nSET a = ?\nWHERE b = ?\nRETURNING id",
+		IndentSql("UPDATE t SET a = ? WHERE b = ? RETURNING id"))
+
+	assert.Equal(t,
+		"PRAGMA TablePathPrefix(\"/Root\");\nDELETE FROM t\nWHERE EXTRACT(YEAR FROM d) = ?",
+		IndentSql("PRAGMA TablePathPrefix(\"/Root\"); DELETE FROM t WHERE EXTRACT(YEAR FROM d) = ?"))
+
+	assert.Equal(t,
+		"WITH r AS (\n  SELECT id\n  FROM o\n)\nSELECT *\nFROM r\nUNION ALL\nSELECT 1",
+		IndentSql("WITH r AS (SELECT id FROM o) SELECT * FROM r UNION ALL SELECT 1"))
+}
+
+func TestToSqlIndentedErr(t *testing.T) {
+	_, _, err := ToSqlIndented(Select())
+	assert.Error(t, err)
+}
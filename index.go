@@ -0,0 +1,265 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// CREATE INDEX
+
+type createIndexData struct {
+	PlaceholderFormat PlaceholderFormat
+	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
+	Name              string
+	Unique            bool
+	IfNotExists       bool
+	Table             string
+	Columns           []string
+	Includes          []string
+	WhereParts        []Sqlizer
+}
+
+func (d *createIndexData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Table) == 0 {
+		err = builderError("create index", "no table")
+		return
+	}
+	if len(d.Columns) == 0 {
+		err = builderError("create index", "no columns")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("CREATE ")
+	if d.Unique {
+		sql.WriteString("UNIQUE ")
+	}
+	sql.WriteString("INDEX ")
+	if d.IfNotExists {
+		sql.WriteString("IF NOT EXISTS ")
+	}
+	if len(d.Name) > 0 {
+		sql.WriteString(d.Name)
+		sql.WriteString(" ")
+	}
+	fmt.Fprintf(sql, "ON %s (%s)", d.Table, strings.Join(d.Columns, ", "))
+
+	if len(d.Includes) > 0 {
+		fmt.Fprintf(sql, " INCLUDE (%s)", strings.Join(d.Includes, ", "))
+	}
+
+	if len(d.WhereParts) > 0 {
+		sql.WriteString(" WHERE ")
+		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
+		if err != nil {
+			err = clauseError("create index", "WHERE", err)
+			return
+		}
+	}
+
+	sqlStr, args, err = finalizeDDL(d.PlaceholderFormat, d.Comments, d.Pragmas, sql.String(), args)
+	return
+}
+
+// CreateIndexBuilder builds CREATE INDEX statements.
+//
+// YDB creates secondary indexes with ALTER TABLE instead; see TableIndex and
+// AlterTableBuilder.AddIndex.
+type CreateIndexBuilder struct {
+	data createIndexData
+	builderOptions
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b CreateIndexBuilder) PlaceholderFormat(f PlaceholderFormat) CreateIndexBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b CreateIndexBuilder) RunWith(runner BaseRunner) CreateIndexBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b CreateIndexBuilder) Exec() (sql.Result, error) {
+	data := b.data
+	return ddlExec(data.RunWith, &data)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b CreateIndexBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b CreateIndexBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query.
+//
+// See SelectBuilder.Pragma.
+func (b CreateIndexBuilder) Pragma(name, value string) CreateIndexBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b CreateIndexBuilder) Comment(comment string) CreateIndexBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
+// Name sets the name of the index. It may be left empty on databases that
+// generate one, e.g. Postgres.
+func (b CreateIndexBuilder) Name(name string) CreateIndexBuilder {
+	b.data.Name = name
+	return b
+}
+
+// Unique makes this a CREATE UNIQUE INDEX.
+func (b CreateIndexBuilder) Unique() CreateIndexBuilder {
+	b.data.Unique = true
+	return b
+}
+
+// IfNotExists adds IF NOT EXISTS to the query.
+func (b CreateIndexBuilder) IfNotExists() CreateIndexBuilder {
+	b.data.IfNotExists = true
+	return b
+}
+
+// On sets the indexed table and columns.
+func (b CreateIndexBuilder) On(table string, columns ...string) CreateIndexBuilder {
+	b.data.Table = b.qualifyTable(table)
+	b.data.Columns = appendStrings(b.data.Columns, columns...)
+	return b
+}
+
+// Include adds covered columns to the index, "INCLUDE (columns)".
+func (b CreateIndexBuilder) Include(columns ...string) CreateIndexBuilder {
+	b.data.Includes = appendStrings(b.data.Includes, columns...)
+	return b
+}
+
+// Where adds a predicate to a partial index.
+//
+// See SelectBuilder.Where for the accepted predicates.
+func (b CreateIndexBuilder) Where(pred interface{}, args ...interface{}) CreateIndexBuilder {
+	b.data.WhereParts = appendSqlizers(b.data.WhereParts, newWherePart(pred, args...))
+	return b
+}
+
+// YQL table index
+
+type tableIndexData struct {
+	Name    string
+	Unique  bool
+	Mode    string
+	Columns []string
+	Covers  []string
+}
+
+func (d *tableIndexData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Name) == 0 {
+		err = builderError("table index", "no name")
+		return
+	}
+	if len(d.Columns) == 0 {
+		err = builderError("table index", "no columns")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("INDEX ")
+	sql.WriteString(d.Name)
+	sql.WriteString(" GLOBAL")
+	if d.Unique {
+		sql.WriteString(" UNIQUE")
+	}
+	if len(d.Mode) > 0 {
+		sql.WriteString(" ")
+		sql.WriteString(d.Mode)
+	}
+	fmt.Fprintf(sql, " ON (%s)", strings.Join(d.Columns, ", "))
+
+	if len(d.Covers) > 0 {
+		fmt.Fprintf(sql, " COVER (%s)", strings.Join(d.Covers, ", "))
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// TableIndexBuilder builds YQL secondary index definitions, as used by
+// CreateTableBuilder.IndexDef and AlterTableBuilder.AddIndex.
+type TableIndexBuilder struct {
+	data tableIndexData
+}
+
+// TableIndex returns a new TableIndexBuilder for a global index with the
+// given name.
+//
+// Ex:
+//     AlterTable("series").AddIndex(TableIndex("title_index").On("title").Cover("year").Async())
+//     // ALTER TABLE series ADD INDEX title_index GLOBAL ASYNC ON (title) COVER (year)
+func TableIndex(name string) TableIndexBuilder {
+	return TableIndexBuilder{data: tableIndexData{Name: name}}
+}
+
+// ToSql builds the index definition into a SQL string and bound args.
+func (b TableIndexBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// On adds indexed columns.
+func (b TableIndexBuilder) On(columns ...string) TableIndexBuilder {
+	b.data.Columns = appendStrings(b.data.Columns, columns...)
+	return b
+}
+
+// Cover adds covered columns, "COVER (columns)", whose values are stored in
+// the index so reads through it don't need to look up the main table.
+func (b TableIndexBuilder) Cover(columns ...string) TableIndexBuilder {
+	b.data.Covers = appendStrings(b.data.Covers, columns...)
+	return b
+}
+
+// Unique makes this a unique index.
+func (b TableIndexBuilder) Unique() TableIndexBuilder {
+	b.data.Unique = true
+	return b
+}
+
+// Sync makes the index synchronous: it is updated in the same transaction as
+// the table. This is the YDB default.
+func (b TableIndexBuilder) Sync() TableIndexBuilder {
+	b.data.Mode = "SYNC"
+	return b
+}
+
+// Async makes the index asynchronous: it is updated in the background and
+// may lag behind the table.
+func (b TableIndexBuilder) Async() TableIndexBuilder {
+	b.data.Mode = "ASYNC"
+	return b
+}
@@ -0,0 +1,82 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateIndexBuilderToSql(t *testing.T) {
+	sql, args, err := CreateIndex("users_email_idx").
+		Unique().
+		IfNotExists().
+		On("users", "lower(email)").
+		Include("name").
+		Where(Eq{"deleted": false}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "CREATE UNIQUE INDEX IF NOT EXISTS users_email_idx ON users (lower(email)) " +
+		"INCLUDE (name) WHERE deleted = $1"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{false}, args)
+}
+
+func TestCreateIndexBuilderUnnamed(t *testing.T) {
+	sql, _, err := CreateIndex("").On("users", "a", "b").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE INDEX ON users (a, b)", sql)
+}
+
+func TestCreateIndexBuilderToSqlErr(t *testing.T) {
+	_, _, err := CreateIndex("i").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = CreateIndex("i").On("t").ToSql()
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { CreateIndex("i").MustSql() })
+}
+
+func TestCreateIndexBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := CreateIndex("i").On("t", "a").RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE INDEX i ON t (a)", db.LastExecSql)
+}
+
+func TestTableIndexBuilderToSql(t *testing.T) {
+	sql, _, err := TableIndex("title_index").On("title", "year").Cover("rating").Async().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INDEX title_index GLOBAL ASYNC ON (title, year) COVER (rating)", sql)
+
+	sql, _, err = TableIndex("email_index").On("email").Unique().Sync().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INDEX email_index GLOBAL UNIQUE SYNC ON (email)", sql)
+
+	_, _, err = TableIndex("").On("a").ToSql()
+	assert.Error(t, err)
+
+	_, _, err = TableIndex("i").ToSql()
+	assert.Error(t, err)
+}
+
+func TestTableIndexInDDL(t *testing.T) {
+	sql, _, err := AlterTable("series").
+		AddIndex(TableIndex("title_index").On("title").Cover("year").Async()).
+		DropIndex("old_index").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE series ADD INDEX title_index GLOBAL ASYNC ON (title) COVER (year), "+
+		"DROP INDEX old_index", sql)
+
+	sql, _, err = CreateTable("series").
+		Column("id", "Uint64").
+		PrimaryKey("id").
+		IndexDef(TableIndex("title_index").On("title").Cover("year")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE TABLE series (id Uint64, PRIMARY KEY (id), "+
+		"INDEX title_index GLOBAL ON (title) COVER (year))", sql)
+}
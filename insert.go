@@ -1,28 +1,31 @@
 package squirrel
 
 import (
-	"bytes"
 	"database/sql"
 	"errors"
 	"fmt"
 	"io"
 	"sort"
 	"strings"
-
-	"github.com/lann/builder"
 )
 
 type insertData struct {
 	PlaceholderFormat PlaceholderFormat
 	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
 	Prefixes          []Sqlizer
 	StatementKeyword  string
+	Ignore            bool
+	ConflictKeys      []string
 	Options           []string
 	Into              string
 	Columns           []string
 	Values            [][]interface{}
+	Returning         []Sqlizer
 	Suffixes          []Sqlizer
 	Select            *SelectBuilder
+	DuplicateUpdates  []setClause
 }
 
 func (d *insertData) Exec() (sql.Result, error) {
@@ -51,32 +54,80 @@ func (d *insertData) QueryRow() RowScanner {
 }
 
 func (d *insertData) ToSql() (sqlStr string, args []interface{}, err error) {
+	// write expressions like ILike for the database
+	_, explicit := d.PlaceholderFormat.(dialectFormat)
+	written := d.forDialect(dialectKindOf(d.PlaceholderFormat), explicit)
+	d = &written
+
+	sqlStr, args, err = d.toSqlRaw()
+	if err != nil {
+		return
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	if err != nil {
+		return
+	}
+
+	sqlStr = commentsToSql(d.Comments) + pragmasToSql(d.Pragmas) + sqlStr
+	return
+}
+
+// forDialect returns d with its expressions, including those in subqueries,
+// written for the database dialect.
+func (d insertData) forDialect(dialect dialectKind, explicit bool) insertData {
+	d.Prefixes = forDialect(d.Prefixes, dialect, explicit)
+	var values [][]interface{}
+	for i, row := range d.Values {
+		if rewritten, ok := argsForDialect(row, dialect, explicit); ok {
+			if values == nil {
+				values = append([][]interface{}(nil), d.Values...)
+			}
+			values[i] = rewritten
+		}
+	}
+	if values != nil {
+		d.Values = values
+	}
+	if d.Select != nil {
+		sel := d.Select.forDialect(dialect, explicit).(SelectBuilder)
+		d.Select = &sel
+	}
+	d.DuplicateUpdates = setClausesForDialect(d.DuplicateUpdates, dialect, explicit)
+	d.Returning = forDialect(d.Returning, dialect, explicit)
+	d.Suffixes = forDialect(d.Suffixes, dialect, explicit)
+	return d
+}
+
+func (d *insertData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 	if len(d.Into) == 0 {
-		err = errors.New("insert statements must specify a table")
+		err = builderError("insert", "no table")
 		return
 	}
 	if len(d.Values) == 0 && d.Select == nil {
-		err = errors.New("insert statements must have at least one set of values or select clause")
+		err = builderError("insert", "no values or select")
 		return
 	}
 
-	sql := &bytes.Buffer{}
+	sql := getBuffer()
+	defer putBuffer(sql)
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("insert", "prefix", err)
 			return
 		}
 
 		sql.WriteString(" ")
 	}
 
-	if d.StatementKeyword == "" {
-		sql.WriteString("INSERT ")
-	} else {
-		sql.WriteString(d.StatementKeyword)
-		sql.WriteString(" ")
+	keyword, onConflict, err := d.statementSyntax()
+	if err != nil {
+		return
 	}
+	sql.WriteString(keyword)
+	sql.WriteString(" ")
 
 	if len(d.Options) > 0 {
 		sql.WriteString(strings.Join(d.Options, " "))
@@ -95,25 +146,128 @@ func (d *insertData) ToSql() (sqlStr string, args []interface{}, err error) {
 
 	if d.Select != nil {
 		args, err = d.appendSelectToSQL(sql, args)
+		if err != nil {
+			err = clauseError("insert", "SELECT", err)
+			return
+		}
 	} else {
 		args, err = d.appendValuesToSQL(sql, args)
+		if err != nil {
+			err = clauseError("insert", "VALUES", err)
+			return
+		}
 	}
-	if err != nil {
-		return
+
+	if onConflict != "" {
+		sql.WriteString(" ")
+		sql.WriteString(onConflict)
+	}
+
+	if len(d.DuplicateUpdates) > 0 {
+		sql.WriteString(" ON DUPLICATE KEY UPDATE ")
+		args, err = appendSetClauses(d.DuplicateUpdates, sql, args)
+		if err != nil {
+			err = clauseError("insert", "ON DUPLICATE KEY UPDATE", err)
+			return
+		}
+	}
+
+	if len(d.Returning) > 0 {
+		sql.WriteString(" RETURNING ")
+		args, err = appendToSql(d.Returning, sql, ", ", args)
+		if err != nil {
+			err = clauseError("insert", "RETURNING", err)
+			return
+		}
 	}
 
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
 		args, err = appendToSql(d.Suffixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("insert", "suffix", err)
 			return
 		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
 }
 
+// statementSyntax returns the statement keyword of the query, e.g. "INSERT
+// IGNORE", and the clause that goes with it after the values, e.g. "ON
+// CONFLICT DO NOTHING", in the dialect of the query.
+func (d *insertData) statementSyntax() (keyword, onConflict string, err error) {
+	dialect := dialectKindOf(d.PlaceholderFormat)
+	if d.Ignore {
+		if d.StatementKeyword != "" {
+			return "", "", builderError("insert", "Ignore can't be used with "+d.StatementKeyword)
+		}
+		switch ignore := insertIgnoreSyntax(dialect); ignore {
+		case "":
+			return "", "", builderError("insert", "Ignore is not supported by this database; use Merge")
+		case insertIgnoreOnConflict:
+			return "INSERT", ignore, nil
+		default:
+			return ignore, "", nil
+		}
+	}
+
+	switch d.StatementKeyword {
+	case "":
+		return "INSERT", "", nil
+	case "UPSERT":
+		// only rewritten for an explicit Dialect, since YDB, which has UPSERT,
+		// can be used with any placeholder format
+		if _, ok := d.PlaceholderFormat.(dialectFormat); ok {
+			return d.upsertSyntax(dialect)
+		}
+	}
+	return d.StatementKeyword, "", nil
+}
+
+// upsertSyntax returns the syntax of an Upsert on databases without an
+// UPSERT statement: INSERT with an ON DUPLICATE KEY UPDATE or ON CONFLICT
+// clause updating every column that was given.
+func (d *insertData) upsertSyntax(dialect dialectKind) (keyword, onConflict string, err error) {
+	switch dialect {
+	case dialectYQL:
+		return "UPSERT", "", nil
+	case dialectMySQL:
+		if len(d.DuplicateUpdates) > 0 {
+			return "INSERT", "", nil
+		}
+		if len(d.Columns) == 0 {
+			return "", "", builderError("insert", "Upsert needs Columns on MySQL")
+		}
+		updates := make([]string, len(d.Columns))
+		for i, c := range d.Columns {
+			updates[i] = fmt.Sprintf("%s = VALUES(%s)", c, c)
+		}
+		return "INSERT", "ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", "), nil
+	case dialectPostgres, dialectSQLite:
+		if len(d.ConflictKeys) == 0 || len(d.Columns) == 0 {
+			return "", "", builderError("insert", "Upsert needs Columns and ConflictKeys on this database")
+		}
+		keys := make(map[string]bool, len(d.ConflictKeys))
+		for _, k := range d.ConflictKeys {
+			keys[k] = true
+		}
+		var updates []string
+		for _, c := range d.Columns {
+			if !keys[c] {
+				updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", c, c))
+			}
+		}
+		onConflict = "ON CONFLICT (" + strings.Join(d.ConflictKeys, ", ") + ") DO "
+		if len(updates) == 0 {
+			return "INSERT", onConflict + "NOTHING", nil
+		}
+		return "INSERT", onConflict + "UPDATE SET " + strings.Join(updates, ", "), nil
+	}
+	return "", "", builderError("insert", "Upsert is not supported by this database; use Merge")
+}
+
 func (d *insertData) appendValuesToSQL(w io.Writer, args []interface{}) ([]interface{}, error) {
 	if len(d.Values) == 0 {
 		return args, errors.New("values for insert statements are not set")
@@ -121,27 +275,37 @@ func (d *insertData) appendValuesToSQL(w io.Writer, args []interface{}) ([]inter
 
 	io.WriteString(w, "VALUES ")
 
-	valuesStrings := make([]string, len(d.Values))
+	// size args for the values bound as they are, the common case
+	n := 0
+	for _, row := range d.Values {
+		n += len(row)
+	}
+	args = growArgs(args, n)
+
 	for r, row := range d.Values {
-		valueStrings := make([]string, len(row))
+		if r > 0 {
+			io.WriteString(w, ",")
+		}
+		io.WriteString(w, "(")
 		for v, val := range row {
+			if v > 0 {
+				io.WriteString(w, ",")
+			}
 			if vs, ok := val.(Sqlizer); ok {
-				vsql, vargs, err := vs.ToSql()
+				vsql, vargs, err := nestedToSql(vs)
 				if err != nil {
 					return nil, err
 				}
-				valueStrings[v] = vsql
+				io.WriteString(w, vsql)
 				args = append(args, vargs...)
 			} else {
-				valueStrings[v] = "?"
+				io.WriteString(w, "?")
 				args = append(args, val)
 			}
 		}
-		valuesStrings[r] = fmt.Sprintf("(%s)", strings.Join(valueStrings, ","))
+		io.WriteString(w, ")")
 	}
 
-	io.WriteString(w, strings.Join(valuesStrings, ","))
-
 	return args, nil
 }
 
@@ -150,7 +314,7 @@ func (d *insertData) appendSelectToSQL(w io.Writer, args []interface{}) ([]inter
 		return args, errors.New("select clause for insert statements are not set")
 	}
 
-	selectClause, sArgs, err := d.Select.ToSql()
+	selectClause, sArgs, err := d.Select.toSqlRaw()
 	if err != nil {
 		return args, err
 	}
@@ -164,42 +328,75 @@ func (d *insertData) appendSelectToSQL(w io.Writer, args []interface{}) ([]inter
 // Builder
 
 // InsertBuilder builds SQL INSERT statements.
-type InsertBuilder builder.Builder
+type InsertBuilder struct {
+	data insertData
+	builderOptions
+}
 
-func init() {
-	builder.Register(InsertBuilder{}, insertData{})
+// build applies the middlewares set with WithMiddleware and returns the data
+// to render.
+func (b InsertBuilder) build() insertData {
+	if len(b.middlewares) > 0 {
+		mws := b.middlewares
+		b.middlewares = nil
+		b = applyMiddlewares(b, mws).(InsertBuilder)
+	}
+	return b.data
 }
 
+// UpsertBuilder builds UPSERT statements (e.g. YDB's "UPSERT INTO").
+//
+// It is an InsertBuilder with the statement keyword set to "UPSERT", so it
+// supports the same Columns, Values, SetMap and Select methods.
+//
+// With a Dialect for a database without UPSERT, it writes an INSERT that
+// updates the given columns of a row that already exists instead: with ON
+// DUPLICATE KEY UPDATE on MySQL, and with ON CONFLICT on PostgreSQL and
+// SQLite, which also need the unique key set with ConflictKeys:
+//     StatementBuilder.Dialect(Postgres).Upsert("t").
+//         Columns("id", "n").Values(1, 2).ConflictKeys("id")
+//     // INSERT INTO "t" ("id","n") VALUES ($1,$2) ON CONFLICT ("id") DO UPDATE SET "n" = EXCLUDED."n"
+type UpsertBuilder = InsertBuilder
+
+// ReplaceBuilder builds REPLACE statements (e.g. "REPLACE INTO" in YDB and
+// MySQL).
+//
+// It is an InsertBuilder with the statement keyword set to "REPLACE", so it
+// supports the same Columns, Values, SetMap and Select methods.
+type ReplaceBuilder = InsertBuilder
+
 // Format methods
 
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b InsertBuilder) PlaceholderFormat(f PlaceholderFormat) InsertBuilder {
-	return builder.Set(b, "PlaceholderFormat", f).(InsertBuilder)
+	b.data.PlaceholderFormat = f
+	return b
 }
 
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
 func (b InsertBuilder) RunWith(runner BaseRunner) InsertBuilder {
-	return setRunWith(b, runner).(InsertBuilder)
+	b.data.RunWith = wrapRunner(runner)
+	return b
 }
 
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b InsertBuilder) Exec() (sql.Result, error) {
-	data := builder.GetStruct(b).(insertData)
+	data := b.build()
 	return data.Exec()
 }
 
 // Query builds and Querys the query with the Runner set by RunWith.
 func (b InsertBuilder) Query() (*sql.Rows, error) {
-	data := builder.GetStruct(b).(insertData)
+	data := b.build()
 	return data.Query()
 }
 
 // QueryRow builds and QueryRows the query with the Runner set by RunWith.
 func (b InsertBuilder) QueryRow() RowScanner {
-	data := builder.GetStruct(b).(insertData)
+	data := b.build()
 	return data.QueryRow()
 }
 
@@ -212,10 +409,22 @@ func (b InsertBuilder) Scan(dest ...interface{}) error {
 
 // ToSql builds the query into a SQL string and bound args.
 func (b InsertBuilder) ToSql() (string, []interface{}, error) {
-	data := builder.GetStruct(b).(insertData)
+	data := b.build()
 	return data.ToSql()
 }
 
+func (b InsertBuilder) toSqlRaw() (string, []interface{}, error) {
+	data := b.build()
+	return data.toSqlRaw()
+}
+
+// forDialect writes the expressions of b for the database d, when b is a
+// subquery.
+func (b InsertBuilder) forDialect(d dialectKind, explicit bool) Sqlizer {
+	b.data = b.data.forDialect(d, explicit)
+	return b
+}
+
 // MustSql builds the query into a SQL string and bound args.
 // It panics if there are any errors.
 func (b InsertBuilder) MustSql() (string, []interface{}) {
@@ -226,6 +435,55 @@ func (b InsertBuilder) MustSql() (string, []interface{}) {
 	return sql, args
 }
 
+// When calls fn with b and returns its result if cond is true, and returns b
+// otherwise, to add optional clauses without breaking the chain:
+//     Insert("users").Columns("name").Values(name).
+//         When(skipDuplicates, func(b InsertBuilder) InsertBuilder { return b.Ignore() })
+func (b InsertBuilder) When(cond bool, fn func(InsertBuilder) InsertBuilder) InsertBuilder {
+	if !cond {
+		return b
+	}
+	return fn(b)
+}
+
+// ToSqlInterpolated builds the query into a SQL string with the args inlined
+// as literals.
+//
+// See SelectBuilder.ToSqlInterpolated.
+func (b InsertBuilder) ToSqlInterpolated() (string, error) {
+	return InterpolateSqlizer(b)
+}
+
+// Freeze renders the query once and returns it as a FrozenQuery, which keeps
+// the Runner set by RunWith.
+//
+// See FrozenQuery.
+func (b InsertBuilder) Freeze() FrozenQuery {
+	data := b.build()
+	q := Freeze(&data)
+	q.runWith = data.RunWith
+	return q
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query, e.g.
+// Pragma("TablePathPrefix", "/Root/db"). An empty value omits the parentheses.
+//
+// Pragmas are only rendered for the outermost query; those set on nested
+// builders are ignored.
+func (b InsertBuilder) Pragma(name, value string) InsertBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b InsertBuilder) Comment(comment string) InsertBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b InsertBuilder) Prefix(sql string, args ...interface{}) InsertBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -233,27 +491,59 @@ func (b InsertBuilder) Prefix(sql string, args ...interface{}) InsertBuilder {
 
 // PrefixExpr adds an expression to the very beginning of the query
 func (b InsertBuilder) PrefixExpr(expr Sqlizer) InsertBuilder {
-	return builder.Append(b, "Prefixes", expr).(InsertBuilder)
+	b.data.Prefixes = appendSqlizers(b.data.Prefixes, expr)
+	return b
+}
+
+// WithNamed adds a YQL named expression, "$name = expr;", in front of the
+// query.
+//
+// See SelectBuilder.WithNamed.
+func (b InsertBuilder) WithNamed(name string, expr Sqlizer) InsertBuilder {
+	return b.PrefixExpr(yqlNamedExpr{name: name, expr: expr, terminated: true})
 }
 
 // Options adds keyword options before the INTO clause of the query.
 func (b InsertBuilder) Options(options ...string) InsertBuilder {
-	return builder.Extend(b, "Options", options).(InsertBuilder)
+	b.data.Options = appendStrings(b.data.Options, options...)
+	return b
 }
 
 // Into sets the INTO clause of the query.
 func (b InsertBuilder) Into(from string) InsertBuilder {
-	return builder.Set(b, "Into", from).(InsertBuilder)
+	b.data.Into = b.qualifyTable(from)
+	return b
 }
 
 // Columns adds insert columns to the query.
 func (b InsertBuilder) Columns(columns ...string) InsertBuilder {
-	return builder.Extend(b, "Columns", columns).(InsertBuilder)
+	b.data.Columns = appendStrings(b.data.Columns, b.quoteColumns(columns)...)
+	return b
 }
 
 // Values adds a single row's values to the query.
 func (b InsertBuilder) Values(values ...interface{}) InsertBuilder {
-	return builder.Append(b, "Values", values).(InsertBuilder)
+	rows := b.data.Values
+	b.data.Values = append(rows[:len(rows):len(rows)], values)
+	return b
+}
+
+// Returning adds RETURNING expressions to the query.
+func (b InsertBuilder) Returning(columns ...string) InsertBuilder {
+	parts := make([]Sqlizer, 0, len(columns))
+	for _, str := range columns {
+		parts = append(parts, newPart(str))
+	}
+	b.data.Returning = appendSqlizers(b.data.Returning, parts...)
+	return b
+}
+
+// ReturningSelect adds a subquery to the RETURNING clause of the query.
+func (b InsertBuilder) ReturningSelect(from SelectBuilder, alias string) InsertBuilder {
+	// Prevent misnumbered parameters in nested selects (#183).
+	from = from.PlaceholderFormat(Question)
+	b.data.Returning = appendSqlizers(b.data.Returning, Alias(from, alias))
+	return b
 }
 
 // Suffix adds an expression to the end of the query
@@ -263,7 +553,8 @@ func (b InsertBuilder) Suffix(sql string, args ...interface{}) InsertBuilder {
 
 // SuffixExpr adds an expression to the end of the query
 func (b InsertBuilder) SuffixExpr(expr Sqlizer) InsertBuilder {
-	return builder.Append(b, "Suffixes", expr).(InsertBuilder)
+	b.data.Suffixes = appendSqlizers(b.data.Suffixes, expr)
+	return b
 }
 
 // SetMap set columns and values for insert builder from a map of column name and value
@@ -281,18 +572,110 @@ func (b InsertBuilder) SetMap(clauses map[string]interface{}) InsertBuilder {
 		vals = append(vals, clauses[col])
 	}
 
-	b = builder.Set(b, "Columns", cols).(InsertBuilder)
-	b = builder.Set(b, "Values", [][]interface{}{vals}).(InsertBuilder)
+	b.data.Columns = b.quoteColumns(cols)
+	b.data.Values = [][]interface{}{vals}
 
 	return b
 }
 
+// SetStruct sets columns and values for the query from the fields of v, a
+// struct or a pointer to one.
+//
+// Columns are named by the "db" tag of each exported field, or by the
+// lowercased field name if it has none; fields tagged `db:"-"` are skipped. A
+// ",omitempty" tag option, SkipZero, OnlyFields and SkipFields leave out more
+// fields. Like SetMap, SetStruct replaces any columns and values set before.
+//
+// Ex:
+//     type User struct {
+//         ID   int64  `db:"id,omitempty"`
+//         Name string `db:"name"`
+//     }
+//     Insert("users").SetStruct(User{Name: "moe"})
+//     // INSERT INTO users (name) VALUES (?)
+//
+// SetStruct panics if v is not a struct.
+func (b InsertBuilder) SetStruct(v interface{}, opts ...StructOption) InsertBuilder {
+	cols, vals := structValues(v, opts)
+	b.data.Columns = b.quoteColumns(cols)
+	b.data.Values = [][]interface{}{vals}
+	return b
+}
+
 // Select set Select clause for insert query
 // If Values and Select are used, then Select has higher priority
 func (b InsertBuilder) Select(sb SelectBuilder) InsertBuilder {
-	return builder.Set(b, "Select", &sb).(InsertBuilder)
+	b.data.Select = &sb
+	return b
+}
+
+// OnDuplicateKeyUpdate adds a MySQL "ON DUPLICATE KEY UPDATE" clause to the
+// query, setting each column in clauses to its value. Columns are sorted for a
+// consistent order.
+//
+// Use InsertedValue to refer to the value that would have been inserted:
+//     Insert("t").Columns("id", "n").Values(1, 2).
+//         OnDuplicateKeyUpdate(map[string]interface{}{"n": InsertedValue("n")})
+//     // INSERT INTO t (id,n) VALUES (?,?) ON DUPLICATE KEY UPDATE n = VALUES(n)
+func (b InsertBuilder) OnDuplicateKeyUpdate(clauses map[string]interface{}) InsertBuilder {
+	updates := b.data.DuplicateUpdates
+	updates = updates[:len(updates):len(updates)]
+	for _, key := range getSortedKeys(clauses) {
+		updates = append(updates, setClause{column: key, value: clauses[key]})
+	}
+	b.data.DuplicateUpdates = updates
+	return b
+}
+
+// InsertedValue returns a "VALUES(column)" expression referring to the value
+// that would have been inserted into column, for use with OnDuplicateKeyUpdate.
+func InsertedValue(column string) Sqlizer {
+	return Expr(fmt.Sprintf("VALUES(%s)", column))
+}
+
+// ConflictKeys sets the columns of the unique key that an Upsert on
+// PostgreSQL or SQLite checks for an existing row. See UpsertBuilder.
+func (b InsertBuilder) ConflictKeys(columns ...string) InsertBuilder {
+	b.data.ConflictKeys = appendStrings(nil, b.quoteColumns(columns)...)
+	return b
+}
+
+// Ignore makes the query skip rows that would violate a unique constraint
+// instead of failing, in the syntax of the Dialect, or of the database
+// inferred from the placeholder format:
+//     Insert("t").Columns("id").Values(1).Ignore()
+//     // INSERT IGNORE INTO t (id) VALUES (?)
+//     Insert("t").Columns("id").Values(1).Ignore().PlaceholderFormat(Dollar)
+//     // INSERT INTO t (id) VALUES ($1) ON CONFLICT DO NOTHING
+//     Insert("t").Columns("id").Values(1).Ignore().PlaceholderFormat(DollarP)
+//     // INSERT OR IGNORE INTO t (id) VALUES ($p1)
+//
+// With the Question format INSERT IGNORE is written; use the SQLite Dialect
+// for SQLite. SQL Server and Oracle have no such syntax, and ToSql returns an
+// error for them; use Merge instead.
+func (b InsertBuilder) Ignore() InsertBuilder {
+	b.data.Ignore = true
+	return b
+}
+
+const insertIgnoreOnConflict = "ON CONFLICT DO NOTHING"
+
+// insertIgnoreSyntax returns the statement keyword, or the ON CONFLICT clause,
+// that makes an INSERT skip conflicting rows on database d, or "" if there is
+// none.
+func insertIgnoreSyntax(d dialectKind) string {
+	switch d {
+	case dialectGeneric, dialectMySQL:
+		return "INSERT IGNORE"
+	case dialectPostgres:
+		return insertIgnoreOnConflict
+	case dialectSQLite, dialectYQL:
+		return "INSERT OR IGNORE"
+	}
+	return ""
 }
 
 func (b InsertBuilder) statementKeyword(keyword string) InsertBuilder {
-	return builder.Set(b, "StatementKeyword", keyword).(InsertBuilder)
+	b.data.StatementKeyword = keyword
+	return b
 }
@@ -110,3 +110,183 @@ func TestInsertBuilderReplace(t *testing.T) {
 
 	assert.Equal(t, expectedSQL, sql)
 }
+
+func TestUpsertBuilder(t *testing.T) {
+	b := Upsert("table").Columns("a", "b").Values(1, 2).Values(3, 4)
+
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "UPSERT INTO table (a,b) VALUES (?,?),(?,?)", sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4}, args)
+}
+
+func TestUpsertBuilderSetMapAndSelect(t *testing.T) {
+	sql, args, err := StatementBuilder.PlaceholderFormat(Dollar).
+		Upsert("table").
+		SetMap(Eq{"a": 1, "b": 2}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPSERT INTO table (a,b) VALUES ($1,$2)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sb := Select("a").From("other").Where(Eq{"b": 3})
+	sql, args, err = Upsert("table").Columns("a").Select(sb).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPSERT INTO table (a) SELECT a FROM other WHERE b = ?", sql)
+	assert.Equal(t, []interface{}{3}, args)
+}
+
+func TestReplaceBuilder(t *testing.T) {
+	sql, args, err := StatementBuilder.PlaceholderFormat(Dollar).
+		Replace("table").
+		SetMap(Eq{"a": 1, "b": 2}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "REPLACE INTO table (a,b) VALUES ($1,$2)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sb := Select("a").From("other")
+	sql, _, err = Replace("table").Columns("a").Select(sb).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "REPLACE INTO table (a) SELECT a FROM other", sql)
+}
+
+func TestInsertBuilderReturning(t *testing.T) {
+	sql, args, err := Insert("a").
+		Columns("b").
+		Values(1).
+		Returning("id", "created_at").
+		ReturningSelect(Select("name").From("c").Where("c.id = ?", 2), "c_name").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSQL := "INSERT INTO a (b) VALUES ($1) RETURNING id, created_at, (SELECT name FROM c WHERE c.id = $2) AS c_name"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, _, err = Upsert("a").Values(1).Returning("id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPSERT INTO a VALUES (?) RETURNING id", sql)
+}
+
+func TestInsertBuilderReturningQuery(t *testing.T) {
+	db := &DBStub{}
+	QueryWith(db, Insert("a").Values(1).Returning("id"))
+	assert.Equal(t, "INSERT INTO a VALUES (?) RETURNING id", db.LastQuerySql)
+}
+
+func TestInsertBuilderOnDuplicateKeyUpdate(t *testing.T) {
+	sql, args, err := Insert("a").
+		Columns("id", "n", "m").
+		Values(1, 2, 3).
+		OnDuplicateKeyUpdate(map[string]interface{}{
+			"n": InsertedValue("n"),
+			"m": Expr("m + ?", 4),
+			"o": 5,
+		}).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSQL := "INSERT INTO a (id,n,m) VALUES (?,?,?) " +
+		"ON DUPLICATE KEY UPDATE m = m + ?, n = VALUES(n), o = ?"
+	assert.Equal(t, expectedSQL, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5}, args)
+}
+
+func TestInsertBuilderIgnore(t *testing.T) {
+	b := Insert("t").Columns("id").Values(1).Ignore()
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT IGNORE INTO t (id) VALUES (?)", sql)
+
+	sql, _, err = b.Suffix("RETURNING id").PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (id) VALUES ($1) ON CONFLICT DO NOTHING RETURNING id", sql)
+
+	sql, _, err = b.PlaceholderFormat(DollarP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT OR IGNORE INTO t (id) VALUES ($p1)", sql)
+
+	_, _, err = b.PlaceholderFormat(AtP).ToSql()
+	assert.EqualError(t, err, "insert builder: Ignore is not supported by this database; use Merge")
+
+	_, _, err = Upsert("t").Columns("id").Values(1).Ignore().ToSql()
+	assert.EqualError(t, err, "insert builder: Ignore can't be used with UPSERT")
+}
+
+func TestInsertBuilderSelectNestedPlaceholders(t *testing.T) {
+	sb := Select("a").From("t1").Where(Eq{"b": 1}).PlaceholderFormat(DollarP)
+	sql, args, err := Insert("t2").
+		Columns("a").
+		Select(sb).
+		Suffix("RETURNING ?", 2).
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "INSERT INTO t2 (a) SELECT a FROM t1 WHERE b = $p1 RETURNING $p2", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestInsertBuilderNestedInSelect(t *testing.T) {
+	ins := Insert("t").Values(1).Returning("id").PlaceholderFormat(Dollar)
+	sql, args, err := Select("*").
+		With("ins", ins).
+		From("ins").
+		Where("id > ?", 2).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "WITH ins AS (INSERT INTO t VALUES ($1) RETURNING id) SELECT * FROM ins WHERE id > $2", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestInsertBuilderSetStruct(t *testing.T) {
+	type user struct {
+		ID   int64  `db:"id,omitempty"`
+		Name string `db:"name"`
+		Age  int    `db:"age"`
+	}
+
+	sql, args, err := Insert("users").SetStruct(user{Name: "moe", Age: 30}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (name,age) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{"moe", 30}, args)
+
+	sql, args, err = Insert("users").SetStruct(&user{ID: 1, Name: "moe"}, SkipZero()).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id,name) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{int64(1), "moe"}, args)
+}
+
+func benchmarkInsertRows(n int) InsertBuilder {
+	b := Insert("events").Columns("id", "kind", "payload", "created_at")
+	for i := 0; i < n; i++ {
+		b = b.Values(i, "click", Expr("JSON(?)", "{}"), Expr("NOW()"))
+	}
+	return b
+}
+
+func BenchmarkInsertBuilder1000Rows(b *testing.B) {
+	ib := benchmarkInsertRows(1000).PlaceholderFormat(Dollar)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = ib.ToSql()
+	}
+}
+
+func TestInsertBuilderWhen(t *testing.T) {
+	ignore := func(b InsertBuilder) InsertBuilder { return b.Ignore() }
+	sql, _, err := Insert("t").Columns("id").Values(1).When(true, ignore).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT IGNORE INTO t (id) VALUES (?)", sql)
+
+	sql, _, err = Insert("t").Columns("id").Values(1).When(false, ignore).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t (id) VALUES (?)", sql)
+}
@@ -0,0 +1,106 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+// queryHook is called before a query is run by an instrumentedRunner. It
+// returns the context to run the query with and a func called with the
+// result of the query.
+type queryHook func(ctx context.Context, op, query string, args []interface{}) (context.Context, func(err error))
+
+// instrumentedRunner calls hook around each query of base. It is the base of
+// TraceRunner and the other runner decorators.
+type instrumentedRunner struct {
+	base BaseRunner
+	hook queryHook
+}
+
+func newInstrumentedRunner(base BaseRunner, hook queryHook) *instrumentedRunner {
+	return &instrumentedRunner{base: wrapRunner(base), hook: hook}
+}
+
+func (r *instrumentedRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.exec(context.Background(), false, query, args)
+}
+
+func (r *instrumentedRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.query(context.Background(), false, query, args)
+}
+
+func (r *instrumentedRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	return r.queryRow(context.Background(), false, query, args)
+}
+
+func (r *instrumentedRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.exec(ctx, true, query, args)
+}
+
+func (r *instrumentedRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.query(ctx, true, query, args)
+}
+
+func (r *instrumentedRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	return r.queryRow(ctx, true, query, args)
+}
+
+// exec, query and queryRow run the query with the context returned by hook if
+// base supports it. Without context support, they fall back to the plain
+// methods of base unless the caller passed its own context.
+func (r *instrumentedRunner) exec(ctx context.Context, withCtx bool, query string, args []interface{}) (res sql.Result, err error) {
+	ctx, done := r.hook(ctx, "Exec", query, args)
+	defer func() { done(err) }()
+
+	if ctxRunner, ok := r.base.(ExecerContext); ok {
+		return ctxRunner.ExecContext(ctx, query, args...)
+	}
+	if withCtx {
+		return nil, NoContextSupport
+	}
+	return r.base.Exec(query, args...)
+}
+
+func (r *instrumentedRunner) query(ctx context.Context, withCtx bool, query string, args []interface{}) (rows *sql.Rows, err error) {
+	ctx, done := r.hook(ctx, "Query", query, args)
+	defer func() { done(err) }()
+
+	if ctxRunner, ok := r.base.(QueryerContext); ok {
+		return ctxRunner.QueryContext(ctx, query, args...)
+	}
+	if withCtx {
+		return nil, NoContextSupport
+	}
+	return r.base.Query(query, args...)
+}
+
+func (r *instrumentedRunner) queryRow(ctx context.Context, withCtx bool, query string, args []interface{}) RowScanner {
+	ctx, done := r.hook(ctx, "QueryRow", query, args)
+
+	var row RowScanner
+	if ctxRunner, ok := r.base.(QueryRowerContext); ok {
+		row = ctxRunner.QueryRowContext(ctx, query, args...)
+	} else if queryRower, ok := r.base.(QueryRower); !ok {
+		row = &Row{err: RunnerNotQueryRunner}
+	} else if withCtx {
+		row = &Row{err: NoContextSupport}
+	} else {
+		row = queryRower.QueryRow(query, args...)
+	}
+	// the error of a QueryRow is only known when it is scanned
+	return &hookedRow{RowScanner: row, done: done}
+}
+
+// hookedRow reports the result of Scan to a queryHook.
+type hookedRow struct {
+	RowScanner
+	done func(err error)
+}
+
+func (r *hookedRow) Scan(dest ...interface{}) error {
+	err := r.RowScanner.Scan(dest...)
+	r.done(err)
+	return err
+}
@@ -0,0 +1,244 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InterpolateSqlizer calls ToSql on s and returns the SQL with its args
+// inlined as literals, for backends or proxies that don't support
+// placeholders.
+//
+// Unlike DebugSqlizer, it is meant for executing the result: placeholders in
+// quoted strings and comments are left alone, and it returns an error rather
+// than guess when an arg can't be written safely. The dialect of the literals
+// is inferred from the PlaceholderFormat as in DebugSqlizer. Supported args
+// are nil, bools, numbers, strings, []byte, time.Time and driver.Valuers
+// returning one of those.
+//
+// With the default Question format the server isn't known, so strings
+// containing a backslash are rejected: MySQL would treat it as an escape
+// character, while standard SQL does not. They are rejected for the MySQL
+// Dialect too, as its NO_BACKSLASH_ESCAPES mode makes that differ from
+// server to server; quotes are always doubled. Postgres is assumed to run with
+// standard_conforming_strings on, the default since 9.1.
+//
+// Strings are written as N'...' for SQL Server. Times keep their offset where
+// the literal has one (PostgreSQL and Oracle), and are written in UTC
+// otherwise, as drivers bind them.
+func InterpolateSqlizer(s Sqlizer) (string, error) {
+	sql, args, err := s.ToSql()
+	if err != nil {
+		return "", err
+	}
+	format, placeholder := sqlizerPlaceholder(s)
+	dialect := dialectKindOf(format)
+	ip := interpolator{
+		placeholder:      placeholder,
+		quoted:           true,
+		backslashEscapes: dialect == dialectYQL,
+		literal: func(v interface{}) (string, error) {
+			return interpolatedLiteral(dialect, v)
+		},
+	}
+	return ip.interpolate(sql, args)
+}
+
+// sqlizerPlaceholder returns the PlaceholderFormat of s and the placeholder
+// it leaves in the SQL, e.g. "$" for Dollar.
+func sqlizerPlaceholder(s Sqlizer) (PlaceholderFormat, string) {
+	var format PlaceholderFormat = Question
+	if pf, ok := s.(placeholderFormatter); ok && pf.placeholderFormat() != nil {
+		format = pf.placeholderFormat()
+	}
+
+	placeholder := "?"
+	if downCast, ok := s.(placeholderDebugger); ok {
+		placeholder = downCast.debugPlaceholder()
+	} else if downCast, ok := format.(placeholderDebugger); ok {
+		placeholder = downCast.debugPlaceholder()
+	}
+	return format, placeholder
+}
+
+// interpolator writes args as literals in place of their placeholders.
+type interpolator struct {
+	placeholder string
+	// quoted skips placeholders in quoted strings, identifiers and comments
+	quoted bool
+	// backslashEscapes tells that a backslash escapes a quote in strings
+	backslashEscapes bool
+	literal          func(v interface{}) (string, error)
+}
+
+func (ip interpolator) interpolate(sql string, args []interface{}) (string, error) {
+	buf := &strings.Builder{}
+	buf.Grow(len(sql) + len(args)*8)
+
+	used := 0
+	seen := make([]bool, len(args))
+	// rest is where the SQL after the last placeholder starts
+	rest := 0
+	for i := 0; i < len(sql); {
+		if ip.quoted {
+			if end := ip.skipQuoted(sql, i); end != i {
+				if end == -1 {
+					return "", fmt.Errorf("unterminated quoted string or comment in %#v", sql[i:])
+				}
+				buf.WriteString(sql[i:end])
+				i = end
+				continue
+			}
+		}
+		if !strings.HasPrefix(sql[i:], ip.placeholder) {
+			buf.WriteByte(sql[i])
+			i++
+			continue
+		}
+
+		p := i
+		end := p + len(ip.placeholder)
+		var n int
+		if ip.placeholder == "?" {
+			if end < len(sql) && sql[end] == '?' { // escape ?? => ?
+				buf.WriteByte('?')
+				i = end + 1
+				continue
+			}
+			n = used
+		} else {
+			// numbered (e.g. $1) or named (e.g. @name) placeholder
+			j := end
+			for j < len(sql) && isNameByte(sql[j], false) {
+				j++
+			}
+			ref := sql[end:j]
+			n = -1
+			if isDigits(ref) {
+				n, _ = strconv.Atoi(ref)
+				n--
+			} else if ref != "" && isNameByte(ref[0], true) {
+				n = debugNamedArg(args, ref)
+			}
+			if n == -1 && !isDigits(ref) {
+				// not a placeholder, e.g. the cast in "a::text"
+				buf.WriteString(sql[p:j])
+				i = j
+				continue
+			}
+			end = j
+		}
+
+		if n < 0 || n >= len(args) {
+			return "", fmt.Errorf("too many placeholders in %#v for %d args", sql[p:], len(args))
+		}
+		lit, err := ip.literal(args[n])
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(lit)
+		if !seen[n] {
+			seen[n] = true
+			used++
+		}
+		i = end
+		rest = end
+	}
+	if used < len(args) {
+		return "", fmt.Errorf("not enough placeholders in %#v for %d args", sql[rest:], len(args))
+	}
+	return buf.String(), nil
+}
+
+// skipQuoted returns the end of the quoted string, quoted identifier or
+// comment starting at sql[i], i if there is none, or -1 if it is not
+// terminated.
+func (ip interpolator) skipQuoted(sql string, i int) int {
+	switch c := sql[i]; {
+	case c == '\'' || c == '"' || c == '`':
+		for j := i + 1; j < len(sql); j++ {
+			if sql[j] == '\\' && ip.backslashEscapes {
+				j++
+			} else if sql[j] == c {
+				// a doubled quote escapes itself and is skipped as an empty
+				// string on the next call
+				return j + 1
+			}
+		}
+		return -1
+	case strings.HasPrefix(sql[i:], "--"):
+		if end := strings.IndexByte(sql[i:], '\n'); end != -1 {
+			return i + end + 1
+		}
+		return len(sql)
+	case strings.HasPrefix(sql[i:], "/*"):
+		if end := strings.Index(sql[i+2:], "*/"); end != -1 {
+			return i + 2 + end + 2
+		}
+		return -1
+	}
+	return i
+}
+
+// interpolatedLiteral renders v as a SQL literal of dialect d, or returns an
+// error if it can't be written safely.
+func interpolatedLiteral(d dialectKind, v interface{}) (string, error) {
+	if named, ok := v.(sql.NamedArg); ok {
+		v = named.Value
+	}
+	if valuer, ok := v.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		v = value
+	}
+
+	switch v := v.(type) {
+	case nil, bool, []byte, time.Time:
+		return debugLiteral(d, v), nil
+	case string:
+		return interpolatedString(d, v)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return "", fmt.Errorf("cannot interpolate %v", f)
+		}
+		return strconv.FormatFloat(f, 'g', -1, rv.Type().Bits()), nil
+	case reflect.Bool:
+		return debugLiteral(d, rv.Bool()), nil
+	case reflect.String:
+		return interpolatedString(d, rv.String())
+	}
+	return "", fmt.Errorf("cannot interpolate arg of type %T", v)
+}
+
+func interpolatedString(d dialectKind, s string) (string, error) {
+	if strings.IndexByte(s, 0) != -1 {
+		return "", errors.New("cannot interpolate a string containing a NUL byte")
+	}
+	if strings.IndexByte(s, '\\') != -1 {
+		switch d {
+		case dialectGeneric:
+			return "", errors.New("cannot interpolate a string containing a backslash with the Question placeholder format")
+		case dialectMySQL:
+			return "", errors.New("cannot interpolate a string containing a backslash for MySQL")
+		}
+	}
+	return debugText(d, s), nil
+}
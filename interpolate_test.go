@@ -0,0 +1,96 @@
+package squirrel
+
+import (
+	"database/sql"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolateSqlizer(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	b := Insert("t").Values(1, 2.5, "it's", true, nil, []byte("AB"), ts, sql.NullInt64{Int64: 7, Valid: true})
+
+	sqlStr, err := InterpolateSqlizer(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES (1,2.5,'it''s',TRUE,NULL,X'4142','2020-01-02 03:04:05',7)", sqlStr)
+
+	sqlStr, err = b.PlaceholderFormat(Dollar).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO t VALUES (1,2.5,'it''s',TRUE,NULL,'\x4142'::bytea,'2020-01-02 03:04:05+00:00'::timestamptz,7)`, sqlStr)
+
+	sqlStr, err = b.PlaceholderFormat(DollarP).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO t VALUES (1,2.5,'it\'s',TRUE,NULL,'\x41\x42',Timestamp("2020-01-02T03:04:05.000000Z"),7)`, sqlStr)
+}
+
+func TestInterpolateSqlizerTimeZone(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	b := Insert("t").Values(ts)
+
+	sqlStr, err := InterpolateSqlizer(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES ('2020-01-02 02:04:05')", sqlStr)
+
+	sqlStr, err = b.PlaceholderFormat(AtP).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES ('2020-01-02 02:04:05')", sqlStr)
+
+	sqlStr, err = b.PlaceholderFormat(Dollar).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES ('2020-01-02 03:04:05+01:00'::timestamptz)", sqlStr)
+
+	sqlStr, err = b.PlaceholderFormat(Colon).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES (TIMESTAMP '2020-01-02 03:04:05 +01:00')", sqlStr)
+
+	sqlStr, err = b.PlaceholderFormat(DollarP).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO t VALUES (Timestamp("2020-01-02T02:04:05.000000Z"))`, sqlStr)
+}
+
+func TestInterpolateSqlizerSQLServerUnicode(t *testing.T) {
+	sqlStr, err := Select("*").From("t").Where("name = ?", "Zoë Ψ").PlaceholderFormat(AtP).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE name = N'Zoë Ψ'", sqlStr)
+}
+
+func TestInterpolateSqlizerSkipsQuoted(t *testing.T) {
+	b := Select("'?'", `"a?"`).
+		From("t").
+		Where("a = ? /* b = ? */", 1).
+		Where("c = ? -- ?\n", "x")
+
+	sqlStr, err := b.ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT '?', "a?" FROM t WHERE a = 1 /* b = ? */ AND c = 'x' -- ?`+"\n", sqlStr)
+
+	sqlStr, err = Select("*").From("t").Where("a = 'it''s?' AND b = ?", 1).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE a = 'it''s?' AND b = 1", sqlStr)
+
+	sqlStr, err = Select("*").From("t").Where(`a = 'it\'s' AND b = ?`, 1).PlaceholderFormat(DollarP).ToSqlInterpolated()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM t WHERE a = 'it\'s' AND b = 1`, sqlStr)
+}
+
+func TestInterpolateSqlizerErrors(t *testing.T) {
+	tests := map[string]Sqlizer{
+		"cannot interpolate a string containing a backslash with the Question placeholder format": Select("*").Where("a = ?", `\`),
+		"cannot interpolate a string containing a NUL byte":                                       Select("*").Where("a = ?", "\x00"),
+		"cannot interpolate arg of type []int":                                                    Select("*").Where("a = ?", []int{1}),
+		"cannot interpolate NaN":                                                                  Select("*").Where("a = ?", math.NaN()),
+		`unterminated quoted string or comment in "'a = ?"`:                                       Select("*").Where("'a = ?", 1),
+	}
+	for msg, s := range tests {
+		_, err := InterpolateSqlizer(s)
+		assert.EqualError(t, err, msg)
+	}
+
+	_, err := Select("*").From("t").Where(`a = ?`, `\`).PlaceholderFormat(Dollar).ToSqlInterpolated()
+	assert.NoError(t, err)
+	_, err = Select("*").From("t").Where("a = ?", 1).Suffix("AND b = $2").PlaceholderFormat(Dollar).ToSqlInterpolated()
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package squirrel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a value inside a JSON document, see JSONGet.
+type jsonPath struct {
+	doc     string
+	keys    []string
+	dialect dialectKind
+}
+
+// JSONGet is the text value at keys inside the JSON document column doc, for
+// use as a column or in a condition.
+//
+// Builders write it for the database of their placeholder format:
+//     JSONGet("doc", "a", "b")
+//     // PostgreSQL (Dollar): doc->'a'->>'b'
+//     // YQL (DollarP):       JSON_VALUE(doc, "$.a.b")
+//     // others:              JSON_VALUE(doc, '$.a.b')
+//
+// Ex:
+//     Select("id").Column(Alias(JSONGet("profile", "address", "city"), "city")).
+//         From("users").
+//         Where(JSONGet("profile", "lang").Eq("en"))
+func JSONGet(doc string, keys ...string) jsonPath {
+	return jsonPath{doc: doc, keys: keys}
+}
+
+func (p jsonPath) forDialect(d dialectKind, _ bool) Sqlizer {
+	p.dialect = d
+	return p
+}
+
+func (p jsonPath) ToSql() (sql string, args []interface{}, err error) {
+	if len(p.keys) == 0 {
+		err = fmt.Errorf("JSONGet on %s requires at least one key", p.doc)
+		return
+	}
+
+	if p.dialect == dialectPostgres {
+		buf := &strings.Builder{}
+		buf.WriteString(p.doc)
+		for i, key := range p.keys {
+			if i == len(p.keys)-1 {
+				buf.WriteString("->>")
+			} else {
+				buf.WriteString("->")
+			}
+			buf.WriteString("'" + strings.Replace(key, "'", "''", -1) + "'")
+		}
+		sql = buf.String()
+		return
+	}
+
+	sql = fmt.Sprintf("JSON_VALUE(%s, %s)", p.doc, jsonPathLiteral(p.dialect, p.keys))
+	return
+}
+
+// Eq is the condition that the value at p equals value, or IS NULL if value
+// is nil.
+func (p jsonPath) Eq(value interface{}) Sqlizer {
+	return jsonCond{path: p, value: value}
+}
+
+// NotEq is the condition that the value at p doesn't equal value, or IS NOT
+// NULL if value is nil.
+func (p jsonPath) NotEq(value interface{}) Sqlizer {
+	return jsonCond{path: p, value: value, not: true}
+}
+
+// jsonCond compares the value at a JSON path
+type jsonCond struct {
+	path  jsonPath
+	value interface{}
+	not   bool
+}
+
+func (c jsonCond) forDialect(d dialectKind, _ bool) Sqlizer {
+	c.path.dialect = d
+	return c
+}
+
+func (c jsonCond) ToSql() (sql string, args []interface{}, err error) {
+	sql, _, err = c.path.ToSql()
+	if err != nil {
+		return
+	}
+
+	// Eq handles nil, Valuers and lists
+	if c.not {
+		return NotEq{sql: c.value}.ToSql()
+	}
+	return Eq{sql: c.value}.ToSql()
+}
+
+type jsonHasKey struct {
+	doc     string
+	key     string
+	dialect dialectKind
+}
+
+// JSONHasKey is the condition that the JSON document column doc has the
+// top-level key.
+//
+// On PostgreSQL (Dollar) it is written with the ? operator, escaped as ?? so
+// that it isn't taken for a placeholder:
+//     doc ? $1
+// On other databases it is written as JSON_EXISTS(doc, '$.key').
+func JSONHasKey(doc, key string) Sqlizer {
+	return jsonHasKey{doc: doc, key: key}
+}
+
+func (h jsonHasKey) forDialect(d dialectKind, _ bool) Sqlizer {
+	h.dialect = d
+	return h
+}
+
+func (h jsonHasKey) ToSql() (sql string, args []interface{}, err error) {
+	if h.dialect == dialectPostgres {
+		return h.doc + " ?? ?", []interface{}{h.key}, nil
+	}
+	sql = fmt.Sprintf("JSON_EXISTS(%s, %s)", h.doc, jsonPathLiteral(h.dialect, []string{h.key}))
+	return
+}
+
+// jsonPathLiteral writes keys as a quoted SQL/JSON path, e.g. '$.a.b'.
+func jsonPathLiteral(d dialectKind, keys []string) string {
+	buf := &strings.Builder{}
+	buf.WriteByte('$')
+	for _, key := range keys {
+		buf.WriteByte('.')
+		if isJSONPathName(key) {
+			buf.WriteString(key)
+		} else {
+			buf.WriteString(strconv.Quote(key))
+		}
+	}
+	if d == dialectYQL {
+		return strconv.Quote(buf.String())
+	}
+	return "'" + strings.Replace(buf.String(), "'", "''", -1) + "'"
+}
+
+// isJSONPathName tells whether key can be written in a JSON path unquoted.
+func isJSONPathName(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if !isNameByte(key[i], i == 0) {
+			return false
+		}
+	}
+	return key != ""
+}
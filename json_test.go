@@ -0,0 +1,69 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONGet(t *testing.T) {
+	b := Select("id").
+		Column(Alias(JSONGet("profile", "address", "city"), "city")).
+		From("users").
+		Where(JSONGet("profile", "lang").Eq("en")).
+		OrderByClause(JSONGet("profile", "it's"))
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, (profile->'address'->>'city') AS city FROM users "+
+		"WHERE profile->>'lang' = $1 ORDER BY profile->>'it''s'", sql)
+	assert.Equal(t, []interface{}{"en"}, args)
+
+	sql, _, err = b.PlaceholderFormat(DollarP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id, (JSON_VALUE(profile, "$.address.city")) AS city FROM users `+
+		`WHERE JSON_VALUE(profile, "$.lang") = $p1 ORDER BY JSON_VALUE(profile, "$.\"it's\"")`, sql)
+
+	sql, _, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, (JSON_VALUE(profile, '$.address.city')) AS city FROM users "+
+		`WHERE JSON_VALUE(profile, '$.lang') = ? ORDER BY JSON_VALUE(profile, '$."it''s"')`, sql)
+
+	_, _, err = JSONGet("profile").ToSql()
+	assert.Error(t, err)
+}
+
+func TestJSONGetCond(t *testing.T) {
+	sql, args, err := Delete("users").
+		Where(Or{JSONGet("doc", "a").NotEq(nil), JSONGet("doc", "b").Eq([]int{1, 2})}).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM users WHERE (doc->>'a' IS NOT NULL OR doc->>'b' IN ($1,$2))", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestJSONHasKey(t *testing.T) {
+	b := Select("id").From("users").Where(JSONHasKey("doc", "a")).Where("id > ?", 1)
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE doc ? $1 AND id > $2", sql)
+	assert.Equal(t, []interface{}{"a", 1}, args)
+
+	sql, args, err = b.PlaceholderFormat(NamedPlaceholders("@")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE JSON_EXISTS(doc, '$.a') AND id > @p1", sql)
+	assert.Len(t, args, 1)
+
+	// a subquery is written for the database of the outer query, and ?? is
+	// unescaped only once
+	sql, _, err = Select("*").
+		FromSelect(b, "u").
+		Where(Expr("x ?? 'k'")).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT id FROM users WHERE doc ? $1 AND id > $2) AS u "+
+		"WHERE x ? 'k'", sql)
+}
@@ -0,0 +1,68 @@
+package squirrel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LockStrength is the strength of a row-locking clause, see
+// SelectBuilder.LockFor.
+type LockStrength string
+
+const (
+	// LockUpdate locks the rows for update: FOR UPDATE.
+	LockUpdate LockStrength = "UPDATE"
+	// LockNoKeyUpdate is the PostgreSQL FOR NO KEY UPDATE.
+	LockNoKeyUpdate LockStrength = "NO KEY UPDATE"
+	// LockShare locks the rows against concurrent updates: FOR SHARE.
+	LockShare LockStrength = "SHARE"
+	// LockKeyShare is the PostgreSQL FOR KEY SHARE.
+	LockKeyShare LockStrength = "KEY SHARE"
+)
+
+// LockOption changes a row-locking clause, see SelectBuilder.LockFor.
+type LockOption func(*lockClause)
+
+// LockOf restricts the lock to the rows of the given tables: OF tables.
+func LockOf(tables ...string) LockOption {
+	return func(l *lockClause) {
+		l.of = append(l.of, tables...)
+	}
+}
+
+// NoWait makes the query fail instead of waiting for locked rows: NOWAIT.
+func NoWait() LockOption {
+	return func(l *lockClause) {
+		l.wait = "NOWAIT"
+	}
+}
+
+// SkipLocked makes the query skip rows that are locked: SKIP LOCKED.
+func SkipLocked() LockOption {
+	return func(l *lockClause) {
+		l.wait = "SKIP LOCKED"
+	}
+}
+
+// lockClause is a "FOR strength [OF tables] [NOWAIT|SKIP LOCKED]" clause
+type lockClause struct {
+	strength LockStrength
+	of       []string
+	wait     string
+}
+
+func (l lockClause) ToSql() (sql string, args []interface{}, err error) {
+	if l.strength == "" {
+		err = fmt.Errorf("lock strength must not be empty")
+		return
+	}
+
+	sql = "FOR " + string(l.strength)
+	if len(l.of) > 0 {
+		sql += " OF " + strings.Join(l.of, ", ")
+	}
+	if l.wait != "" {
+		sql += " " + l.wait
+	}
+	return
+}
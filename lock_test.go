@@ -0,0 +1,31 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderLockFor(t *testing.T) {
+	b := Select("*").From("jobs j").Join("queues q USING (queue_id)").Where(Eq{"state": "queued"}).Limit(10)
+
+	sql, args, err := b.LockFor(LockUpdate).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM jobs j JOIN queues q USING (queue_id) WHERE state = ? LIMIT 10 FOR UPDATE", sql)
+	assert.Equal(t, []interface{}{"queued"}, args)
+
+	sql, _, err = b.LockFor(LockUpdate, LockOf("j"), SkipLocked()).
+		LockFor(LockKeyShare, LockOf("q"), NoWait()).
+		Suffix("-- worker").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM jobs j JOIN queues q USING (queue_id) WHERE state = ? LIMIT 10 "+
+		"FOR UPDATE OF j SKIP LOCKED FOR KEY SHARE OF q NOWAIT -- worker", sql)
+
+	sql, _, err = CountOf(b.LockFor(LockShare)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM jobs j JOIN queues q USING (queue_id) WHERE state = ?", sql)
+
+	_, _, err = b.LockFor("").ToSql()
+	assert.Error(t, err)
+}
@@ -0,0 +1,72 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// QueryLogEntry describes a query run by a LogRunner.
+type QueryLogEntry struct {
+	// Operation is the runner method: "Exec", "Query" or "QueryRow".
+	Operation string
+	// Statement is the generated SQL.
+	Statement string
+	// Args are the bound args. Use ArgSummary to log them without values.
+	Args []interface{}
+	// Duration is the time the query took. For a QueryRow it includes the
+	// Scan of the row.
+	Duration time.Duration
+	// Err is the error of the query, or nil.
+	Err error
+}
+
+// ArgSummary describes Args by type only, e.g. "[int64, string, <nil>]", so
+// that logs don't leak bound values.
+func (e QueryLogEntry) ArgSummary() string {
+	types := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		types[i] = fmt.Sprintf("%T", arg)
+	}
+	return "[" + strings.Join(types, ", ") + "]"
+}
+
+// QueryLogger receives an entry for every query run by a LogRunner.
+type QueryLogger interface {
+	LogQuery(ctx context.Context, entry QueryLogEntry)
+}
+
+// QueryLoggerFunc is an adapter to allow the use of an ordinary function as a
+// QueryLogger.
+//
+// Ex:
+//     logger := sq.QueryLoggerFunc(func(ctx context.Context, e sq.QueryLogEntry) {
+//         log.Printf("%s %q args=%s took=%s err=%v", e.Operation, e.Statement, e.ArgSummary(), e.Duration, e.Err)
+//     })
+type QueryLoggerFunc func(ctx context.Context, entry QueryLogEntry)
+
+// LogQuery calls f(ctx, entry).
+func (f QueryLoggerFunc) LogQuery(ctx context.Context, entry QueryLogEntry) {
+	f(ctx, entry)
+}
+
+// LogRunner returns a runner that logs each Exec, Query and QueryRow of base
+// with logger once the query is done. The entry of a QueryRow is logged when
+// the returned row is scanned.
+func LogRunner(base BaseRunner, logger QueryLogger) RunnerContext {
+	return newInstrumentedRunner(base, func(ctx context.Context, op, query string, args []interface{}) (context.Context, func(err error)) {
+		start := time.Now()
+		return ctx, func(err error) {
+			logger.LogQuery(ctx, QueryLogEntry{
+				Operation: op,
+				Statement: query,
+				Args:      args,
+				Duration:  time.Since(start),
+				Err:       err,
+			})
+		}
+	})
+}
@@ -0,0 +1,50 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogRunner(t *testing.T) {
+	var entries []QueryLogEntry
+	logger := QueryLoggerFunc(func(ctx context.Context, e QueryLogEntry) {
+		entries = append(entries, e)
+	})
+	runner := LogRunner(&DBStub{}, logger)
+
+	_, err := Insert("a").Columns("b", "c").Values(1, "x").RunWith(runner).Exec()
+	assert.NoError(t, err)
+	err = Select("a").From("b").RunWith(runner).QueryRowContext(ctx).Scan()
+	assert.NoError(t, err)
+
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "Exec", entries[0].Operation)
+	assert.Equal(t, "INSERT INTO a (b,c) VALUES (?,?)", entries[0].Statement)
+	assert.Equal(t, []interface{}{1, "x"}, entries[0].Args)
+	assert.Nil(t, entries[0].Err)
+	assert.Equal(t, "QueryRow", entries[1].Operation)
+	assert.True(t, entries[1].Duration >= 0)
+}
+
+func TestLogRunnerError(t *testing.T) {
+	var entry QueryLogEntry
+	logger := QueryLoggerFunc(func(ctx context.Context, e QueryLogEntry) {
+		entry = e
+	})
+	runner := LogRunner(baseRunnerStub{err: errors.New("boom")}, logger)
+
+	_, err := runner.Query("SELECT 1")
+	assert.EqualError(t, err, "boom")
+	assert.EqualError(t, entry.Err, "boom")
+}
+
+func TestQueryLogEntryArgSummary(t *testing.T) {
+	e := QueryLogEntry{Args: []interface{}{int64(1), "secret", nil}}
+	assert.Equal(t, "[int64, string, <nil>]", e.ArgSummary())
+	assert.Equal(t, "[]", QueryLogEntry{}.ArgSummary())
+}
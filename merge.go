@@ -0,0 +1,309 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"sort"
+	"strings"
+)
+
+// mergeWhen is a "WHEN [NOT] MATCHED [AND cond] THEN action" clause of a MERGE
+type mergeWhen struct {
+	matched bool
+	cond    Sqlizer
+	// action is UPDATE, DELETE or INSERT
+	action  string
+	set     []setClause
+	columns []string
+	values  []interface{}
+}
+
+func (w mergeWhen) appendToSql(sql *bytes.Buffer, args []interface{}) ([]interface{}, error) {
+	if w.matched {
+		sql.WriteString(" WHEN MATCHED")
+	} else {
+		sql.WriteString(" WHEN NOT MATCHED")
+	}
+	if w.cond != nil {
+		sql.WriteString(" AND ")
+		var err error
+		args, err = appendToSql([]Sqlizer{w.cond}, sql, "", args)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sql.WriteString(" THEN ")
+
+	switch w.action {
+	case "UPDATE":
+		sql.WriteString("UPDATE SET ")
+		return appendSetClauses(w.set, sql, args)
+	case "INSERT":
+		sql.WriteString("INSERT ")
+		if len(w.columns) > 0 {
+			sql.WriteString("(")
+			sql.WriteString(strings.Join(w.columns, ", "))
+			sql.WriteString(") ")
+		}
+		insert := insertData{Values: [][]interface{}{w.values}}
+		return insert.appendValuesToSQL(sql, args)
+	default:
+		sql.WriteString(w.action)
+		return args, nil
+	}
+}
+
+type mergeData struct {
+	PlaceholderFormat PlaceholderFormat
+	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
+	Into              string
+	Using             Sqlizer
+	On                Sqlizer
+	Whens             []mergeWhen
+	Suffixes          []Sqlizer
+}
+
+func (d *mergeData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Into) == 0 {
+		err = builderError("merge", "no target table (Into)")
+		return
+	}
+	if d.Using == nil {
+		err = builderError("merge", "no source (Using)")
+		return
+	}
+	if d.On == nil {
+		err = builderError("merge", "no join condition (On)")
+		return
+	}
+	if len(d.Whens) == 0 {
+		err = builderError("merge", "no WHEN clauses")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	sql.WriteString("MERGE INTO ")
+	sql.WriteString(d.Into)
+
+	sql.WriteString(" USING ")
+	args, err = appendToSql([]Sqlizer{d.Using}, sql, "", args)
+	if err != nil {
+		err = clauseError("merge", "USING", err)
+		return
+	}
+
+	sql.WriteString(" ON ")
+	args, err = appendToSql([]Sqlizer{d.On}, sql, "", args)
+	if err != nil {
+		err = clauseError("merge", "ON", err)
+		return
+	}
+
+	for _, w := range d.Whens {
+		args, err = w.appendToSql(sql, args)
+		if err != nil {
+			err = clauseError("merge", "WHEN", err)
+			return
+		}
+	}
+
+	if len(d.Suffixes) > 0 {
+		sql.WriteString(" ")
+		args, err = appendToSql(d.Suffixes, sql, " ", args)
+		if err != nil {
+			err = clauseError("merge", "suffix", err)
+			return
+		}
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sql.String(), args)
+	if err != nil {
+		return
+	}
+	sqlStr = commentsToSql(d.Comments) + pragmasToSql(d.Pragmas) + sqlStr
+	return
+}
+
+// MergeBuilder builds SQL MERGE statements, as supported by SQL Server, Oracle
+// and PostgreSQL 15+.
+//
+// Ex:
+//     Merge("stock s").
+//         UsingSelect(Select("item_id", "qty").From("deliveries").Where("day = ?", day), "d").
+//         On("s.item_id = d.item_id").
+//         WhenMatchedThenUpdate(map[string]interface{}{"qty": Expr("s.qty + d.qty")}).
+//         WhenNotMatchedThenInsert([]string{"item_id", "qty"}, Expr("d.item_id"), Expr("d.qty"))
+//     // MERGE INTO stock s USING (SELECT item_id, qty FROM deliveries WHERE day = ?) AS d
+//     // ON s.item_id = d.item_id
+//     // WHEN MATCHED THEN UPDATE SET qty = s.qty + d.qty
+//     // WHEN NOT MATCHED THEN INSERT (item_id, qty) VALUES (d.item_id,d.qty)
+//
+// SQL Server requires a MERGE statement to end with a semicolon; add it with
+// Suffix(";").
+type MergeBuilder struct {
+	data mergeData
+	builderOptions
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b MergeBuilder) PlaceholderFormat(f PlaceholderFormat) MergeBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b MergeBuilder) RunWith(runner BaseRunner) MergeBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b MergeBuilder) Exec() (sql.Result, error) {
+	data := b.data
+	if data.RunWith == nil {
+		return nil, RunnerNotSet
+	}
+	return ExecWith(data.RunWith, &data)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b MergeBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b MergeBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// When calls fn with b and returns its result if cond is true, and returns b
+// otherwise. See SelectBuilder.When.
+func (b MergeBuilder) When(cond bool, fn func(MergeBuilder) MergeBuilder) MergeBuilder {
+	if !cond {
+		return b
+	}
+	return fn(b)
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query.
+//
+// See SelectBuilder.Pragma.
+func (b MergeBuilder) Pragma(name, value string) MergeBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b MergeBuilder) Comment(comment string) MergeBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
+// Into sets the target table of the query.
+func (b MergeBuilder) Into(target string) MergeBuilder {
+	b.data.Into = b.qualifyTable(target)
+	return b
+}
+
+// Using sets the source table of the query.
+func (b MergeBuilder) Using(source string) MergeBuilder {
+	b.data.Using = newPart(b.qualifyTable(source))
+	return b
+}
+
+// UsingSelect sets a subquery as the source of the query.
+func (b MergeBuilder) UsingSelect(source SelectBuilder, alias string) MergeBuilder {
+	// Prevent misnumbered parameters in nested selects (#183).
+	source = source.PlaceholderFormat(Question)
+	b.data.Using = Alias(source, alias)
+	return b
+}
+
+// UsingValues sets a VALUES list of literal rows as the source of the query.
+// values should be named with ValuesBuilder.As.
+func (b MergeBuilder) UsingValues(values ValuesBuilder) MergeBuilder {
+	b.data.Using = values
+	return b
+}
+
+// On sets the condition matching source rows to target rows.
+//
+// See SelectBuilder.Where for the accepted types of pred.
+func (b MergeBuilder) On(pred interface{}, args ...interface{}) MergeBuilder {
+	b.data.On = newWherePart(pred, args...)
+	return b
+}
+
+// WhenMatchedThenUpdate adds a "WHEN MATCHED THEN UPDATE SET ..." clause,
+// setting the columns of set in sorted order, as UpdateBuilder.SetMap does.
+func (b MergeBuilder) WhenMatchedThenUpdate(set map[string]interface{}) MergeBuilder {
+	return b.WhenMatchedAndThenUpdate(nil, set)
+}
+
+// WhenMatchedAndThenUpdate adds a "WHEN MATCHED AND cond THEN UPDATE SET ..."
+// clause.
+func (b MergeBuilder) WhenMatchedAndThenUpdate(cond Sqlizer, set map[string]interface{}) MergeBuilder {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	clauses := make([]setClause, len(keys))
+	for i, key := range keys {
+		clauses[i] = setClause{column: key, value: set[key]}
+	}
+	return b.when(mergeWhen{matched: true, cond: cond, action: "UPDATE", set: clauses})
+}
+
+// WhenMatchedThenDelete adds a "WHEN MATCHED THEN DELETE" clause.
+func (b MergeBuilder) WhenMatchedThenDelete() MergeBuilder {
+	return b.WhenMatchedAndThenDelete(nil)
+}
+
+// WhenMatchedAndThenDelete adds a "WHEN MATCHED AND cond THEN DELETE" clause.
+func (b MergeBuilder) WhenMatchedAndThenDelete(cond Sqlizer) MergeBuilder {
+	return b.when(mergeWhen{matched: true, cond: cond, action: "DELETE"})
+}
+
+// WhenNotMatchedThenInsert adds a "WHEN NOT MATCHED THEN INSERT (columns)
+// VALUES (values)" clause. Sqlizer values, e.g. Expr("src.id"), are written in
+// place, others are bound as args.
+func (b MergeBuilder) WhenNotMatchedThenInsert(columns []string, values ...interface{}) MergeBuilder {
+	return b.WhenNotMatchedAndThenInsert(nil, columns, values...)
+}
+
+// WhenNotMatchedAndThenInsert adds a "WHEN NOT MATCHED AND cond THEN INSERT"
+// clause.
+func (b MergeBuilder) WhenNotMatchedAndThenInsert(cond Sqlizer, columns []string, values ...interface{}) MergeBuilder {
+	return b.when(mergeWhen{cond: cond, action: "INSERT", columns: columns, values: values})
+}
+
+func (b MergeBuilder) when(w mergeWhen) MergeBuilder {
+	whens := b.data.Whens
+	b.data.Whens = append(whens[:len(whens):len(whens)], w)
+	return b
+}
+
+// Suffix adds an expression to the end of the query
+func (b MergeBuilder) Suffix(sql string, args ...interface{}) MergeBuilder {
+	return b.SuffixExpr(Expr(sql, args...))
+}
+
+// SuffixExpr adds an expression to the end of the query
+func (b MergeBuilder) SuffixExpr(expr Sqlizer) MergeBuilder {
+	b.data.Suffixes = appendSqlizers(b.data.Suffixes, expr)
+	return b
+}
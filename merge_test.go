@@ -0,0 +1,79 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeBuilderToSql(t *testing.T) {
+	sql, args, err := Merge("stock s").
+		UsingSelect(Select("item_id", "qty").From("deliveries").Where("day = ?", 3).PlaceholderFormat(Dollar), "d").
+		On("s.item_id = d.item_id AND s.store = ?", "north").
+		WhenMatchedAndThenDelete(Expr("s.qty + d.qty <= ?", 0)).
+		WhenMatchedThenUpdate(map[string]interface{}{"qty": Expr("s.qty + d.qty"), "note": "restocked"}).
+		WhenNotMatchedThenInsert([]string{"item_id", "qty", "store"}, Expr("d.item_id"), Expr("d.qty"), "north").
+		Suffix(";").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "MERGE INTO stock s " +
+		"USING (SELECT item_id, qty FROM deliveries WHERE day = $1) AS d " +
+		"ON s.item_id = d.item_id AND s.store = $2 " +
+		"WHEN MATCHED AND s.qty + d.qty <= $3 THEN DELETE " +
+		"WHEN MATCHED THEN UPDATE SET note = $4, qty = s.qty + d.qty " +
+		"WHEN NOT MATCHED THEN INSERT (item_id, qty, store) VALUES (d.item_id,d.qty,$5) ;"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{3, "north", 0, "restocked", "north"}, args)
+}
+
+func TestMergeBuilderUsing(t *testing.T) {
+	sql, args, err := StatementBuilder.TablePathPrefix("/Root").
+		Merge("a").
+		Using("b").
+		On("a.id = b.id").
+		WhenNotMatchedAndThenInsert(Expr("b.ok"), []string{"id"}, Expr("b.id")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO `/Root/a` USING `/Root/b` ON a.id = b.id "+
+		"WHEN NOT MATCHED AND b.ok THEN INSERT (id) VALUES (b.id)", sql)
+	assert.Empty(t, args)
+
+	v := Values().Row(1, "x").As("v", "id", "name")
+	sql, args, err = Merge("t").UsingValues(v).On("t.id = v.id").
+		WhenMatchedThenUpdate(map[string]interface{}{"name": Expr("v.name")}).
+		PlaceholderFormat(AtP).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO t USING (VALUES (@p1,@p2)) AS v (id, name) ON t.id = v.id "+
+		"WHEN MATCHED THEN UPDATE SET name = v.name", sql)
+	assert.Equal(t, []interface{}{1, "x"}, args)
+}
+
+func TestMergeBuilderToSqlErr(t *testing.T) {
+	b := Merge("t").Using("s").On("t.id = s.id").WhenMatchedThenDelete()
+
+	_, _, err := b.ToSql()
+	assert.NoError(t, err)
+
+	_, _, err = Merge("").Using("s").On("t.id = s.id").WhenMatchedThenDelete().ToSql()
+	assert.Error(t, err)
+	_, _, err = Merge("t").On("t.id = s.id").WhenMatchedThenDelete().ToSql()
+	assert.Error(t, err)
+	_, _, err = Merge("t").Using("s").WhenMatchedThenDelete().ToSql()
+	assert.Error(t, err)
+	_, _, err = Merge("t").Using("s").On("t.id = s.id").ToSql()
+	assert.Error(t, err)
+	assert.Panics(t, func() { Merge("t").MustSql() })
+}
+
+func TestMergeBuilderRunners(t *testing.T) {
+	db := &DBStub{}
+	_, err := Merge("t").Using("s").On("t.id = s.id").WhenMatchedThenDelete().RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN DELETE", db.LastExecSql)
+
+	_, err = Merge("t").Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
@@ -0,0 +1,142 @@
+package squirrel
+
+import (
+	"strings"
+)
+
+// StatementKind returns the kind of the SQL statement sql as a short metric
+// label: "select", "insert", "update", "delete", "upsert" or "other".
+//
+// Leading comments and YQL pragmas are skipped, and so is the WITH clause:
+// the kind is that of the statement after it. INSERT statements with an
+// ON CONFLICT ... DO UPDATE, ON DUPLICATE KEY UPDATE or OR REPLACE clause, and
+// UPSERT, REPLACE and MERGE statements, are all "upsert"; INSERT IGNORE and
+// ON CONFLICT DO NOTHING are "insert". Words in quoted strings, comments and
+// parentheses are not looked at.
+func StatementKind(sql string) string {
+	words := statementWords(skipStatementPrelude(sql))
+	if len(words) > 0 && words[0] == "WITH" {
+		// skip the CTEs, whose queries are in parentheses, up to the statement
+		for len(words) > 0 && !isStatementKeyword(words[0]) {
+			words = words[1:]
+		}
+	}
+	if len(words) == 0 {
+		return "other"
+	}
+
+	switch words[0] {
+	case "SELECT", "VALUES":
+		return "select"
+	case "INSERT":
+		return insertKind(words[1:])
+	case "UPSERT", "REPLACE", "MERGE":
+		return "upsert"
+	case "UPDATE":
+		return "update"
+	case "DELETE":
+		return "delete"
+	}
+	return "other"
+}
+
+func isStatementKeyword(word string) bool {
+	switch word {
+	case "SELECT", "VALUES", "INSERT", "UPSERT", "REPLACE", "MERGE", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+// insertKind tells an INSERT that may update existing rows from a plain one,
+// from the words after INSERT.
+func insertKind(words []string) string {
+	if len(words) > 1 && words[0] == "OR" && words[1] == "REPLACE" {
+		return "upsert"
+	}
+	for i := 0; i+1 < len(words); i++ {
+		if words[i] != "ON" {
+			continue
+		}
+		switch words[i+1] {
+		case "DUPLICATE":
+			return "upsert"
+		case "CONFLICT":
+			for j := i + 2; j+1 < len(words); j++ {
+				if words[j] == "DO" {
+					if words[j+1] == "UPDATE" {
+						return "upsert"
+					}
+					break
+				}
+			}
+		}
+	}
+	return "insert"
+}
+
+// statementWords returns the upper-cased words of sql outside of quoted
+// strings, quoted identifiers, comments and parentheses.
+func statementWords(sql string) []string {
+	var words []string
+	depth := 0
+	for i := 0; i < len(sql); {
+		if end := (interpolator{}).skipQuoted(sql, i); end != i {
+			if end == -1 {
+				break
+			}
+			i = end
+			continue
+		}
+		switch c := sql[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth > 0 {
+				depth--
+			}
+		case isNameByte(c, true):
+			j := i + 1
+			for j < len(sql) && isNameByte(sql[j], false) {
+				j++
+			}
+			if depth == 0 {
+				words = append(words, strings.ToUpper(sql[i:j]))
+			}
+			i = j
+			continue
+		}
+		i++
+	}
+	return words
+}
+
+// skipStatementPrelude skips the whitespace, comments and pragmas before the
+// statement in sql.
+func skipStatementPrelude(sql string) string {
+	for {
+		sql = strings.TrimLeft(sql, " \t\r\n")
+		switch {
+		case strings.HasPrefix(sql, "/*"):
+			end := strings.Index(sql, "*/")
+			if end == -1 {
+				return ""
+			}
+			sql = sql[end+2:]
+		case strings.HasPrefix(sql, "--"):
+			end := strings.IndexByte(sql, '\n')
+			if end == -1 {
+				return ""
+			}
+			sql = sql[end+1:]
+		case len(sql) > 6 && strings.EqualFold(sql[:7], "PRAGMA "):
+			end := strings.IndexByte(sql, ';')
+			if end == -1 {
+				return ""
+			}
+			sql = sql[end+1:]
+		default:
+			return sql
+		}
+	}
+}
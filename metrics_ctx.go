@@ -0,0 +1,61 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"time"
+)
+
+// QueryInfo describes a query run by an InstrumentRunner.
+type QueryInfo struct {
+	// Operation is the runner method: "Exec", "Query" or "QueryRow".
+	Operation string
+	// Statement is the generated SQL.
+	Statement string
+	// Kind is the StatementKind of Statement.
+	Kind string
+}
+
+// Instrumentation is notified of the start and the end of each query run by
+// an InstrumentRunner, e.g. to record metrics.
+//
+// A Prometheus implementation recording counts, latencies and errors by kind:
+//     type promInstrumentation struct {
+//         latency *prometheus.HistogramVec // labels: kind
+//         errors  *prometheus.CounterVec   // labels: kind
+//     }
+//
+//     func (p promInstrumentation) OnQueryStart(ctx context.Context, info sq.QueryInfo) context.Context {
+//         return ctx
+//     }
+//
+//     func (p promInstrumentation) OnQueryEnd(ctx context.Context, info sq.QueryInfo, d time.Duration, err error) {
+//         p.latency.WithLabelValues(info.Kind).Observe(d.Seconds())
+//         if err != nil {
+//             p.errors.WithLabelValues(info.Kind).Inc()
+//         }
+//     }
+//
+// The histogram count doubles as the query count.
+type Instrumentation interface {
+	// OnQueryStart is called before the query is run. The returned context
+	// is passed to the underlying runner and to OnQueryEnd.
+	OnQueryStart(ctx context.Context, info QueryInfo) context.Context
+	// OnQueryEnd is called with the duration and the error of the query.
+	OnQueryEnd(ctx context.Context, info QueryInfo, duration time.Duration, err error)
+}
+
+// InstrumentRunner returns a runner that notifies inst around each Exec,
+// Query and QueryRow of base. A QueryRow ends when the returned row is
+// scanned.
+func InstrumentRunner(base BaseRunner, inst Instrumentation) RunnerContext {
+	return newInstrumentedRunner(base, func(ctx context.Context, op, query string, args []interface{}) (context.Context, func(err error)) {
+		info := QueryInfo{Operation: op, Statement: query, Kind: StatementKind(query)}
+		ctx = inst.OnQueryStart(ctx, info)
+		start := time.Now()
+		return ctx, func(err error) {
+			inst.OnQueryEnd(ctx, info, time.Since(start), err)
+		}
+	})
+}
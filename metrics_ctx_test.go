@@ -0,0 +1,56 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type instrumentationStub struct {
+	started []QueryInfo
+	ended   []QueryInfo
+	errs    []error
+}
+
+func (s *instrumentationStub) OnQueryStart(ctx context.Context, info QueryInfo) context.Context {
+	s.started = append(s.started, info)
+	return ctx
+}
+
+func (s *instrumentationStub) OnQueryEnd(ctx context.Context, info QueryInfo, duration time.Duration, err error) {
+	s.ended = append(s.ended, info)
+	s.errs = append(s.errs, err)
+}
+
+func TestInstrumentRunner(t *testing.T) {
+	inst := &instrumentationStub{}
+	runner := InstrumentRunner(&DBStub{}, inst)
+
+	_, err := Delete("a").Where("b = ?", 1).RunWith(runner).ExecContext(ctx)
+	assert.NoError(t, err)
+	_, err = Upsert("a").Columns("b").Values(1).RunWith(runner).Exec()
+	assert.NoError(t, err)
+
+	expected := []QueryInfo{
+		{Operation: "Exec", Statement: "DELETE FROM a WHERE b = ?", Kind: "delete"},
+		{Operation: "Exec", Statement: "UPSERT INTO a (b) VALUES (?)", Kind: "upsert"},
+	}
+	assert.Equal(t, expected, inst.started)
+	assert.Equal(t, expected, inst.ended)
+	assert.Equal(t, []error{nil, nil}, inst.errs)
+}
+
+func TestInstrumentRunnerError(t *testing.T) {
+	inst := &instrumentationStub{}
+	runner := InstrumentRunner(baseRunnerStub{err: errors.New("boom")}, inst)
+
+	_, err := runner.Query("SELECT 1")
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, "select", inst.ended[0].Kind)
+	assert.EqualError(t, inst.errs[0], "boom")
+}
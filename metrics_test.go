@@ -0,0 +1,62 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatementKind(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM a":                                  "select",
+		"  select 1":                                       "select",
+		"WITH t AS (SELECT 1) SELECT * FROM t":             "select",
+		"INSERT INTO a VALUES (?)":                         "insert",
+		"INSERT INTO a VALUES (?) ON CONFLICT DO NOTHING":  "insert",
+		"INSERT INTO a VALUES (?) ON DUPLICATE KEY UPDATE": "upsert",
+		"UPSERT INTO a (b) VALUES (?)":                     "upsert",
+		"REPLACE INTO a (b) VALUES (?)":                    "upsert",
+		"MERGE INTO a USING b ON a.id = b.id":              "upsert",
+		"UPDATE a SET b = ?":                               "update",
+		"DELETE FROM a":                                    "delete",
+		"CREATE TABLE a (b INT)":                           "other",
+		"":                                                 "other",
+		"/* api */ PRAGMA TablePathPrefix(\"/Root\"); UPDATE a SET b = ?": "update",
+		"-- report\nSELECT 1": "select",
+		"/* unterminated":     "other",
+	}
+	for sql, kind := range tests {
+		assert.Equal(t, kind, StatementKind(sql), sql)
+	}
+}
+
+func TestStatementKindTricky(t *testing.T) {
+	tests := map[string]string{
+		"INSERT IGNORE INTO a VALUES (?)":                                      "insert",
+		"INSERT OR IGNORE INTO a VALUES (?)":                                   "insert",
+		"INSERT OR REPLACE INTO a VALUES (?)":                                  "upsert",
+		"INSERT INTO a (id) VALUES (?) ON CONFLICT (id) DO UPDATE SET n = 1":   "upsert",
+		"INSERT INTO a VALUES (' ON DUPLICATE KEY UPDATE')":                    "insert",
+		"INSERT INTO a VALUES (?) /* ON CONFLICT DO UPDATE */":                 "insert",
+		"INSERT INTO a SELECT 'x'' ON CONFLICT (b) DO UPDATE' FROM b":          "insert",
+		"WITH gone AS (SELECT id FROM a) DELETE FROM b WHERE id IN (SELECT 1)": "delete",
+		"WITH RECURSIVE t (n) AS (SELECT 1) UPDATE a SET b = 1":                "update",
+		"WITH t AS (SELECT 1) INSERT INTO a SELECT * FROM t":                   "insert",
+		"WITH t AS (DELETE FROM a RETURNING *) SELECT * FROM t":                "select",
+	}
+	for sql, kind := range tests {
+		assert.Equal(t, kind, StatementKind(sql), sql)
+	}
+}
+
+func TestStatementKindBuilders(t *testing.T) {
+	for _, d := range []Dialect{Postgres, MySQL, SQLite} {
+		sql, _, err := StatementBuilder.Dialect(d).Insert("t").Columns("id").Values(1).Ignore().ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "insert", StatementKind(sql), sql)
+
+		sql, _, err = StatementBuilder.Dialect(d).Upsert("t").Columns("id", "n").Values(1, 2).ConflictKeys("id").ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, "upsert", StatementKind(sql), sql)
+	}
+}
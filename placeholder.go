@@ -1,8 +1,10 @@
 package squirrel
 
 import (
-	"bytes"
+	"database/sql"
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -14,6 +16,22 @@ type PlaceholderFormat interface {
 	ReplacePlaceholders(sql string) (string, error)
 }
 
+// argsPlaceholderFormat is implemented by placeholder formats that rewrite the
+// args along with the SQL, like the NamedPlaceholders formats.
+type argsPlaceholderFormat interface {
+	replacePlaceholdersArgs(sql string, args []interface{}) (string, []interface{}, error)
+}
+
+// replacePlaceholders applies f to a rendered query. Args bound with
+// NamedArgs are passed positionally unless f binds them by name.
+func replacePlaceholders(f PlaceholderFormat, sql string, args []interface{}) (string, []interface{}, error) {
+	if af, ok := f.(argsPlaceholderFormat); ok {
+		return af.replacePlaceholdersArgs(sql, args)
+	}
+	sql, err := f.ReplacePlaceholders(sql)
+	return sql, positionalArgs(args), err
+}
+
 type placeholderDebugger interface {
 	debugPlaceholder() string
 }
@@ -34,6 +52,11 @@ var (
 	// AtP is a PlaceholderFormat instance that replaces placeholders with
 	// "@p"-prefixed positional placeholders (e.g. @p1, @p2, @p3).
 	AtP = atpFormat{}
+
+	// DollarP is a PlaceholderFormat instance that replaces placeholders with
+	// "$p"-prefixed positional placeholders (e.g. $p1, $p2, $p3), the
+	// parameter names used in YQL.
+	DollarP = dollarpFormat{}
 )
 
 type questionFormat struct{}
@@ -76,6 +99,102 @@ func (atpFormat) debugPlaceholder() string {
 	return "@p"
 }
 
+type dollarpFormat struct{}
+
+func (dollarpFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePositionalPlaceholders(sql, "$p")
+}
+
+func (dollarpFormat) debugPlaceholder() string {
+	return "$p"
+}
+
+// NamedPlaceholders returns a PlaceholderFormat that binds args by name, for
+// drivers that accept database/sql.NamedArg. Placeholders are rendered as
+// prefix followed by the name, e.g. "@" for SQL Server, ":" for Oracle or "$"
+// for YDB.
+//
+// Args bound with NamedArgs keep their name, and a name used more than once
+// is bound only once. Other args are named by their position: p1, p2, p3.
+//
+// Ex:
+//     Select("*").From("t").
+//         Where(Expr("a = :x OR b = :x", NamedArgs{"x": 1})).
+//         Where(Eq{"c": 2}).
+//         PlaceholderFormat(NamedPlaceholders("@"))
+//     // SELECT * FROM t WHERE a = @x OR b = @x AND c = @p3
+//     // args: sql.Named("x", 1), sql.Named("p3", 2)
+func NamedPlaceholders(prefix string) PlaceholderFormat {
+	return namedFormat{prefix: prefix}
+}
+
+type namedFormat struct {
+	prefix string
+}
+
+// ReplacePlaceholders names every placeholder by its position, as only the
+// builders know the args. It is used when the format is called directly.
+func (f namedFormat) ReplacePlaceholders(sql string) (string, error) {
+	return replacePositionalPlaceholders(sql, f.prefix+"p")
+}
+
+func (f namedFormat) debugPlaceholder() string {
+	return f.prefix
+}
+
+func (f namedFormat) replacePlaceholdersArgs(sqlStr string, args []interface{}) (string, []interface{}, error) {
+	buf := &strings.Builder{}
+	buf.Grow(len(sqlStr) + len(args)*(len(f.prefix)+4))
+
+	var named []interface{}
+	values := make(map[string]interface{}, len(args))
+	i := 0
+	start := 0
+	for p := 0; p < len(sqlStr); p++ {
+		if sqlStr[p] != '?' {
+			continue
+		}
+		buf.WriteString(sqlStr[start:p])
+		start = p + 1
+
+		if p+1 < len(sqlStr) && sqlStr[p+1] == '?' { // escape ?? => ?
+			buf.WriteByte('?')
+			p++
+			start++
+			continue
+		}
+
+		if i >= len(args) {
+			return "", nil, fmt.Errorf("too many placeholders for %d args", len(args))
+		}
+
+		var name string
+		var value interface{}
+		if arg, ok := args[i].(namedArg); ok {
+			name, value = arg.name, arg.value
+		} else {
+			name, value = "p"+strconv.Itoa(i+1), args[i]
+		}
+		i++
+
+		if prev, ok := values[name]; !ok {
+			values[name] = value
+			named = append(named, sql.Named(name, value))
+		} else if !reflect.DeepEqual(prev, value) {
+			return "", nil, fmt.Errorf("named arg %q is bound to different values", name)
+		}
+
+		buf.WriteString(f.prefix)
+		buf.WriteString(name)
+	}
+	if i < len(args) {
+		return "", nil, fmt.Errorf("not enough placeholders for %d args", len(args))
+	}
+
+	buf.WriteString(sqlStr[start:])
+	return buf.String(), named, nil
+}
+
 // Placeholders returns a string with count ? placeholders joined with commas.
 func Placeholders(count int) string {
 	if count < 1 {
@@ -86,29 +205,33 @@ func Placeholders(count int) string {
 }
 
 func replacePositionalPlaceholders(sql, prefix string) (string, error) {
-	buf := &bytes.Buffer{}
+	n := strings.Count(sql, "?")
+	if n == 0 {
+		return sql, nil
+	}
+
+	buf := &strings.Builder{}
+	buf.Grow(len(sql) + n*(len(prefix)+len(strconv.Itoa(n))))
+
+	var num [20]byte
 	i := 0
-	for {
-		p := strings.Index(sql, "?")
-		if p == -1 {
-			break
+	start := 0
+	for p := 0; p < len(sql); p++ {
+		if sql[p] != '?' {
+			continue
 		}
-
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
+		buf.WriteString(sql[start:p])
+		if p+1 < len(sql) && sql[p+1] == '?' { // escape ?? => ?
+			buf.WriteByte('?')
+			p++
 		} else {
 			i++
-			buf.WriteString(sql[:p])
-			fmt.Fprintf(buf, "%s%d", prefix, i)
-			sql = sql[p+1:]
+			buf.WriteString(prefix)
+			buf.Write(strconv.AppendInt(num[:0], int64(i), 10))
 		}
+		start = p + 1
 	}
 
-	buf.WriteString(sql)
+	buf.WriteString(sql[start:])
 	return buf.String(), nil
 }
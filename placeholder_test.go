@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"database/sql"
 	"strings"
 	"testing"
 
@@ -31,6 +32,12 @@ func TestAtp(t *testing.T) {
 	assert.Equal(t, "x = @p1 AND y = @p2", s)
 }
 
+func TestDollarP(t *testing.T) {
+	sql := "x = ? AND y = ?"
+	s, _ := DollarP.ReplacePlaceholders(sql)
+	assert.Equal(t, "x = $p1 AND y = $p2", s)
+}
+
 func TestPlaceholders(t *testing.T) {
 	assert.Equal(t, Placeholders(2), "?,?")
 }
@@ -53,6 +60,96 @@ func TestEscapeAtp(t *testing.T) {
 	assert.Equal(t, "SELECT uuid, \"data\" #> '{tags}' AS tags FROM nodes WHERE  \"data\" -> 'tags' ?| array['@p1'] AND enabled = @p2", s)
 }
 
+func TestEscapeDollarP(t *testing.T) {
+	sql := "SELECT * FROM nodes WHERE tags ??| array['?'] AND enabled = ?"
+	s, _ := DollarP.ReplacePlaceholders(sql)
+	assert.Equal(t, "SELECT * FROM nodes WHERE tags ?| array['$p1'] AND enabled = $p2", s)
+}
+
+func TestNamedPlaceholders(t *testing.T) {
+	b := Select("*").From("t").
+		Where(Expr("(a = :x OR b = :x)", NamedArgs{"x": 1})).
+		Where(Eq{"c": 2}).
+		Where("d ?? e")
+
+	sqlStr, args, err := b.PlaceholderFormat(NamedPlaceholders("@")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE (a = @x OR b = @x) AND c = @p3 AND d ? e", sqlStr)
+	assert.Equal(t, []interface{}{sql.Named("x", 1), sql.Named("p3", 2)}, args)
+
+	sqlStr, args, err = b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE (a = $1 OR b = $2) AND c = $3 AND d ? e", sqlStr)
+	assert.Equal(t, []interface{}{1, 1, 2}, args)
+}
+
+func TestNamedPlaceholdersConflict(t *testing.T) {
+	_, _, err := Select("*").From("t").
+		Where(Expr("a = :x", NamedArgs{"x": 1})).
+		Where(Expr("b = :x", NamedArgs{"x": 2})).
+		PlaceholderFormat(NamedPlaceholders(":")).
+		ToSql()
+	assert.Error(t, err)
+}
+
+func TestNamedPlaceholdersReplacePlaceholders(t *testing.T) {
+	s, _ := NamedPlaceholders("$").ReplacePlaceholders("a = ? AND b = ?")
+	assert.Equal(t, "a = $p1 AND b = $p2", s)
+}
+
+func TestReplacePositionalPlaceholdersEdges(t *testing.T) {
+	cases := map[string]string{
+		"":             "",
+		"SELECT 1":     "SELECT 1",
+		"?":            "$1",
+		"??":           "?",
+		"a = ? ??":     "a = $1 ?",
+		"???":          "?$1",
+		"(?,?,?,?,?)?": "($1,$2,$3,$4,$5)$6",
+	}
+	for in, expected := range cases {
+		s, err := Dollar.ReplacePlaceholders(in)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, s, "input %q", in)
+	}
+}
+
+func benchmarkInListSql(n int) string {
+	return "SELECT * FROM t WHERE id IN (" + Placeholders(n) + ")"
+}
+
+func BenchmarkReplacePlaceholdersDollar(b *testing.B) {
+	sql := benchmarkInListSql(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Dollar.ReplacePlaceholders(sql)
+	}
+}
+
+func BenchmarkReplacePlaceholdersAtP(b *testing.B) {
+	sql := benchmarkInListSql(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = AtP.ReplacePlaceholders(sql)
+	}
+}
+
+func BenchmarkReplacePlaceholdersNamed(b *testing.B) {
+	sql := benchmarkInListSql(1000)
+	args := make([]interface{}, 1000)
+	for i := range args {
+		args[i] = i
+	}
+	f := NamedPlaceholders("@").(argsPlaceholderFormat)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = f.replacePlaceholdersArgs(sql, args)
+	}
+}
+
 func BenchmarkPlaceholdersArray(b *testing.B) {
 	var count = b.N
 	placeholders := make([]string, count)
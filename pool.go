@@ -0,0 +1,38 @@
+package squirrel
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBufferSize is the capacity above which a buffer isn't returned to
+// bufferPool, so that one huge query doesn't pin its memory.
+const maxPooledBufferSize = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return &bytes.Buffer{} },
+}
+
+// getBuffer returns an empty buffer from bufferPool. Release it with
+// putBuffer once its contents have been copied, e.g. with String.
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// growArgs returns args with room for n more args.
+func growArgs(args []interface{}, n int) []interface{} {
+	if cap(args)-len(args) >= n {
+		return args
+	}
+	grown := make([]interface{}, len(args), len(args)+n)
+	copy(grown, args)
+	return grown
+}
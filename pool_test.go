@@ -0,0 +1,31 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrowArgs(t *testing.T) {
+	args := growArgs([]interface{}{1}, 3)
+	assert.Equal(t, []interface{}{1}, args)
+	assert.True(t, cap(args) >= 4)
+
+	same := growArgs(args, 2)
+	assert.Equal(t, &args[0], &same[0])
+}
+
+func TestBufferPool(t *testing.T) {
+	buf := getBuffer()
+	buf.WriteString("SELECT 1")
+	putBuffer(buf)
+	assert.Equal(t, 0, getBuffer().Len())
+
+	// results must not share memory with pooled buffers
+	sql1, _, err := Select("a").From("t1").ToSql()
+	assert.NoError(t, err)
+	sql2, _, err := Select("b").From("t2").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t1", sql1)
+	assert.Equal(t, "SELECT b FROM t2", sql2)
+}
@@ -0,0 +1,79 @@
+package squirrel
+
+import (
+	"bytes"
+	"strings"
+)
+
+// pragma is a single YQL "PRAGMA name(value);" statement
+type pragma struct {
+	name  string
+	value string
+}
+
+var pragmaValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// pragmasToSql renders pragmas as a statement prefix. When the same pragma is
+// set more than once, e.g. on both a StatementBuilderType and a query built
+// from it, only the last value is kept, at the position of the first one.
+func pragmasToSql(pragmas []pragma) string {
+	if len(pragmas) == 0 {
+		return ""
+	}
+
+	values := make(map[string]string, len(pragmas))
+	var names []string
+	for _, p := range pragmas {
+		key := strings.ToLower(p.name)
+		if _, ok := values[key]; !ok {
+			names = append(names, p.name)
+		}
+		values[key] = p.value
+	}
+
+	buf := &bytes.Buffer{}
+	for _, name := range names {
+		buf.WriteString("PRAGMA ")
+		buf.WriteString(name)
+		if value := values[strings.ToLower(name)]; value != "" {
+			buf.WriteString(`("`)
+			buf.WriteString(pragmaValueEscaper.Replace(value))
+			buf.WriteString(`")`)
+		}
+		buf.WriteString("; ")
+	}
+	return buf.String()
+}
+
+// escapeComment breaks up every "/*" and "*/" in c with a space, so that c
+// can't end the comment it is written in. A single strings.Replacer pass
+// isn't enough: it turns "/*/" into "/ */".
+func escapeComment(c string) string {
+	if !strings.Contains(c, "/*") && !strings.Contains(c, "*/") {
+		return c
+	}
+	buf := &strings.Builder{}
+	buf.Grow(len(c) + 8)
+	for i := 0; i < len(c); i++ {
+		if i > 0 && (c[i-1] == '/' && c[i] == '*' || c[i-1] == '*' && c[i] == '/') {
+			buf.WriteByte(' ')
+		}
+		buf.WriteByte(c[i])
+	}
+	return buf.String()
+}
+
+// commentsToSql renders comments as "/* comment */ " statement prefixes.
+func commentsToSql(comments []string) string {
+	if len(comments) == 0 {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+	for _, c := range comments {
+		buf.WriteString("/* ")
+		buf.WriteString(escapeComment(c))
+		buf.WriteString(" */ ")
+	}
+	return buf.String()
+}
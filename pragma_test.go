@@ -0,0 +1,115 @@
+package squirrel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPragma(t *testing.T) {
+	sql, args, err := Select("*").
+		From("t").
+		Where("a = ?", 1).
+		Pragma("TablePathPrefix", "/Root/db").
+		Pragma("AnsiInForEmptyOrNullableItemsCollections", "").
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := `PRAGMA TablePathPrefix("/Root/db"); ` +
+		"PRAGMA AnsiInForEmptyOrNullableItemsCollections; " +
+		"SELECT * FROM t WHERE a = $p1"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestPragmaDedup(t *testing.T) {
+	sb := StatementBuilder.Pragma("TablePathPrefix", "/Root/prod").Pragma("Foo", "x")
+
+	sql, _, err := sb.Update("t").
+		Set("a", 1).
+		Pragma("tablepathprefix", "/Root/test").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `PRAGMA TablePathPrefix("/Root/test"); PRAGMA Foo("x"); UPDATE t SET a = ?`, sql)
+
+	sql, _, err = sb.Insert("t").Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `PRAGMA TablePathPrefix("/Root/prod"); PRAGMA Foo("x"); INSERT INTO t VALUES (?)`, sql)
+
+	sql, _, err = sb.Delete("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `PRAGMA TablePathPrefix("/Root/prod"); PRAGMA Foo("x"); DELETE FROM t`, sql)
+}
+
+func TestPragmaNested(t *testing.T) {
+	sub := Select("id").From("b").Pragma("Foo", "inner")
+	sql, _, err := Select("*").
+		From("a").
+		Where(Expr("id IN (?)", sub)).
+		Pragma("Foo", `say "?"`).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `PRAGMA Foo("say \"?\""); SELECT * FROM a WHERE id IN (SELECT id FROM b)`, sql)
+}
+
+func TestComment(t *testing.T) {
+	sql, args, err := Select("*").From("users").Where("id = ?", 1).
+		Comment("route=/api/users").
+		Comment("evil */ DROP TABLE users; /*").
+		Pragma("TablePathPrefix", "/Root").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* route=/api/users */ /* evil * / DROP TABLE users; / * */ "+
+		"PRAGMA TablePathPrefix(\"/Root\"); SELECT * FROM users WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	// nested queries don't render comments
+	sql, _, err = Select("*").FromSelect(Select("1").Comment("inner"), "s").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT 1) AS s", sql)
+}
+
+func TestCommentEscaping(t *testing.T) {
+	for _, c := range []string{"/*/", "*/*/", "**//", "/*/ DROP TABLE users; --"} {
+		sql, _, err := Select("*").From("users").Comment(c).ToSql()
+		assert.NoError(t, err)
+		body := strings.TrimSuffix(strings.TrimPrefix(sql, "/* "), " */ SELECT * FROM users")
+		assert.NotContains(t, body, "*/", c)
+		assert.NotContains(t, body, "/*", c)
+	}
+
+	sql, _, err := Select("*").From("users").Comment("/*/ DROP TABLE users; --").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* / * / DROP TABLE users; -- */ SELECT * FROM users", sql)
+
+	assert.Equal(t, "/ * /", escapeComment("/*/"))
+	assert.Equal(t, "* / * /", escapeComment("*/*/"))
+	assert.Equal(t, "** //", escapeComment("**//"))
+}
+
+func TestStatementBuilderComment(t *testing.T) {
+	sb := StatementBuilder.Comment("app=billing")
+
+	sql, _, err := sb.Update("t").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app=billing */ UPDATE t SET a = ?", sql)
+
+	sql, _, err = sb.Insert("t").Values(1).Comment("job=import").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app=billing */ /* job=import */ INSERT INTO t VALUES (?)", sql)
+
+	sql, _, err = sb.Delete("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app=billing */ DELETE FROM t", sql)
+
+	sql, _, err = sb.DropTable("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app=billing */ DROP TABLE t", sql)
+
+	sql, _, err = sb.CreateIndex("i").On("t", "a").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "/* app=billing */ CREATE INDEX i ON t (a)", sql)
+}
@@ -0,0 +1,126 @@
+package squirrel
+
+import (
+	"strings"
+)
+
+// Quoter quotes identifiers, e.g. table and column names, for a database.
+type Quoter interface {
+	// QuoteIdent quotes a single identifier, escaping any quote characters
+	// in it.
+	QuoteIdent(name string) string
+}
+
+type identQuoter struct {
+	open, close string
+}
+
+func (q identQuoter) QuoteIdent(name string) string {
+	return q.open + strings.Replace(name, q.close, q.close+q.close, -1) + q.close
+}
+
+var (
+	// BacktickQuoter quotes identifiers with backticks, as YQL and MySQL do.
+	BacktickQuoter Quoter = identQuoter{"`", "`"}
+
+	// DoubleQuoteQuoter quotes identifiers with double quotes, as standard
+	// SQL, PostgreSQL, SQLite and Oracle do.
+	DoubleQuoteQuoter Quoter = identQuoter{`"`, `"`}
+
+	// BracketQuoter quotes identifiers with square brackets, as SQL Server
+	// does.
+	BracketQuoter Quoter = identQuoter{"[", "]"}
+)
+
+// isIdent tells whether s is a plain, unquoted identifier.
+func isIdent(s string) bool {
+	if s == "" || !isNameByte(s[0], true) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isNameByte(s[i], false) && s[i] != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// quoteName quotes each part of a dotted name like "schema.table", or
+// returns false if name is not made of plain identifiers. A trailing ".*"
+// is kept as it is.
+func quoteName(q Quoter, name string) (string, bool) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "*" && i == len(parts)-1 && i > 0 {
+			continue
+		}
+		if !isIdent(part) {
+			return "", false
+		}
+		parts[i] = q.QuoteIdent(part)
+	}
+	return strings.Join(parts, "."), true
+}
+
+// quoteTable quotes the table name at the start of clause, leaving the rest
+// (e.g. an alias or a join condition) as it is.
+func quoteTable(q Quoter, clause string) string {
+	trimmed := strings.TrimLeft(clause, " ")
+	name, rest := trimmed, ""
+	if i := strings.IndexAny(trimmed, " \t\n"); i >= 0 {
+		name, rest = trimmed[:i], trimmed[i:]
+	}
+	if quoted, ok := quoteName(q, name); ok {
+		return quoted + rest
+	}
+	return clause
+}
+
+// quoteColumn quotes column if it is a plain column reference, optionally
+// aliased: "name", "t.name", "t.*", "name AS n" or "name n". Anything else is
+// taken to be a raw expression and returned as it is.
+func quoteColumn(q Quoter, column string) string {
+	fields := strings.Fields(column)
+	switch {
+	case len(fields) == 1:
+		if fields[0] == "*" {
+			return column
+		}
+	case len(fields) == 2 && isIdent(fields[1]):
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS") && isIdent(fields[2]):
+	default:
+		return column
+	}
+
+	name, ok := quoteName(q, fields[0])
+	if !ok {
+		return column
+	}
+	switch len(fields) {
+	case 2:
+		return name + " " + q.QuoteIdent(fields[1])
+	case 3:
+		return name + " " + fields[1] + " " + q.QuoteIdent(fields[2])
+	}
+	return name
+}
+
+// quoteColumn quotes column with the Quoter set by
+// StatementBuilderType.QuoteIdentifiers, if any.
+func (o builderOptions) quoteColumn(column string) string {
+	if o.quoter == nil {
+		return column
+	}
+	return quoteColumn(o.quoter, column)
+}
+
+func (o builderOptions) quoteColumns(columns []string) []string {
+	if o.quoter == nil {
+		return columns
+	}
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = quoteColumn(o.quoter, column)
+	}
+	return quoted
+}
@@ -0,0 +1,67 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoters(t *testing.T) {
+	assert.Equal(t, "`a``b`", BacktickQuoter.QuoteIdent("a`b"))
+	assert.Equal(t, `"a""b"`, DoubleQuoteQuoter.QuoteIdent(`a"b`))
+	assert.Equal(t, "[a]]b]", BracketQuoter.QuoteIdent("a]b"))
+}
+
+func TestQuoteColumn(t *testing.T) {
+	tests := map[string]string{
+		"id":              `"id"`,
+		"u.name":          `"u"."name"`,
+		"u.*":             `"u".*`,
+		"*":               `*`,
+		"name AS n":       `"name" AS "n"`,
+		"u.name n":        `"u"."name" "n"`,
+		"COUNT(*) AS n":   `COUNT(*) AS n`,
+		"a + b":           `a + b`,
+		`"already"`:       `"already"`,
+		"name AS n extra": `name AS n extra`,
+	}
+	for column, expected := range tests {
+		assert.Equal(t, expected, quoteColumn(DoubleQuoteQuoter, column), column)
+	}
+}
+
+func TestStatementBuilderQuoteIdentifiers(t *testing.T) {
+	sb := StatementBuilder.QuoteIdentifiers(DoubleQuoteQuoter)
+
+	sql, _, err := sb.Select("id", "u.name AS user", "COUNT(*) AS n").
+		Column("order").
+		From("order o").
+		Join("users u ON u.id = o.user_id").
+		Where("o.id = ?", 1).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t,
+		`SELECT "id", "u"."name" AS "user", COUNT(*) AS n, "order" `+
+			`FROM "order" o JOIN "users" u ON u.id = o.user_id WHERE o.id = ?`,
+		sql)
+
+	sql, _, err = sb.Insert("order").Columns("group", "select").Values(1, 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "order" ("group","select") VALUES (?,?)`, sql)
+
+	sql, _, err = sb.Update("public.order").SetMap(map[string]interface{}{"group": 1}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "public"."order" SET "group" = ?`, sql)
+
+	sql, _, err = sb.Delete("order").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "order"`, sql)
+}
+
+func TestStatementBuilderQuoteIdentifiersTablePathPrefix(t *testing.T) {
+	sb := StatementBuilder.QuoteIdentifiers(BacktickQuoter).TablePathPrefix("/Root/db")
+
+	sql, _, err := sb.Select("key", "value").From("kv").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `key`, `value` FROM `/Root/db/kv`", sql)
+}
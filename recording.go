@@ -0,0 +1,102 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// RecordingRunnerNoRows is returned by the Query methods of RecordingRunner,
+// which has no rows to return.
+var RecordingRunnerNoRows = errors.New("RecordingRunner has no rows to return")
+
+// RecordedQuery is a query run with a RecordingRunner.
+type RecordedQuery struct {
+	// Method is the name of the Runner method called, e.g. "Exec" or
+	// "QueryRowContext".
+	Method string
+	SQL    string
+	Args   []interface{}
+}
+
+// RecordingRunner is a Runner that records the queries run with it instead
+// of sending them to a database, to test code built on the builders without
+// one:
+//     r := &RecordingRunner{}
+//     err := repo.DeactivateUser(r, 42) // runs Update("users")...RunWith(r).Exec()
+//     q, _ := r.Last()
+//     // q.SQL == "UPDATE users SET active = ? WHERE id = ?"
+//     // q.Args == []interface{}{false, 42}
+//
+// Exec returns Result, or a result of 0 rows affected if it is nil. Query
+// returns RecordingRunnerNoRows and QueryRow returns a row whose Scan
+// returns sql.ErrNoRows.
+//
+// It is safe for concurrent use.
+type RecordingRunner struct {
+	Result sql.Result
+
+	mu      sync.Mutex
+	queries []RecordedQuery
+}
+
+func (r *RecordingRunner) record(method, query string, args []interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, RecordedQuery{
+		Method: method,
+		SQL:    query,
+		Args:   append([]interface{}(nil), args...),
+	})
+}
+
+func (r *RecordingRunner) result() sql.Result {
+	if r.Result != nil {
+		return r.Result
+	}
+	return driver.RowsAffected(0)
+}
+
+// Exec records the query.
+func (r *RecordingRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	r.record("Exec", query, args)
+	return r.result(), nil
+}
+
+// Query records the query and returns RecordingRunnerNoRows.
+func (r *RecordingRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	r.record("Query", query, args)
+	return nil, RecordingRunnerNoRows
+}
+
+// QueryRow records the query and returns a row whose Scan returns
+// sql.ErrNoRows.
+func (r *RecordingRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	r.record("QueryRow", query, args)
+	return &Row{err: sql.ErrNoRows}
+}
+
+// Queries returns the queries recorded so far, in the order they were run.
+func (r *RecordingRunner) Queries() []RecordedQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedQuery(nil), r.queries...)
+}
+
+// Last returns the last query recorded. ok is false if there is none.
+func (r *RecordingRunner) Last() (q RecordedQuery, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.queries) == 0 {
+		return RecordedQuery{}, false
+	}
+	return r.queries[len(r.queries)-1], true
+}
+
+// Reset forgets the queries recorded so far.
+func (r *RecordingRunner) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = nil
+}
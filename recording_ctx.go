@@ -0,0 +1,27 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ExecContext records the query.
+func (r *RecordingRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	r.record("ExecContext", query, args)
+	return r.result(), nil
+}
+
+// QueryContext records the query and returns RecordingRunnerNoRows.
+func (r *RecordingRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	r.record("QueryContext", query, args)
+	return nil, RecordingRunnerNoRows
+}
+
+// QueryRowContext records the query and returns a row whose Scan returns
+// sql.ErrNoRows.
+func (r *RecordingRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	r.record("QueryRowContext", query, args)
+	return &Row{err: sql.ErrNoRows}
+}
@@ -0,0 +1,31 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordingRunnerContext(t *testing.T) {
+	r := &RecordingRunner{}
+	var _ RunnerContext = r
+
+	_, err := Insert("users").Columns("name").Values("ann").RunWith(r).ExecContext(ctx)
+	assert.NoError(t, err)
+
+	_, err = Select("*").From("users").RunWith(r).QueryContext(ctx)
+	assert.Equal(t, RecordingRunnerNoRows, err)
+
+	var id int
+	err = Select("id").From("users").RunWith(r).QueryRowContext(ctx).Scan(&id)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	assert.Equal(t, []RecordedQuery{
+		{Method: "ExecContext", SQL: "INSERT INTO users (name) VALUES (?)", Args: []interface{}{"ann"}},
+		{Method: "QueryContext", SQL: "SELECT * FROM users"},
+		{Method: "QueryRowContext", SQL: "SELECT id FROM users"},
+	}, r.Queries())
+}
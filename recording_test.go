@@ -0,0 +1,57 @@
+package squirrel
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordingRunner(t *testing.T) {
+	r := &RecordingRunner{}
+	var _ Runner = r
+
+	_, ok := r.Last()
+	assert.False(t, ok)
+
+	res, err := Update("users").Set("active", false).Where(Eq{"id": 42}).RunWith(r).Exec()
+	assert.NoError(t, err)
+	n, err := res.RowsAffected()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+
+	_, err = Select("*").From("users").RunWith(r).Query()
+	assert.Equal(t, RecordingRunnerNoRows, err)
+
+	var name string
+	err = Select("name").From("users").Where(Eq{"id": 1}).RunWith(r).QueryRow().Scan(&name)
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	assert.Equal(t, []RecordedQuery{
+		{Method: "Exec", SQL: "UPDATE users SET active = ? WHERE id = ?", Args: []interface{}{false, 42}},
+		{Method: "Query", SQL: "SELECT * FROM users"},
+		{Method: "QueryRow", SQL: "SELECT name FROM users WHERE id = ?", Args: []interface{}{1}},
+	}, r.Queries())
+
+	q, ok := r.Last()
+	assert.True(t, ok)
+	assert.Equal(t, "QueryRow", q.Method)
+
+	r.Reset()
+	assert.Empty(t, r.Queries())
+}
+
+func TestRecordingRunnerResult(t *testing.T) {
+	r := &RecordingRunner{Result: sqlResultStub{rows: 3}}
+	res, err := Delete("users").Where("active = ?", false).RunWith(r).Exec()
+	assert.NoError(t, err)
+	n, _ := res.RowsAffected()
+	assert.Equal(t, int64(3), n)
+}
+
+type sqlResultStub struct {
+	rows int64
+}
+
+func (r sqlResultStub) LastInsertId() (int64, error) { return 0, nil }
+func (r sqlResultStub) RowsAffected() (int64, error) { return r.rows, nil }
@@ -0,0 +1,216 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// RetryClassifier tells whether err, returned by a query, is worth retrying.
+type RetryClassifier func(err error) bool
+
+// RetryOptions configure RetryRunner.
+type RetryOptions struct {
+	// MaxAttempts is the number of times a query is run at most, 3 if zero.
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry, 10ms if zero. It doubles
+	// with each retry, up to MaxBackoff, 1s if zero. Each wait is randomized
+	// between half and all of it, so that queries failing together don't
+	// retry together.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Classifiers tell which errors are retried: an error is retried if any
+	// of them returns true. They are IsSerializationFailure, IsDeadlock and
+	// IsBadConn if empty.
+	//
+	// With YDB, add the classifier of its SDK:
+	//     Classifiers: []sq.RetryClassifier{func(err error) bool { return ydb.IsTransportError(err) }}
+	Classifiers []RetryClassifier
+}
+
+// sqlStater is implemented by the errors of PostgreSQL drivers like pgx and
+// lib/pq.
+type sqlStater interface {
+	SQLState() string
+}
+
+func sqlState(err error) string {
+	var e sqlStater
+	if errors.As(err, &e) {
+		return e.SQLState()
+	}
+	return ""
+}
+
+// IsSerializationFailure tells whether err is a serialization failure of a
+// transaction, SQLSTATE 40001.
+func IsSerializationFailure(err error) bool {
+	if sqlState(err) == "40001" {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "could not serialize access")
+}
+
+// IsDeadlock tells whether err reports a deadlock, SQLSTATE 40P01 on
+// PostgreSQL or error 1213 on MySQL.
+func IsDeadlock(err error) bool {
+	if sqlState(err) == "40P01" {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "deadlock detected") ||
+		strings.Contains(msg, "Error 1213")
+}
+
+// IsBadConn tells whether err is driver.ErrBadConn, returned by drivers when
+// a connection is broken before the query was sent.
+func IsBadConn(err error) bool {
+	return errors.Is(err, driver.ErrBadConn)
+}
+
+var defaultRetryClassifiers = []RetryClassifier{IsSerializationFailure, IsDeadlock, IsBadConn}
+
+// RetryRunner returns a runner that retries the Execs and Querys of base that
+// fail with a retryable error, waiting with an exponential backoff between
+// attempts. If the context of the query is done while waiting, the wait
+// stops and the error is ctx.Err(), wrapped with the last query error.
+//
+// Every query run with it may be run more than once, so use it only with
+// idempotent statements, and not inside a transaction, where a failed query
+// aborts the whole transaction:
+//     reads := sq.RetryRunner(db, sq.RetryOptions{MaxAttempts: 5})
+//     rows, err := sq.Select("*").From("users").RunWith(reads).QueryContext(ctx)
+//
+// QueryRow is not retried, as its error is only known when its row is
+// scanned.
+func RetryRunner(base BaseRunner, opts RetryOptions) RunnerContext {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = 10 * time.Millisecond
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = time.Second
+	}
+	if len(opts.Classifiers) == 0 {
+		opts.Classifiers = defaultRetryClassifiers
+	}
+	return &retryRunner{base: wrapRunner(base), opts: opts}
+}
+
+type retryRunner struct {
+	base BaseRunner
+	opts RetryOptions
+}
+
+func (r *retryRunner) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.exec(context.Background(), false, query, args)
+}
+
+func (r *retryRunner) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return r.query(context.Background(), false, query, args)
+}
+
+// QueryRow is not retried, as its error is only known when the row is
+// scanned.
+func (r *retryRunner) QueryRow(query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.base.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return queryRower.QueryRow(query, args...)
+}
+
+func (r *retryRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.exec(ctx, true, query, args)
+}
+
+func (r *retryRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.query(ctx, true, query, args)
+}
+
+// QueryRowContext is not retried, as its error is only known when the row is
+// scanned.
+func (r *retryRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	queryRower, ok := r.base.(QueryRowerContext)
+	if !ok {
+		return &Row{err: NoContextSupport}
+	}
+	return queryRower.QueryRowContext(ctx, query, args...)
+}
+
+func (r *retryRunner) exec(ctx context.Context, withCtx bool, query string, args []interface{}) (res sql.Result, err error) {
+	err = r.retry(ctx, func() error {
+		if ctxRunner, ok := r.base.(ExecerContext); ok {
+			res, err = ctxRunner.ExecContext(ctx, query, args...)
+		} else if withCtx {
+			res, err = nil, NoContextSupport
+		} else {
+			res, err = r.base.Exec(query, args...)
+		}
+		return err
+	})
+	return
+}
+
+func (r *retryRunner) query(ctx context.Context, withCtx bool, query string, args []interface{}) (rows *sql.Rows, err error) {
+	err = r.retry(ctx, func() error {
+		if ctxRunner, ok := r.base.(QueryerContext); ok {
+			rows, err = ctxRunner.QueryContext(ctx, query, args...)
+		} else if withCtx {
+			rows, err = nil, NoContextSupport
+		} else {
+			rows, err = r.base.Query(query, args...)
+		}
+		return err
+	})
+	return
+}
+
+// retry calls fn until it succeeds, fails with an error that isn't
+// retryable, or has been called MaxAttempts times.
+func (r *retryRunner) retry(ctx context.Context, fn func() error) error {
+	backoff := r.opts.MinBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.opts.MaxAttempts || !r.retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(jitter(backoff))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > r.opts.MaxBackoff {
+			backoff = r.opts.MaxBackoff
+		}
+	}
+}
+
+// jitter returns a random wait between half and all of backoff.
+func jitter(backoff time.Duration) time.Duration {
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+func (r *retryRunner) retryable(err error) bool {
+	for _, c := range r.opts.Classifiers {
+		if c(err) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyRunnerStub fails its first len(errs) queries with errs.
+type flakyRunnerStub struct {
+	DBStub
+	errs  []error
+	calls int
+}
+
+func (r *flakyRunnerStub) next() error {
+	r.calls++
+	if r.calls <= len(r.errs) {
+		return r.errs[r.calls-1]
+	}
+	return nil
+}
+
+func (r *flakyRunnerStub) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if err := r.next(); err != nil {
+		return nil, err
+	}
+	return r.DBStub.ExecContext(ctx, query, args...)
+}
+
+func (r *flakyRunnerStub) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if err := r.next(); err != nil {
+		return nil, err
+	}
+	return r.DBStub.QueryContext(ctx, query, args...)
+}
+
+type sqlStateErrorStub string
+
+func (e sqlStateErrorStub) Error() string    { return "ERROR: " + string(e) }
+func (e sqlStateErrorStub) SQLState() string { return string(e) }
+
+func TestRetryRunner(t *testing.T) {
+	db := &flakyRunnerStub{errs: []error{driver.ErrBadConn, sqlStateErrorStub("40001")}}
+	runner := RetryRunner(db, RetryOptions{MinBackoff: time.Nanosecond})
+
+	_, err := Update("users").Set("a", 1).RunWith(runner).ExecContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, db.calls)
+	assert.Equal(t, "UPDATE users SET a = ?", db.LastExecSql)
+
+	db = &flakyRunnerStub{errs: []error{errors.New("Error 1213: Deadlock found"), driver.ErrBadConn, driver.ErrBadConn}}
+	runner = RetryRunner(db, RetryOptions{MinBackoff: time.Nanosecond})
+	_, err = Select("*").From("users").RunWith(runner).Query()
+	assert.Equal(t, driver.ErrBadConn, err)
+	assert.Equal(t, 3, db.calls)
+}
+
+func TestRetryRunnerNotRetryable(t *testing.T) {
+	db := &flakyRunnerStub{errs: []error{errors.New("syntax error")}}
+	runner := RetryRunner(db, RetryOptions{MinBackoff: time.Nanosecond})
+	_, err := Select("*").From("users").RunWith(runner).QueryContext(ctx)
+	assert.EqualError(t, err, "syntax error")
+	assert.Equal(t, 1, db.calls)
+
+	transient := errors.New("transport error")
+	db = &flakyRunnerStub{errs: []error{transient}}
+	runner = RetryRunner(db, RetryOptions{
+		MinBackoff:  time.Nanosecond,
+		Classifiers: []RetryClassifier{func(err error) bool { return err == transient }},
+	})
+	_, err = Select("*").From("users").RunWith(runner).QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, db.calls)
+}
+
+func TestRetryRunnerContextDone(t *testing.T) {
+	db := &flakyRunnerStub{errs: []error{driver.ErrBadConn, driver.ErrBadConn}}
+	runner := RetryRunner(db, RetryOptions{MinBackoff: time.Hour})
+
+	c, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := Delete("users").RunWith(runner).ExecContext(c)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.EqualError(t, err, "context canceled (last error: driver: bad connection)")
+	assert.Equal(t, 1, db.calls)
+}
+
+func TestRetryJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		wait := jitter(10 * time.Millisecond)
+		assert.True(t, wait >= 5*time.Millisecond && wait <= 10*time.Millisecond, wait)
+	}
+}
+
+func TestRetryClassifiers(t *testing.T) {
+	assert.True(t, IsSerializationFailure(fmt.Errorf("commit: %w", sqlStateErrorStub("40001"))))
+	assert.True(t, IsSerializationFailure(errors.New("pq: could not serialize access due to concurrent update")))
+	assert.False(t, IsSerializationFailure(sqlStateErrorStub("23505")))
+
+	assert.True(t, IsDeadlock(sqlStateErrorStub("40P01")))
+	assert.True(t, IsDeadlock(errors.New("Error 1213 (40001): Deadlock found when trying to get lock")))
+	assert.False(t, IsDeadlock(errors.New("Error 1062: Duplicate entry")))
+
+	assert.True(t, IsBadConn(fmt.Errorf("exec: %w", driver.ErrBadConn)))
+	assert.False(t, IsBadConn(sql.ErrNoRows))
+}
@@ -0,0 +1,351 @@
+package squirrel
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// Rows is the interface that wraps the methods of database/sql.Rows used to
+// scan results into structs and maps.
+type Rows interface {
+	Columns() ([]string, error)
+	Next() bool
+	Scan(dest ...interface{}) error
+	Err() error
+	Close() error
+}
+
+// ScanStruct scans the current row of rows into dest, a pointer to a struct.
+//
+// Columns are matched to fields the same way SetStruct maps fields to columns:
+// by the "db" tag, or by the lowercased field name. It is an error for a column
+// to have no matching field; fields without a column are left untouched.
+//
+// Ex:
+//     rows, err := Select("id", "name").From("users").RunWith(db).Query()
+//     ...
+//     for rows.Next() {
+//         var u User
+//         err = ScanStruct(rows, &u)
+//         ...
+//     }
+func ScanStruct(rows Rows, dest interface{}) error {
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ScanStruct: expected a pointer to a struct, not %T", dest)
+	}
+	indexes, err := scanIndexes(v.Elem().Type(), columns)
+	if err != nil {
+		return err
+	}
+	targets, err := scanTargets(v.Elem(), indexes)
+	if err != nil {
+		return err
+	}
+	return rows.Scan(targets...)
+}
+
+// ScanStructs scans all the rows of rows into dest, a pointer to a slice of
+// structs or of pointers to structs, and closes rows.
+//
+// Columns are matched to fields as in ScanStruct.
+//
+// Ex:
+//     var users []User
+//     err := ScanStructs(rows, &users)
+func ScanStructs(rows Rows, dest interface{}) error {
+	defer rows.Close()
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("ScanStructs: expected a pointer to a slice, not %T", dest)
+	}
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	structType, isPtr := elemType, false
+	if structType.Kind() == reflect.Ptr {
+		structType, isPtr = structType.Elem(), true
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("ScanStructs: expected a slice of structs, not %T", dest)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	indexes, err := scanIndexes(structType, columns)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		elem := reflect.New(structType)
+		targets, err := scanTargets(elem.Elem(), indexes)
+		if err != nil {
+			return err
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+		if isPtr {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	v.Elem().Set(slice)
+	return nil
+}
+
+// ScanMap scans the current row of rows into a map from column name to value.
+//
+// Values are as returned by the driver, except that []byte values are copied,
+// since the driver may reuse their memory on the next call to Next, and that
+// []byte values of text columns are converted to strings if rows reports the
+// types of its columns, as *sql.Rows does.
+func ScanMap(rows Rows) (map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	text, err := textColumns(rows, len(columns))
+	if err != nil {
+		return nil, err
+	}
+	return scanMap(rows, columns, text)
+}
+
+// ScanMaps scans all the rows of rows into maps from column name to value,
+// as ScanMap does, and closes rows. It suits queries whose columns aren't
+// known in advance, like reports:
+//     rows, err := Select(columns...).From("sales").RunWith(db).Query()
+//     ...
+//     report, err := ScanMaps(rows)
+//     ...
+//     json.NewEncoder(w).Encode(report)
+func ScanMaps(rows Rows) ([]map[string]interface{}, error) {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	text, err := textColumns(rows, len(columns))
+	if err != nil {
+		return nil, err
+	}
+
+	maps := []map[string]interface{}{}
+	for rows.Next() {
+		m, err := scanMap(rows, columns, text)
+		if err != nil {
+			return nil, err
+		}
+		maps = append(maps, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return maps, nil
+}
+
+func scanMap(rows Rows, columns []string, text []bool) (map[string]interface{}, error) {
+	values := make([]interface{}, len(columns))
+	targets := make([]interface{}, len(columns))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+	if err := rows.Scan(targets...); err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if b, ok := values[i].([]byte); ok {
+			if text[i] {
+				values[i] = string(b)
+			} else {
+				values[i] = append([]byte(nil), b...)
+			}
+		}
+		m[column] = values[i]
+	}
+	return m, nil
+}
+
+// columnTyper is implemented by *sql.Rows.
+type columnTyper interface {
+	ColumnTypes() ([]*sql.ColumnType, error)
+}
+
+var (
+	stringType     = reflect.TypeOf("")
+	nullStringType = reflect.TypeOf(sql.NullString{})
+)
+
+// textColumns tells which of the n columns of rows hold text, as far as the
+// driver reports it.
+func textColumns(rows Rows, n int) ([]bool, error) {
+	text := make([]bool, n)
+	ct, ok := rows.(columnTyper)
+	if !ok {
+		return text, nil
+	}
+	types, err := ct.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	for i, t := range types {
+		if i < n {
+			st := t.ScanType()
+			text[i] = st == stringType || st == nullStringType
+		}
+	}
+	return text, nil
+}
+
+// ForEachRow calls fn for each row of rows, e.g. to scan it with rows.Scan,
+// ScanStruct or ScanMap, and closes rows. It stops at the first error
+// returned by fn.
+//
+// Unlike ScanStructs, it keeps only the current row in memory, so it suits
+// exports and other reads of large result sets.
+//
+// Ex:
+//     err := ForEachRow(rows, func(rows Rows) error {
+//         var e Event
+//         if err := ScanStruct(rows, &e); err != nil {
+//             return err
+//         }
+//         return enc.Encode(e)
+//     })
+func ForEachRow(rows Rows, fn func(Rows) error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ForEachRowWith Queries db with the SQL returned by s and calls fn for each
+// row of the result. See ForEachRow.
+func ForEachRowWith(db Queryer, s Sqlizer, fn func(Rows) error) error {
+	rows, err := QueryWith(db, s)
+	if err != nil {
+		return err
+	}
+	return ForEachRow(rows, fn)
+}
+
+// scanIndexes returns the field index of each of the columns in the struct
+// type t.
+func scanIndexes(t reflect.Type, columns []string) ([][]int, error) {
+	byColumn := make(map[string][]int)
+	for _, f := range structFields(t) {
+		byColumn[f.column] = f.index
+	}
+	indexes := make([][]int, len(columns))
+	for i, column := range columns {
+		index, ok := byColumn[column]
+		if !ok {
+			return nil, fmt.Errorf("missing destination field for column %q in %s", column, t)
+		}
+		indexes[i] = index
+	}
+	return indexes, nil
+}
+
+// scanTargets returns pointers to the fields of the struct v at indexes,
+// allocating nil embedded pointers on the way.
+func scanTargets(v reflect.Value, indexes [][]int) ([]interface{}, error) {
+	targets := make([]interface{}, len(indexes))
+	for i, index := range indexes {
+		fv := v
+		for j, x := range index {
+			if j > 0 && fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					if !fv.CanSet() {
+						return nil, fmt.Errorf("cannot allocate nil pointer to unexported embedded struct %s", fv.Type().Elem())
+					}
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(x)
+		}
+		targets[i] = fv.Addr().Interface()
+	}
+	return targets, nil
+}
+
+// ScanStruct is a shortcut for Query and ScanStruct on the first row. It
+// returns sql.ErrNoRows if the query returns no rows.
+func (b SelectBuilder) ScanStruct(dest interface{}) error {
+	rows, err := b.Query()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	return ScanStruct(rows, dest)
+}
+
+// ScanStructs is a shortcut for Query and ScanStructs.
+func (b SelectBuilder) ScanStructs(dest interface{}) error {
+	rows, err := b.Query()
+	if err != nil {
+		return err
+	}
+	return ScanStructs(rows, dest)
+}
+
+// ScanMap is a shortcut for Query and ScanMap on the first row. It returns
+// sql.ErrNoRows if the query returns no rows.
+func (b SelectBuilder) ScanMap() (map[string]interface{}, error) {
+	rows, err := b.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+	return ScanMap(rows)
+}
+
+// ScanMaps is a shortcut for Query and ScanMaps.
+func (b SelectBuilder) ScanMaps() ([]map[string]interface{}, error) {
+	rows, err := b.Query()
+	if err != nil {
+		return nil, err
+	}
+	return ScanMaps(rows)
+}
+
+// ForEachRow is a shortcut for Query and ForEachRow.
+func (b SelectBuilder) ForEachRow(fn func(Rows) error) error {
+	rows, err := b.Query()
+	if err != nil {
+		return err
+	}
+	return ForEachRow(rows, fn)
+}
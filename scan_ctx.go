@@ -0,0 +1,41 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+)
+
+// ForEachRowContext is like ForEachRow, but stops with ctx.Err() once ctx is
+// done.
+func ForEachRowContext(ctx context.Context, rows Rows, fn func(Rows) error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ForEachRowContextWith QueryContexts db with the SQL returned by s and calls
+// fn for each row of the result. See ForEachRowContext.
+func ForEachRowContextWith(ctx context.Context, db QueryerContext, s Sqlizer, fn func(Rows) error) error {
+	rows, err := QueryContextWith(ctx, db, s)
+	if err != nil {
+		return err
+	}
+	return ForEachRowContext(ctx, rows, fn)
+}
+
+// ForEachRowContext is a shortcut for QueryContext and ForEachRowContext.
+func (b SelectBuilder) ForEachRowContext(ctx context.Context, fn func(Rows) error) error {
+	rows, err := b.QueryContext(ctx)
+	if err != nil {
+		return err
+	}
+	return ForEachRowContext(ctx, rows, fn)
+}
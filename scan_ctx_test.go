@@ -0,0 +1,51 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBuilderForEachRowContext(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	var ns []int64
+	err := Select("n").From("numbers").RunWith(db).ForEachRowContext(ctx, func(r Rows) error {
+		var n int64
+		err := r.Scan(&n)
+		ns = append(ns, n)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ns)
+
+	ns = nil
+	err = ForEachRowContextWith(ctx, db, Select("n").From("numbers"), func(r Rows) error {
+		var n int64
+		err := r.Scan(&n)
+		ns = append(ns, n)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ns)
+}
+
+func TestForEachRowContextCanceled(t *testing.T) {
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rows := &rowsStub{columns: []string{"id"}, rows: [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}}}
+	calls := 0
+	err := ForEachRowContext(cancelCtx, rows, func(Rows) error {
+		calls++
+		cancel()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, calls)
+	assert.True(t, rows.closed)
+}
@@ -0,0 +1,238 @@
+package squirrel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rowsStub is an in-memory Rows.
+type rowsStub struct {
+	columns []string
+	rows    [][]interface{}
+	cur     int
+	closed  bool
+}
+
+func (r *rowsStub) Columns() ([]string, error) { return r.columns, nil }
+
+func (r *rowsStub) Next() bool {
+	r.cur++
+	return r.cur <= len(r.rows)
+}
+
+func (r *rowsStub) Scan(dest ...interface{}) error {
+	if len(dest) != len(r.columns) {
+		return fmt.Errorf("expected %d destination arguments, not %d", len(r.columns), len(dest))
+	}
+	for i, d := range dest {
+		switch d := d.(type) {
+		case *int64:
+			*d = r.rows[r.cur-1][i].(int64)
+		case *string:
+			*d = r.rows[r.cur-1][i].(string)
+		case *interface{}:
+			*d = r.rows[r.cur-1][i]
+		default:
+			return fmt.Errorf("unsupported destination %T", d)
+		}
+	}
+	return nil
+}
+
+func (r *rowsStub) Err() error { return nil }
+
+func (r *rowsStub) Close() error {
+	r.closed = true
+	return nil
+}
+
+type scanTestBase struct {
+	ID int64 `db:"id"`
+}
+
+// ScanTestBase is exported so that a nil pointer to it can be allocated when
+// it is embedded.
+type ScanTestBase struct {
+	ID int64 `db:"id"`
+}
+
+type scanTestUser struct {
+	scanTestBase
+	Name  string `db:"name"`
+	Email string
+}
+
+func TestScanStruct(t *testing.T) {
+	rows := &rowsStub{
+		columns: []string{"name", "id", "email"},
+		rows:    [][]interface{}{{"moe", int64(1), "moe@example.com"}},
+	}
+	assert.True(t, rows.Next())
+
+	var u scanTestUser
+	err := ScanStruct(rows, &u)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), u.ID)
+	assert.Equal(t, "moe", u.Name)
+	assert.Equal(t, "moe@example.com", u.Email)
+
+	assert.Error(t, ScanStruct(rows, u))
+}
+
+func TestScanStructNilEmbeddedPointer(t *testing.T) {
+	type user struct {
+		*ScanTestBase
+		Name string `db:"name"`
+	}
+	rows := &rowsStub{columns: []string{"id", "name"}, rows: [][]interface{}{{int64(1), "moe"}}}
+	rows.Next()
+
+	var u user
+	assert.NoError(t, ScanStruct(rows, &u))
+	assert.Equal(t, int64(1), u.ID)
+}
+
+func TestScanStructMissingField(t *testing.T) {
+	rows := &rowsStub{columns: []string{"name", "age"}, rows: [][]interface{}{{"moe", int64(3)}}}
+	rows.Next()
+
+	var u scanTestUser
+	err := ScanStruct(rows, &u)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"age"`)
+}
+
+func TestScanStructs(t *testing.T) {
+	newRows := func() *rowsStub {
+		return &rowsStub{
+			columns: []string{"id", "name"},
+			rows:    [][]interface{}{{int64(1), "moe"}, {int64(2), "larry"}},
+		}
+	}
+
+	rows := newRows()
+	var users []scanTestUser
+	assert.NoError(t, ScanStructs(rows, &users))
+	assert.True(t, rows.closed)
+	assert.Len(t, users, 2)
+	assert.Equal(t, int64(2), users[1].ID)
+	assert.Equal(t, "larry", users[1].Name)
+
+	var ptrs []*scanTestUser
+	assert.NoError(t, ScanStructs(newRows(), &ptrs))
+	assert.Len(t, ptrs, 2)
+	assert.Equal(t, "moe", ptrs[0].Name)
+
+	assert.Error(t, ScanStructs(newRows(), users))
+	var ints []int
+	assert.Error(t, ScanStructs(newRows(), &ints))
+}
+
+func TestScanMap(t *testing.T) {
+	b := []byte("moe")
+	rows := &rowsStub{columns: []string{"id", "name"}, rows: [][]interface{}{{int64(1), b}}}
+	rows.Next()
+
+	m, err := ScanMap(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": int64(1), "name": []byte("moe")}, m)
+
+	b[0] = 'j'
+	assert.Equal(t, []byte("moe"), m["name"])
+}
+
+func TestScanMaps(t *testing.T) {
+	rows := &rowsStub{columns: []string{"id", "name"}, rows: [][]interface{}{
+		{int64(1), []byte("moe")},
+		{int64(2), nil},
+	}}
+	maps, err := ScanMaps(rows)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"id": int64(1), "name": []byte("moe")},
+		{"id": int64(2), "name": nil},
+	}, maps)
+	assert.True(t, rows.closed)
+
+	maps, err = ScanMaps(&rowsStub{columns: []string{"id"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{}, maps)
+}
+
+func TestSelectBuilderScanMaps(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	maps, err := Select("word").From("numbers").RunWith(db).ScanMaps()
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{{"word": "1"}, {"word": "2"}, {"word": "3"}}, maps)
+
+	m, err := Select("n").From("numbers").RunWith(db).ScanMap()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"n": int64(1)}, m)
+
+	_, err = Select("n").From("numbers").ScanMaps()
+	assert.Equal(t, RunnerNotSet, err)
+}
+
+func TestSelectBuilderScanStructNoRunner(t *testing.T) {
+	var u scanTestUser
+	assert.Equal(t, RunnerNotSet, Select("id").From("users").ScanStruct(&u))
+	var users []scanTestUser
+	assert.Equal(t, RunnerNotSet, Select("id").From("users").ScanStructs(&users))
+}
+
+func TestForEachRow(t *testing.T) {
+	rows := &rowsStub{columns: []string{"id"}, rows: [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}}}
+	var ids []int64
+	err := ForEachRow(rows, func(r Rows) error {
+		var id int64
+		if err := r.Scan(&id); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.True(t, rows.closed)
+
+	rows = &rowsStub{columns: []string{"id"}, rows: [][]interface{}{{int64(1)}, {int64(2)}}}
+	calls := 0
+	err = ForEachRow(rows, func(r Rows) error {
+		calls++
+		return fmt.Errorf("stop")
+	})
+	assert.EqualError(t, err, "stop")
+	assert.Equal(t, 1, calls)
+	assert.True(t, rows.closed)
+}
+
+func TestSelectBuilderForEachRow(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	var sum int64
+	err := Select("n").From("numbers").RunWith(db).ForEachRow(func(r Rows) error {
+		var n int64
+		err := r.Scan(&n)
+		sum += n
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), sum)
+
+	sum = 0
+	err = ForEachRowWith(db, Select("n").From("numbers").Where("n > ?", 0), func(r Rows) error {
+		m, err := ScanMap(r)
+		sum += m["n"].(int64)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), sum)
+
+	err = Select("n").From("numbers").ForEachRow(func(Rows) error { return nil })
+	assert.Equal(t, RunnerNotSet, err)
+}
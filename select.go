@@ -1,29 +1,45 @@
 package squirrel
 
 import (
-	"bytes"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
-
-	"github.com/lann/builder"
 )
 
 type selectData struct {
 	PlaceholderFormat PlaceholderFormat
 	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
 	Prefixes          []Sqlizer
+	RecursiveCTEs     bool
+	CTEs              []Sqlizer
+	Hints             []string
 	Options           []string
+	DistinctOn        []string
 	Columns           []Sqlizer
 	From              Sqlizer
+	ViewIndex         string
+	FlattenMode       string
+	FlattenBy         []string
 	Joins             []Sqlizer
 	WhereParts        []Sqlizer
-	GroupBys          []string
+	GroupBys          []Sqlizer
 	HavingParts       []Sqlizer
+	Windows           []Sqlizer
+	SetOps            []Sqlizer
 	OrderByParts      []Sqlizer
-	Limit             string
-	Offset            string
+	Limit             Sqlizer
+	Offset            Sqlizer
+	Pagination        Pagination
+	Locks             []Sqlizer
 	Suffixes          []Sqlizer
+
+	// subquery is set, along with outer, the dialect of the query holding
+	// it, when d is written as part of another query.
+	subquery bool
+	outer    dialectKind
 }
 
 func (d *selectData) Exec() (sql.Result, error) {
@@ -52,26 +68,95 @@ func (d *selectData) QueryRow() RowScanner {
 }
 
 func (d *selectData) ToSql() (sqlStr string, args []interface{}, err error) {
+	dialect := dialectKindOf(d.PlaceholderFormat)
+
+	// write expressions like ILike for the database
+	_, explicit := d.PlaceholderFormat.(dialectFormat)
+	written := d.forDialect(dialect, explicit)
+	d = &written
+
+	// on YQL hints are pragmas, which only come before the whole query
+	pragmas := d.Pragmas
+	if dialect == dialectYQL {
+		for _, h := range d.Hints {
+			if !isValidHint(h) {
+				err = clauseError("select", "hint", fmt.Errorf("invalid hint %q", h))
+				return
+			}
+			pragmas = appendPragma(pragmas, h, "")
+		}
+		d.Hints = nil
+	}
+
 	sqlStr, args, err = d.toSqlRaw()
 	if err != nil {
 		return
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sqlStr)
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	if err != nil {
+		return
+	}
+
+	sqlStr = commentsToSql(d.Comments) + pragmasToSql(pragmas) + sqlStr
 	return
 }
 
+// forDialect returns d with its expressions, including those in subqueries,
+// written for the database dialect.
+func (d selectData) forDialect(dialect dialectKind, explicit bool) selectData {
+	d.Prefixes = forDialect(d.Prefixes, dialect, explicit)
+	d.CTEs = forDialect(d.CTEs, dialect, explicit)
+	d.Columns = forDialect(d.Columns, dialect, explicit)
+	if d.From != nil {
+		d.From, _ = partForDialect(d.From, dialect, explicit)
+	}
+	d.Joins = forDialect(d.Joins, dialect, explicit)
+	d.WhereParts = forDialect(d.WhereParts, dialect, explicit)
+	d.GroupBys = forDialect(d.GroupBys, dialect, explicit)
+	d.HavingParts = forDialect(d.HavingParts, dialect, explicit)
+	d.SetOps = forDialect(d.SetOps, dialect, explicit)
+	d.OrderByParts = forDialect(d.OrderByParts, dialect, explicit)
+	d.Suffixes = forDialect(d.Suffixes, dialect, explicit)
+	return d
+}
+
+// dialect returns the database d is written for: that of the outermost query
+// when d is a subquery.
+func (d *selectData) dialect() dialectKind {
+	if d.subquery {
+		return d.outer
+	}
+	return dialectKindOf(d.PlaceholderFormat)
+}
+
 func (d *selectData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 	if len(d.Columns) == 0 {
-		err = fmt.Errorf("select statements must have at least one result column")
+		err = builderError("select", "no result columns")
 		return
 	}
 
-	sql := &bytes.Buffer{}
+	sql := getBuffer()
+	defer putBuffer(sql)
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("select", "prefix", err)
+			return
+		}
+
+		sql.WriteString(" ")
+	}
+
+	if len(d.CTEs) > 0 {
+		sql.WriteString("WITH ")
+		if d.RecursiveCTEs {
+			sql.WriteString("RECURSIVE ")
+		}
+		args, err = appendToSql(d.CTEs, sql, ", ", args)
+		if err != nil {
+			err = clauseError("select", "WITH", err)
 			return
 		}
 
@@ -80,14 +165,58 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 
 	sql.WriteString("SELECT ")
 
+	for _, h := range d.Hints {
+		if !isValidHint(h) {
+			err = clauseError("select", "hint", fmt.Errorf("invalid hint %q", h))
+			return
+		}
+	}
+
+	dialect := d.dialect()
+	if len(d.Hints) > 0 && d.subquery && (dialect == dialectSQLServer || dialect == dialectYQL) {
+		err = clauseError("select", "hint", errors.New("only supported on the outermost query on this database"))
+		return
+	}
+	if len(d.Hints) > 0 && dialect != dialectSQLServer && dialect != dialectYQL {
+		sql.WriteString("/*+ ")
+		sql.WriteString(strings.Join(d.Hints, " "))
+		sql.WriteString(" */ ")
+	}
+
+	if len(d.DistinctOn) > 0 {
+		if dialect != dialectPostgres {
+			err = clauseError("select", "DISTINCT ON", errors.New("only supported by PostgreSQL; use the Dollar placeholder format"))
+			return
+		}
+		sql.WriteString("DISTINCT ON (")
+		sql.WriteString(strings.Join(d.DistinctOn, ", "))
+		sql.WriteString(") ")
+	}
+
 	if len(d.Options) > 0 {
 		sql.WriteString(strings.Join(d.Options, " "))
 		sql.WriteString(" ")
 	}
 
+	pagination, err := d.pagination()
+	if err != nil {
+		return
+	}
+
+	if pagination == PaginationTop && d.Limit != nil {
+		sql.WriteString("TOP (")
+		args, err = appendToSql([]Sqlizer{d.Limit}, sql, "", args)
+		if err != nil {
+			err = clauseError("select", "TOP", err)
+			return
+		}
+		sql.WriteString(") ")
+	}
+
 	if len(d.Columns) > 0 {
 		args, err = appendToSql(d.Columns, sql, ", ", args)
 		if err != nil {
+			err = clauseError("select", "SELECT", err)
 			return
 		}
 	}
@@ -96,14 +225,37 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(" FROM ")
 		args, err = appendToSql([]Sqlizer{d.From}, sql, "", args)
 		if err != nil {
+			err = clauseError("select", "FROM", err)
 			return
 		}
 	}
 
+	if len(d.ViewIndex) > 0 {
+		sql.WriteString(" VIEW ")
+		sql.WriteString(d.ViewIndex)
+	}
+
+	if len(d.FlattenBy) > 0 {
+		sql.WriteString(" FLATTEN ")
+		if len(d.FlattenMode) > 0 {
+			sql.WriteString(d.FlattenMode)
+			sql.WriteString(" ")
+		}
+		sql.WriteString("BY ")
+		if len(d.FlattenBy) == 1 {
+			sql.WriteString(d.FlattenBy[0])
+		} else {
+			sql.WriteString("(")
+			sql.WriteString(strings.Join(d.FlattenBy, ", "))
+			sql.WriteString(")")
+		}
+	}
+
 	if len(d.Joins) > 0 {
 		sql.WriteString(" ")
 		args, err = appendToSql(d.Joins, sql, " ", args)
 		if err != nil {
+			err = clauseError("select", "JOIN", err)
 			return
 		}
 	}
@@ -112,19 +264,43 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(" WHERE ")
 		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
 		if err != nil {
+			err = clauseError("select", "WHERE", err)
 			return
 		}
 	}
 
 	if len(d.GroupBys) > 0 {
 		sql.WriteString(" GROUP BY ")
-		sql.WriteString(strings.Join(d.GroupBys, ", "))
+		args, err = appendToSql(d.GroupBys, sql, ", ", args)
+		if err != nil {
+			err = clauseError("select", "GROUP BY", err)
+			return
+		}
 	}
 
 	if len(d.HavingParts) > 0 {
 		sql.WriteString(" HAVING ")
 		args, err = appendToSql(d.HavingParts, sql, " AND ", args)
 		if err != nil {
+			err = clauseError("select", "HAVING", err)
+			return
+		}
+	}
+
+	if len(d.Windows) > 0 {
+		sql.WriteString(" WINDOW ")
+		args, err = appendToSql(d.Windows, sql, ", ", args)
+		if err != nil {
+			err = clauseError("select", "WINDOW", err)
+			return
+		}
+	}
+
+	if len(d.SetOps) > 0 {
+		sql.WriteString(" ")
+		args, err = appendToSql(d.SetOps, sql, " ", args)
+		if err != nil {
+			err = clauseError("select", "set operation", err)
 			return
 		}
 	}
@@ -133,18 +309,70 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(" ORDER BY ")
 		args, err = appendToSql(d.OrderByParts, sql, ", ", args)
 		if err != nil {
+			err = clauseError("select", "ORDER BY", err)
 			return
 		}
 	}
 
-	if len(d.Limit) > 0 {
-		sql.WriteString(" LIMIT ")
-		sql.WriteString(d.Limit)
+	switch pagination {
+	case PaginationLimitOffset:
+		if d.Limit != nil {
+			sql.WriteString(" LIMIT ")
+			args, err = appendToSql([]Sqlizer{d.Limit}, sql, "", args)
+			if err != nil {
+				err = clauseError("select", "LIMIT", err)
+				return
+			}
+		}
+
+		if d.Offset != nil {
+			sql.WriteString(" OFFSET ")
+			args, err = appendToSql([]Sqlizer{d.Offset}, sql, "", args)
+			if err != nil {
+				err = clauseError("select", "OFFSET", err)
+				return
+			}
+		}
+	case PaginationOffsetFetch:
+		if d.Offset != nil || d.Limit != nil {
+			// SQL Server needs the OFFSET for a FETCH
+			sql.WriteString(" OFFSET ")
+			if d.Offset != nil {
+				args, err = appendToSql([]Sqlizer{d.Offset}, sql, "", args)
+				if err != nil {
+					err = clauseError("select", "OFFSET", err)
+					return
+				}
+			} else {
+				sql.WriteString("0")
+			}
+			sql.WriteString(" ROWS")
+		}
+
+		if d.Limit != nil {
+			sql.WriteString(" FETCH NEXT ")
+			args, err = appendToSql([]Sqlizer{d.Limit}, sql, "", args)
+			if err != nil {
+				err = clauseError("select", "FETCH", err)
+				return
+			}
+			sql.WriteString(" ROWS ONLY")
+		}
+	}
+
+	if len(d.Locks) > 0 {
+		sql.WriteString(" ")
+		args, err = appendToSql(d.Locks, sql, " ", args)
+		if err != nil {
+			err = clauseError("select", "FOR", err)
+			return
+		}
 	}
 
-	if len(d.Offset) > 0 {
-		sql.WriteString(" OFFSET ")
-		sql.WriteString(d.Offset)
+	if len(d.Hints) > 0 && dialect == dialectSQLServer {
+		sql.WriteString(" OPTION (")
+		sql.WriteString(strings.Join(d.Hints, ", "))
+		sql.WriteString(")")
 	}
 
 	if len(d.Suffixes) > 0 {
@@ -152,6 +380,7 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 
 		args, err = appendToSql(d.Suffixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("select", "suffix", err)
 			return
 		}
 	}
@@ -163,10 +392,29 @@ func (d *selectData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 // Builder
 
 // SelectBuilder builds SQL SELECT statements.
-type SelectBuilder builder.Builder
+type SelectBuilder struct {
+	data selectData
+	builderOptions
+}
+
+// build applies the middlewares set with WithMiddleware and returns the data
+// to render.
+func (b SelectBuilder) build() selectData {
+	if len(b.middlewares) > 0 {
+		mws := b.middlewares
+		b.middlewares = nil
+		b = applyMiddlewares(b, mws).(SelectBuilder)
+	}
+	return b.data
+}
 
-func init() {
-	builder.Register(SelectBuilder{}, selectData{})
+// forDialect writes the expressions of b for the database d, when b is a
+// subquery.
+func (b SelectBuilder) forDialect(d dialectKind, explicit bool) Sqlizer {
+	b.data = b.data.forDialect(d, explicit)
+	b.data.subquery = true
+	b.data.outer = d
+	return b
 }
 
 // Format methods
@@ -174,7 +422,8 @@ func init() {
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b SelectBuilder) PlaceholderFormat(f PlaceholderFormat) SelectBuilder {
-	return builder.Set(b, "PlaceholderFormat", f).(SelectBuilder)
+	b.data.PlaceholderFormat = f
+	return b
 }
 
 // Runner methods
@@ -184,24 +433,25 @@ func (b SelectBuilder) PlaceholderFormat(f PlaceholderFormat) SelectBuilder {
 //
 // Internally we use this to mock out the database connection for testing.
 func (b SelectBuilder) RunWith(runner BaseRunner) SelectBuilder {
-	return setRunWith(b, runner).(SelectBuilder)
+	b.data.RunWith = wrapRunner(runner)
+	return b
 }
 
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b SelectBuilder) Exec() (sql.Result, error) {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.Exec()
 }
 
 // Query builds and Querys the query with the Runner set by RunWith.
 func (b SelectBuilder) Query() (*sql.Rows, error) {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.Query()
 }
 
 // QueryRow builds and QueryRows the query with the Runner set by RunWith.
 func (b SelectBuilder) QueryRow() RowScanner {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.QueryRow()
 }
 
@@ -210,16 +460,21 @@ func (b SelectBuilder) Scan(dest ...interface{}) error {
 	return b.QueryRow().Scan(dest...)
 }
 
+// ToCountSql builds the query returned by CountOf.
+func (b SelectBuilder) ToCountSql() (string, []interface{}, error) {
+	return CountOf(b).ToSql()
+}
+
 // SQL methods
 
 // ToSql builds the query into a SQL string and bound args.
 func (b SelectBuilder) ToSql() (string, []interface{}, error) {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.ToSql()
 }
 
 func (b SelectBuilder) toSqlRaw() (string, []interface{}, error) {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.toSqlRaw()
 }
 
@@ -233,6 +488,64 @@ func (b SelectBuilder) MustSql() (string, []interface{}) {
 	return sql, args
 }
 
+// When calls fn with b and returns its result if cond is true, and returns b
+// otherwise, to add optional clauses without breaking the chain:
+//     Select("*").From("users").
+//         When(req.Team != "", func(b SelectBuilder) SelectBuilder {
+//             return b.Join("teams t ON t.id = users.team_id").Where(Eq{"t.name": req.Team})
+//         }).
+//         When(req.Limit > 0, func(b SelectBuilder) SelectBuilder { return b.Limit(req.Limit) })
+func (b SelectBuilder) When(cond bool, fn func(SelectBuilder) SelectBuilder) SelectBuilder {
+	if !cond {
+		return b
+	}
+	return fn(b)
+}
+
+// ToSqlInterpolated builds the query into a SQL string with the args inlined
+// as literals, for backends that don't support placeholders. The literals
+// are written for the database implied by the PlaceholderFormat.
+//
+// See InterpolateSqlizer.
+func (b SelectBuilder) ToSqlInterpolated() (string, error) {
+	return InterpolateSqlizer(b)
+}
+
+// Freeze renders the query once and returns it as a FrozenQuery, which keeps
+// the Runner set by RunWith.
+//
+// See FrozenQuery.
+func (b SelectBuilder) Freeze() FrozenQuery {
+	data := b.build()
+	q := Freeze(&data)
+	q.runWith = data.RunWith
+	return q
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query, e.g.
+// Pragma("TablePathPrefix", "/Root/db"). An empty value omits the parentheses.
+//
+// Pragmas are only rendered for the outermost query; those set on nested
+// builders are ignored.
+func (b SelectBuilder) Pragma(name, value string) SelectBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata, so that DBAs can attribute slow queries.
+//
+// Ex:
+//     Select("*").From("users").Comment("route=/api/users,handler=list")
+//     // /* route=/api/users,handler=list */ SELECT * FROM users
+//
+// Any "/*" or "*/" in comment is broken up so that it can't end the comment
+// early. Like pragmas, comments are only rendered for the outermost query.
+func (b SelectBuilder) Comment(comment string) SelectBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b SelectBuilder) Prefix(sql string, args ...interface{}) SelectBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -240,7 +553,36 @@ func (b SelectBuilder) Prefix(sql string, args ...interface{}) SelectBuilder {
 
 // PrefixExpr adds an expression to the very beginning of the query
 func (b SelectBuilder) PrefixExpr(expr Sqlizer) SelectBuilder {
-	return builder.Append(b, "Prefixes", expr).(SelectBuilder)
+	b.data.Prefixes = appendSqlizers(b.data.Prefixes, expr)
+	return b
+}
+
+// WithNamed adds a YQL named expression, "$name = expr;", in front of the
+// query, which refers to it by name:
+//     Select("*").From("orders").
+//         WithNamed("$ids", Select("id").From("users").Where(Eq{"active": true})).
+//         Where("user_id IN $ids")
+//     // $ids = SELECT id FROM users WHERE active = ?; SELECT * FROM orders WHERE user_id IN $ids
+func (b SelectBuilder) WithNamed(name string, expr Sqlizer) SelectBuilder {
+	return b.PrefixExpr(yqlNamedExpr{name: name, expr: expr, terminated: true})
+}
+
+// With adds a common table expression to the WITH clause of the query.
+//
+// Ex:
+//     Select("*").With("recent", Select("id").From("orders").Where("age < ?", 7)).From("recent")
+//     // WITH recent AS (SELECT id FROM orders WHERE age < ?) SELECT * FROM recent
+func (b SelectBuilder) With(alias string, query Sqlizer) SelectBuilder {
+	b.data.CTEs = appendSqlizers(b.data.CTEs, cte{alias: alias, query: query})
+	return b
+}
+
+// WithRecursive adds a common table expression to the WITH clause of the
+// query and marks the clause as WITH RECURSIVE.
+func (b SelectBuilder) WithRecursive(alias string, query Sqlizer) SelectBuilder {
+	b = b.With(alias, query)
+	b.data.RecursiveCTEs = true
+	return b
 }
 
 // Distinct adds a DISTINCT clause to the query.
@@ -248,25 +590,98 @@ func (b SelectBuilder) Distinct() SelectBuilder {
 	return b.Options("DISTINCT")
 }
 
+// DistinctOn adds a PostgreSQL DISTINCT ON (columns) clause to the query,
+// keeping only the first row of each set of rows with equal columns.
+//
+// Ex:
+//     Select("user_id", "kind").From("events").
+//         DistinctOn("user_id").OrderBy("user_id", "created_at DESC")
+//
+// Since no other database supports it, ToSql returns an error unless the
+// placeholder format of the outermost query is Dollar.
+func (b SelectBuilder) DistinctOn(columns ...string) SelectBuilder {
+	b.data.DistinctOn = appendStrings(b.data.DistinctOn, columns...)
+	return b
+}
+
+// Hint adds optimizer hints to the query, written where the database
+// expects them:
+//     Select("*").From("t1").Join("t2 USING (id)").Hint("JOIN_ORDER(t2, t1)")
+//     // SELECT /*+ JOIN_ORDER(t2, t1) */ * FROM t1 JOIN t2 USING (id)
+//     Select("*").From("t").Hint("MAXDOP 1").PlaceholderFormat(AtP)
+//     // SQL Server: SELECT * FROM t OPTION (MAXDOP 1)
+//     Select("*").From("t").Hint(`ydb.CostBasedOptimizer("on")`).PlaceholderFormat(DollarP)
+//     // YQL: PRAGMA ydb.CostBasedOptimizer("on"); SELECT * FROM t
+//
+// On YQL hints are pragmas, rendered like those set by Pragma, and on SQL
+// Server an OPTION clause, so both only go on the outermost query; ToSql
+// returns an error for hints on a subquery there.
+//
+// Hints may only hold identifiers, numbers, spaces, commas, dots, "=",
+// balanced parentheses and double-quoted words; ToSql returns an error for
+// any other hint.
+func (b SelectBuilder) Hint(hints ...string) SelectBuilder {
+	b.data.Hints = appendStrings(b.data.Hints, hints...)
+	return b
+}
+
+// isValidHint tells whether h only holds identifiers, numbers, spaces, ",",
+// ".", "=", balanced parentheses and double-quoted strings without quotes,
+// backslashes, "*" or parentheses, so that it can't end the comment, OPTION
+// or PRAGMA it is written in.
+func isValidHint(h string) bool {
+	if strings.TrimSpace(h) == "" {
+		return false
+	}
+	depth := 0
+	for i := 0; i < len(h); i++ {
+		c := h[i]
+		switch {
+		case isNameByte(c, false), c == ' ', c == ',', c == '.', c == '=':
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth--; depth < 0 {
+				return false
+			}
+		case c == '"':
+			for i++; i < len(h) && h[i] != '"'; i++ {
+				if c := h[i]; !isNameByte(c, false) && !strings.ContainsRune(" .,:/-", rune(c)) {
+					return false
+				}
+			}
+			if i == len(h) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return depth == 0
+}
+
 // Options adds select option to the query
 func (b SelectBuilder) Options(options ...string) SelectBuilder {
-	return builder.Extend(b, "Options", options).(SelectBuilder)
+	b.data.Options = appendStrings(b.data.Options, options...)
+	return b
 }
 
 // Columns adds result columns to the query.
 func (b SelectBuilder) Columns(columns ...string) SelectBuilder {
-	parts := make([]interface{}, 0, len(columns))
+	parts := make([]Sqlizer, 0, len(columns))
 	for _, str := range columns {
-		parts = append(parts, newPart(str))
+		parts = append(parts, newPart(b.quoteColumn(str)))
 	}
-	return builder.Extend(b, "Columns", parts).(SelectBuilder)
+	b.data.Columns = appendSqlizers(b.data.Columns, parts...)
+	return b
 }
 
 // RemoveColumns remove all columns from query.
 // Must add a new column with Column or Columns methods, otherwise
 // return a error.
 func (b SelectBuilder) RemoveColumns() SelectBuilder {
-	return builder.Delete(b, "Columns").(SelectBuilder)
+	b.data.Columns = nil
+	return b
 }
 
 // Column adds a result column to the query.
@@ -274,49 +689,207 @@ func (b SelectBuilder) RemoveColumns() SelectBuilder {
 // the columns string, for example:
 //   Column("IF(col IN ("+squirrel.Placeholders(3)+"), 1, 0) as col", 1, 2, 3)
 func (b SelectBuilder) Column(column interface{}, args ...interface{}) SelectBuilder {
-	return builder.Append(b, "Columns", newPart(column, args...)).(SelectBuilder)
+	switch c := column.(type) {
+	case string:
+		if len(args) == 0 {
+			column = b.quoteColumn(c)
+		}
+	case Ident:
+		column = b.ident(c)
+	}
+	b.data.Columns = appendSqlizers(b.data.Columns, newPart(column, args...))
+	return b
 }
 
 // From sets the FROM clause of the query.
 func (b SelectBuilder) From(from string) SelectBuilder {
-	return builder.Set(b, "From", newPart(from)).(SelectBuilder)
+	b.data.From = newPart(b.qualifyTable(from))
+	return b
 }
 
 // FromSelect sets a subquery into the FROM clause of the query.
 func (b SelectBuilder) FromSelect(from SelectBuilder, alias string) SelectBuilder {
 	// Prevent misnumbered parameters in nested selects (#183).
 	from = from.PlaceholderFormat(Question)
-	return builder.Set(b, "From", Alias(from, alias)).(SelectBuilder)
+	b.data.From = Alias(from, alias)
+	return b
+}
+
+// FromExpr sets a table expression, e.g. an aliased subquery or a function
+// call, into the FROM clause of the query.
+//
+// Ex:
+//     Select("s.id").FromExpr(Alias(sub, "s"))
+//     // SELECT s.id FROM (SELECT ...) AS s
+func (b SelectBuilder) FromExpr(from Sqlizer) SelectBuilder {
+	b.data.From = from
+	return b
+}
+
+// FromValues sets a VALUES list of literal rows into the FROM clause of the
+// query. values should be named with ValuesBuilder.As.
+func (b SelectBuilder) FromValues(values ValuesBuilder) SelectBuilder {
+	b.data.From = values
+	return b
+}
+
+// FromAsTable sets YQL "AS_TABLE($paramName)" into the FROM clause of the
+// query, selecting from list, a List<Struct> value bound as the parameter,
+// e.g. to join rows on the client with a table on YDB:
+//     Select("t.*").
+//         FromAsTable("$keys", keys).
+//         Join("items t ON t.id = id").
+//         PlaceholderFormat(NamedPlaceholders("$"))
+//     // SELECT t.* FROM AS_TABLE($keys) JOIN items t ON t.id = id
+//
+// list must be a value the driver can bind as a List<Struct>, such as a
+// ydb-go-sdk types.Value. With a NamedPlaceholders format the parameter is
+// bound as a database/sql.NamedArg named paramName without its "$"; with other
+// formats it is bound by position.
+func (b SelectBuilder) FromAsTable(paramName string, list interface{}) SelectBuilder {
+	name := strings.TrimPrefix(paramName, "$")
+	b.data.From = Expr("AS_TABLE(:"+name+")", NamedArgs{name: list})
+	return b
+}
+
+// ViewIndex makes the query read the FROM table through the given YDB
+// secondary index, i.e. "FROM table VIEW index".
+func (b SelectBuilder) ViewIndex(index string) SelectBuilder {
+	b.data.ViewIndex = index
+	return b
+}
+
+// FlattenBy adds a YQL FLATTEN BY clause to the FROM table, which turns each
+// row into one row per item of the given list, dict or optional columns.
+//
+// Ex:
+//     Select("id", "tag").From("posts").FlattenBy("tags AS tag").Where(Eq{"tag": "go"})
+//     // SELECT id, tag FROM posts FLATTEN BY tags AS tag WHERE tag = ?
+//
+// Calling it again replaces the columns.
+func (b SelectBuilder) FlattenBy(cols ...string) SelectBuilder {
+	return b.flattenBy("", cols)
+}
+
+// FlattenListBy adds a FLATTEN LIST BY clause to the FROM table. See FlattenBy.
+func (b SelectBuilder) FlattenListBy(cols ...string) SelectBuilder {
+	return b.flattenBy("LIST", cols)
+}
+
+// FlattenDictBy adds a FLATTEN DICT BY clause to the FROM table. See FlattenBy.
+func (b SelectBuilder) FlattenDictBy(cols ...string) SelectBuilder {
+	return b.flattenBy("DICT", cols)
+}
+
+// FlattenOptionalBy adds a FLATTEN OPTIONAL BY clause to the FROM table. See
+// FlattenBy.
+func (b SelectBuilder) FlattenOptionalBy(cols ...string) SelectBuilder {
+	return b.flattenBy("OPTIONAL", cols)
+}
+
+func (b SelectBuilder) flattenBy(mode string, cols []string) SelectBuilder {
+	b.data.FlattenMode = mode
+	b.data.FlattenBy = append([]string(nil), cols...)
+	return b
 }
 
 // JoinClause adds a join clause to the query.
 func (b SelectBuilder) JoinClause(pred interface{}, args ...interface{}) SelectBuilder {
-	return builder.Append(b, "Joins", newPart(pred, args...)).(SelectBuilder)
+	b.data.Joins = appendSqlizers(b.data.Joins, newPart(pred, args...))
+	return b
 }
 
 // Join adds a JOIN clause to the query.
 func (b SelectBuilder) Join(join string, rest ...interface{}) SelectBuilder {
-	return b.JoinClause("JOIN "+join, rest...)
+	return b.JoinClause("JOIN "+b.qualifyTable(join), rest...)
 }
 
 // LeftJoin adds a LEFT JOIN clause to the query.
 func (b SelectBuilder) LeftJoin(join string, rest ...interface{}) SelectBuilder {
-	return b.JoinClause("LEFT JOIN "+join, rest...)
+	return b.JoinClause("LEFT JOIN "+b.qualifyTable(join), rest...)
 }
 
 // RightJoin adds a RIGHT JOIN clause to the query.
 func (b SelectBuilder) RightJoin(join string, rest ...interface{}) SelectBuilder {
-	return b.JoinClause("RIGHT JOIN "+join, rest...)
+	return b.JoinClause("RIGHT JOIN "+b.qualifyTable(join), rest...)
 }
 
 // InnerJoin adds a INNER JOIN clause to the query.
 func (b SelectBuilder) InnerJoin(join string, rest ...interface{}) SelectBuilder {
-	return b.JoinClause("INNER JOIN "+join, rest...)
+	return b.JoinClause("INNER JOIN "+b.qualifyTable(join), rest...)
 }
 
 // CrossJoin adds a CROSS JOIN clause to the query.
 func (b SelectBuilder) CrossJoin(join string, rest ...interface{}) SelectBuilder {
-	return b.JoinClause("CROSS JOIN "+join, rest...)
+	return b.JoinClause("CROSS JOIN "+b.qualifyTable(join), rest...)
+}
+
+// JoinExpr adds a JOIN clause with a table expression, e.g. an aliased
+// subquery, to the query. on and args make up its ON condition, which is left
+// out if on is empty.
+//
+// Ex:
+//     Select("*").From("users u").
+//         JoinExpr(Alias(lastOrders, "o"), "o.user_id = u.id AND o.total > ?", 100)
+//     // SELECT * FROM users u JOIN (SELECT ...) AS o ON o.user_id = u.id AND o.total > ?
+func (b SelectBuilder) JoinExpr(join Sqlizer, on string, args ...interface{}) SelectBuilder {
+	return b.joinExpr("JOIN", join, on, args)
+}
+
+// LeftJoinExpr adds a LEFT JOIN clause with a table expression to the query.
+func (b SelectBuilder) LeftJoinExpr(join Sqlizer, on string, args ...interface{}) SelectBuilder {
+	return b.joinExpr("LEFT JOIN", join, on, args)
+}
+
+// RightJoinExpr adds a RIGHT JOIN clause with a table expression to the query.
+func (b SelectBuilder) RightJoinExpr(join Sqlizer, on string, args ...interface{}) SelectBuilder {
+	return b.joinExpr("RIGHT JOIN", join, on, args)
+}
+
+// InnerJoinExpr adds a INNER JOIN clause with a table expression to the query.
+func (b SelectBuilder) InnerJoinExpr(join Sqlizer, on string, args ...interface{}) SelectBuilder {
+	return b.joinExpr("INNER JOIN", join, on, args)
+}
+
+// CrossJoinExpr adds a CROSS JOIN clause with a table expression to the query.
+func (b SelectBuilder) CrossJoinExpr(join Sqlizer, on string, args ...interface{}) SelectBuilder {
+	return b.joinExpr("CROSS JOIN", join, on, args)
+}
+
+func (b SelectBuilder) joinExpr(kind string, join Sqlizer, on string, args []interface{}) SelectBuilder {
+	if on == "" {
+		return b.JoinClause(ConcatExpr(kind+" ", join))
+	}
+	return b.JoinClause(ConcatExpr(kind+" ", join, " ON ", Expr(on, args...)))
+}
+
+// JoinUsing adds a JOIN clause to the query that matches rows on equal
+// columns, i.e. "JOIN table USING (columns)".
+//
+// Ex:
+//     Select("*").From("orders").JoinUsing("customers", "customer_id", "region")
+//     // SELECT * FROM orders JOIN customers USING (customer_id, region)
+func (b SelectBuilder) JoinUsing(table string, columns ...string) SelectBuilder {
+	return b.joinUsing("JOIN", table, columns)
+}
+
+// LeftJoinUsing adds a LEFT JOIN ... USING clause to the query.
+func (b SelectBuilder) LeftJoinUsing(table string, columns ...string) SelectBuilder {
+	return b.joinUsing("LEFT JOIN", table, columns)
+}
+
+// RightJoinUsing adds a RIGHT JOIN ... USING clause to the query.
+func (b SelectBuilder) RightJoinUsing(table string, columns ...string) SelectBuilder {
+	return b.joinUsing("RIGHT JOIN", table, columns)
+}
+
+// InnerJoinUsing adds a INNER JOIN ... USING clause to the query.
+func (b SelectBuilder) InnerJoinUsing(table string, columns ...string) SelectBuilder {
+	return b.joinUsing("INNER JOIN", table, columns)
+}
+
+func (b SelectBuilder) joinUsing(join, table string, columns []string) SelectBuilder {
+	return b.JoinClause(fmt.Sprintf("%s %s USING (%s)", join, b.qualifyTable(table), strings.Join(columns, ", ")))
 }
 
 // Where adds an expression to the WHERE clause of the query.
@@ -343,24 +916,137 @@ func (b SelectBuilder) Where(pred interface{}, args ...interface{}) SelectBuilde
 	if pred == nil || pred == "" {
 		return b
 	}
-	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(SelectBuilder)
+	b.data.WhereParts = appendSqlizers(b.data.WhereParts, newWherePart(pred, args...))
+	return b
 }
 
 // GroupBy adds GROUP BY expressions to the query.
 func (b SelectBuilder) GroupBy(groupBys ...string) SelectBuilder {
-	return builder.Extend(b, "GroupBys", groupBys).(SelectBuilder)
+	parts := make([]Sqlizer, len(groupBys))
+	for i, groupBy := range groupBys {
+		parts[i] = newPart(groupBy)
+	}
+	b.data.GroupBys = appendSqlizers(b.data.GroupBys, parts...)
+	return b
+}
+
+// GroupByClause adds a GROUP BY expression with args, or a Sqlizer, to the
+// query.
+//
+// Ex:
+//     Select("DATE_TRUNC(?, created_at) AS period", "COUNT(*)").From("orders").
+//         GroupByClause("DATE_TRUNC(?, created_at)", "month")
+//     Select("COUNT(*)").From("users").GroupByClause(JSONGet("profile", "country"))
+func (b SelectBuilder) GroupByClause(pred interface{}, args ...interface{}) SelectBuilder {
+	b.data.GroupBys = appendSqlizers(b.data.GroupBys, newPart(pred, args...))
+	return b
+}
+
+// GroupByIdent adds columns to the GROUP BY clause of the query, checking that
+// each is a plain identifier.
+//
+// See Ident.
+func (b SelectBuilder) GroupByIdent(columns ...Ident) SelectBuilder {
+	parts := make([]Sqlizer, len(columns))
+	for i, column := range columns {
+		parts[i] = b.ident(column)
+	}
+	b.data.GroupBys = appendSqlizers(b.data.GroupBys, parts...)
+	return b
+}
+
+// GroupByRollup adds a ROLLUP(columns) grouping to the GROUP BY clause of the
+// query, which also aggregates over each prefix of columns and over all rows.
+//
+// Ex:
+//     Select("year", "month", "SUM(total)").From("sales").GroupByRollup("year", "month")
+//     // SELECT year, month, SUM(total) FROM sales GROUP BY ROLLUP(year, month)
+//
+// MySQL writes this as "GROUP BY year, month WITH ROLLUP" instead; use
+// GroupBy and Suffix for it.
+func (b SelectBuilder) GroupByRollup(columns ...string) SelectBuilder {
+	return b.GroupBy("ROLLUP(" + strings.Join(columns, ", ") + ")")
+}
+
+// GroupByCube adds a CUBE(columns) grouping to the GROUP BY clause of the
+// query, which also aggregates over every subset of columns.
+func (b SelectBuilder) GroupByCube(columns ...string) SelectBuilder {
+	return b.GroupBy("CUBE(" + strings.Join(columns, ", ") + ")")
+}
+
+// GroupByGroupingSets adds a GROUPING SETS grouping to the GROUP BY clause of
+// the query, which aggregates over each of sets. An empty set aggregates over
+// all rows.
+//
+// Ex:
+//     GroupByGroupingSets([]string{"year", "month"}, []string{"region"}, nil)
+//     // GROUP BY GROUPING SETS ((year, month), (region), ())
+func (b SelectBuilder) GroupByGroupingSets(sets ...[]string) SelectBuilder {
+	groups := make([]string, len(sets))
+	for i, set := range sets {
+		groups[i] = "(" + strings.Join(set, ", ") + ")"
+	}
+	return b.GroupBy("GROUPING SETS (" + strings.Join(groups, ", ") + ")")
 }
 
 // Having adds an expression to the HAVING clause of the query.
 //
-// See Where.
+// It accepts the same preds as Where, including maps like Eq and Gt:
+//     Select("user_id", "COUNT(*)").From("orders").GroupBy("user_id").
+//         Having(Gt{"COUNT(*)": 5})
+//     // ... GROUP BY user_id HAVING COUNT(*) > ?
 func (b SelectBuilder) Having(pred interface{}, rest ...interface{}) SelectBuilder {
-	return builder.Append(b, "HavingParts", newWherePart(pred, rest...)).(SelectBuilder)
+	if pred == nil || pred == "" {
+		return b
+	}
+	b.data.HavingParts = appendSqlizers(b.data.HavingParts, newWherePart(pred, rest...))
+	return b
+}
+
+// Window adds a named window to the WINDOW clause of the query. Window
+// functions can refer to it by name with Over.
+func (b SelectBuilder) Window(name string, window WindowBuilder) SelectBuilder {
+	b.data.Windows = appendSqlizers(b.data.Windows, namedWindow{name: name, window: window})
+	return b
+}
+
+// Union combines the query with other using UNION.
+//
+// Set operations are rendered after HAVING, so ORDER BY, LIMIT and OFFSET set
+// on b apply to the combined result.
+func (b SelectBuilder) Union(other SelectBuilder) SelectBuilder {
+	b.data.SetOps = appendSqlizers(b.data.SetOps, setOp{op: "UNION", query: other})
+	return b
+}
+
+// UnionAll combines the query with other using UNION ALL.
+//
+// See Union.
+func (b SelectBuilder) UnionAll(other SelectBuilder) SelectBuilder {
+	b.data.SetOps = appendSqlizers(b.data.SetOps, setOp{op: "UNION ALL", query: other})
+	return b
+}
+
+// Intersect combines the query with other using INTERSECT.
+//
+// See Union.
+func (b SelectBuilder) Intersect(other SelectBuilder) SelectBuilder {
+	b.data.SetOps = appendSqlizers(b.data.SetOps, setOp{op: "INTERSECT", query: other})
+	return b
+}
+
+// Except combines the query with other using EXCEPT.
+//
+// See Union.
+func (b SelectBuilder) Except(other SelectBuilder) SelectBuilder {
+	b.data.SetOps = appendSqlizers(b.data.SetOps, setOp{op: "EXCEPT", query: other})
+	return b
 }
 
 // OrderByClause adds ORDER BY clause to the query.
 func (b SelectBuilder) OrderByClause(pred interface{}, args ...interface{}) SelectBuilder {
-	return builder.Append(b, "OrderByParts", newPart(pred, args...)).(SelectBuilder)
+	b.data.OrderByParts = appendSqlizers(b.data.OrderByParts, newPart(pred, args...))
+	return b
 }
 
 // OrderBy adds ORDER BY expressions to the query.
@@ -372,24 +1058,191 @@ func (b SelectBuilder) OrderBy(orderBys ...string) SelectBuilder {
 	return b
 }
 
+// OrderBySafe adds "col ASC" or "col DESC" to the ORDER BY clause of the
+// query. Unlike OrderBy, it is safe to use with a user supplied column: ToSql
+// returns an error if col isn't a plain identifier.
+//
+// Ex:
+//     dir, err := ParseDirection(r.URL.Query().Get("dir"))
+//     ...
+//     Select("*").From("users").OrderBySafe(Ident(r.URL.Query().Get("sort")), dir)
+//
+// Use an allow-list of sortable columns on top of it where users mustn't sort
+// by every column.
+func (b SelectBuilder) OrderBySafe(col Ident, dir Direction) SelectBuilder {
+	b.data.OrderByParts = appendSqlizers(b.data.OrderByParts, orderTerm{expr: b.ident(col), dir: dir})
+	return b
+}
+
+// OrderByCol adds "col ASC" or "col DESC" to the ORDER BY clause of the
+// query, with NULLS FIRST/LAST if nulls is given. col is written as it is;
+// use OrderBySafe for user supplied columns.
+func (b SelectBuilder) OrderByCol(col string, dir Direction, nulls ...Nulls) SelectBuilder {
+	term := orderTerm{expr: newPart(col), dir: dir}
+	if len(nulls) > 0 {
+		term.nulls = nulls[len(nulls)-1]
+	}
+	b.data.OrderByParts = appendSqlizers(b.data.OrderByParts, term)
+	return b
+}
+
+// SeekAfter sets up keyset (seek) pagination: it orders the query by
+// orderCols and, unless lastValues is empty, keeps only the rows that come
+// after lastValues, the order values of the last row of the previous page.
+//
+// Ex:
+//     Select("*").From("events").
+//         SeekAfter([]string{"created_at", "id"}, []interface{}{lastCreatedAt, lastID}).
+//         Limit(50)
+//     // SELECT * FROM events WHERE (created_at, id) > (?, ?) ORDER BY created_at, id LIMIT 50
+//
+// A column may be followed by ASC or DESC. Columns sorted in different
+// directions are compared one by one, as in
+// "(a > ? OR (a = ? AND b < ?))".
+//
+// Pass nil lastValues for the first page. ToSql returns an error if
+// lastValues is not empty and doesn't match orderCols.
+func (b SelectBuilder) SeekAfter(orderCols []string, lastValues []interface{}) SelectBuilder {
+	b = b.OrderBy(orderCols...)
+	if len(lastValues) == 0 {
+		return b
+	}
+
+	seek := seekExpr{
+		columns: make([]string, len(orderCols)),
+		desc:    make([]bool, len(orderCols)),
+		values:  lastValues,
+	}
+	for i, col := range orderCols {
+		seek.columns[i] = col
+		fields := strings.Fields(col)
+		if len(fields) == 2 {
+			switch strings.ToUpper(fields[1]) {
+			case "ASC":
+				seek.columns[i] = fields[0]
+			case "DESC":
+				seek.columns[i], seek.desc[i] = fields[0], true
+			}
+		}
+	}
+	b.data.WhereParts = appendSqlizers(b.data.WhereParts, seek)
+	return b
+}
+
 // Limit sets a LIMIT clause on the query.
 func (b SelectBuilder) Limit(limit uint64) SelectBuilder {
-	return builder.Set(b, "Limit", fmt.Sprintf("%d", limit)).(SelectBuilder)
+	b.data.Limit = b.limitPart(limit)
+	return b
+}
+
+// LimitParam sets a LIMIT clause on the query with the limit bound as a
+// placeholder arg rather than inlined into the SQL.
+func (b SelectBuilder) LimitParam(limit uint64) SelectBuilder {
+	b.data.Limit = newPart("?", limit)
+	return b
 }
 
 // Limit ALL allows to access all records with limit
 func (b SelectBuilder) RemoveLimit() SelectBuilder {
-	return builder.Delete(b, "Limit").(SelectBuilder)
+	b.data.Limit = nil
+	return b
 }
 
 // Offset sets a OFFSET clause on the query.
 func (b SelectBuilder) Offset(offset uint64) SelectBuilder {
-	return builder.Set(b, "Offset", fmt.Sprintf("%d", offset)).(SelectBuilder)
+	b.data.Offset = b.limitPart(offset)
+	return b
+}
+
+// OffsetParam sets an OFFSET clause on the query with the offset bound as a
+// placeholder arg rather than inlined into the SQL.
+func (b SelectBuilder) OffsetParam(offset uint64) SelectBuilder {
+	b.data.Offset = newPart("?", offset)
+	return b
 }
 
 // RemoveOffset removes OFFSET clause.
 func (b SelectBuilder) RemoveOffset() SelectBuilder {
-	return builder.Delete(b, "Offset").(SelectBuilder)
+	b.data.Offset = nil
+	return b
+}
+
+// Pagination is the syntax in which the Limit and Offset of a query are
+// written.
+type Pagination int
+
+const (
+	// PaginationAuto uses the syntax of the Dialect: OFFSET/FETCH for Oracle,
+	// TOP or, with an offset, OFFSET/FETCH for SQL Server, and LIMIT/OFFSET
+	// otherwise, including when there is no Dialect.
+	PaginationAuto Pagination = iota
+	// PaginationLimitOffset writes "LIMIT n OFFSET m".
+	PaginationLimitOffset
+	// PaginationOffsetFetch writes the standard "OFFSET m ROWS FETCH NEXT n
+	// ROWS ONLY", for Oracle 12c+ and SQL Server 2012+. SQL Server also
+	// requires an ORDER BY clause with it.
+	PaginationOffsetFetch
+	// PaginationTop writes SQL Server's "SELECT TOP (n)". It can't be used
+	// with an offset.
+	PaginationTop
+)
+
+// Pagination sets the syntax of the Limit and Offset of the query, overriding
+// that of the Dialect:
+//     Select("*").From("users").OrderBy("id").Offset(20).Limit(10).
+//         Pagination(PaginationOffsetFetch)
+//     // SELECT * FROM users ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY
+func (b SelectBuilder) Pagination(p Pagination) SelectBuilder {
+	b.data.Pagination = p
+	return b
+}
+
+// pagination returns the syntax to write Limit and Offset in.
+func (d *selectData) pagination() (Pagination, error) {
+	p := d.Pagination
+	if p == PaginationAuto {
+		p = PaginationLimitOffset
+		if df, ok := d.PlaceholderFormat.(dialectFormat); ok {
+			switch df.dialect {
+			case dialectOracle:
+				p = PaginationOffsetFetch
+			case dialectSQLServer:
+				p = PaginationTop
+				if d.Offset != nil {
+					p = PaginationOffsetFetch
+				}
+			}
+		}
+	}
+
+	switch p {
+	case PaginationLimitOffset, PaginationOffsetFetch:
+	case PaginationTop:
+		if d.Offset != nil {
+			return p, clauseError("select", "TOP", errors.New("can't be used with Offset; use PaginationOffsetFetch"))
+		}
+	default:
+		return p, builderError("select", fmt.Sprintf("invalid pagination %d", int(p)))
+	}
+	return p, nil
+}
+
+// LockFor adds a row-locking clause to the query, e.g. FOR UPDATE.
+//
+// Ex:
+//     Select("*").From("jobs").Where(Eq{"state": "queued"}).Limit(10).
+//         LockFor(LockUpdate, LockOf("jobs"), SkipLocked())
+//     // SELECT * FROM jobs WHERE state = ? LIMIT 10 FOR UPDATE OF jobs SKIP LOCKED
+//
+// Calling LockFor more than once adds more clauses, e.g. to lock the rows of
+// joined tables with a different strength.
+func (b SelectBuilder) LockFor(strength LockStrength, opts ...LockOption) SelectBuilder {
+	lock := lockClause{strength: strength}
+	for _, opt := range opts {
+		opt(&lock)
+	}
+	b.data.Locks = appendSqlizers(b.data.Locks, lock)
+	return b
 }
 
 // Suffix adds an expression to the end of the query
@@ -399,5 +1252,47 @@ func (b SelectBuilder) Suffix(sql string, args ...interface{}) SelectBuilder {
 
 // SuffixExpr adds an expression to the end of the query
 func (b SelectBuilder) SuffixExpr(expr Sqlizer) SelectBuilder {
-	return builder.Append(b, "Suffixes", expr).(SelectBuilder)
+	b.data.Suffixes = appendSqlizers(b.data.Suffixes, expr)
+	return b
+}
+
+// CountOf returns a query counting the rows of b, e.g. for the total of a
+// paginated listing.
+//
+// ORDER BY, LIMIT, OFFSET and locking clauses are removed and the result columns are replaced
+// with COUNT(*); FROM, joins, WHERE and their args are kept. Queries whose row
+// count depends on the result columns, i.e. with GROUP BY, HAVING, DISTINCT or
+// set operations, are counted as a subquery instead:
+//     SELECT COUNT(*) FROM (SELECT ...) AS count_query
+//
+// Ex:
+//     users := Select("id", "name").From("users").Where(Eq{"active": true}).
+//         OrderBy("name").Limit(20)
+//     CountOf(users).RunWith(db).Scan(&total)
+//     // SELECT COUNT(*) FROM users WHERE active = ?
+func CountOf(b SelectBuilder) SelectBuilder {
+	b.data.OrderByParts = nil
+	b.data.Limit = nil
+	b.data.Offset = nil
+	b.data.Locks = nil
+
+	d := b.data
+	if len(d.GroupBys) == 0 && len(d.HavingParts) == 0 && len(d.Options) == 0 && len(d.DistinctOn) == 0 && len(d.SetOps) == 0 {
+		b.data.Columns = []Sqlizer{newPart("COUNT(*)")}
+		b.data.Windows = nil
+		return b
+	}
+
+	// the outer query gets the options and comments of b, but not its
+	// middlewares, which already run on b itself
+	count := SelectBuilder{data: selectData{
+		PlaceholderFormat: d.PlaceholderFormat,
+		RunWith:           d.RunWith,
+		Pragmas:           d.Pragmas,
+		Comments:          d.Comments,
+	}, builderOptions: b.builderOptions}
+	count.middlewares = nil
+	b.data.Pragmas = nil
+	b.data.Comments = nil
+	return count.Column("COUNT(*)").FromSelect(b, "count_query")
 }
@@ -5,8 +5,6 @@ package squirrel
 import (
 	"context"
 	"database/sql"
-
-	"github.com/lann/builder"
 )
 
 func (d *selectData) ExecContext(ctx context.Context) (sql.Result, error) {
@@ -47,19 +45,19 @@ func (d *selectData) QueryRowContext(ctx context.Context) RowScanner {
 
 // ExecContext builds and ExecContexts the query with the Runner set by RunWith.
 func (b SelectBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.ExecContext(ctx)
 }
 
 // QueryContext builds and QueryContexts the query with the Runner set by RunWith.
 func (b SelectBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.QueryContext(ctx)
 }
 
 // QueryRowContext builds and QueryRowContexts the query with the Runner set by RunWith.
 func (b SelectBuilder) QueryRowContext(ctx context.Context) RowScanner {
-	data := builder.GetStruct(b).(selectData)
+	data := b.build()
 	return data.QueryRowContext(ctx)
 }
 
@@ -91,6 +91,28 @@ func TestSelectBuilderFromSelectNestedDollarPlaceholders(t *testing.T) {
 	assert.Equal(t, expectedArgs, args)
 }
 
+func TestSelectBuilderFromExprAndJoinExpr(t *testing.T) {
+	users := Select("id").From("users").Where(Eq{"active": true})
+	orders := Select("user_id", "SUM(total) AS total").From("orders").Where(Gt{"total": 10}).GroupBy("user_id")
+	b := Select("u.id", "o.total").
+		FromExpr(Alias(users, "u")).
+		LeftJoinExpr(Alias(orders, "o"), "o.user_id = u.id AND o.total < ?", 1000).
+		CrossJoinExpr(Alias(Expr("generate_series(1, ?)", 3), "n"), "").
+		Where(Eq{"u.id": 5}).
+		PlaceholderFormat(Dollar)
+	sql, args, err := b.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT u.id, o.total " +
+		"FROM (SELECT id FROM users WHERE active = $1) AS u " +
+		"LEFT JOIN (SELECT user_id, SUM(total) AS total FROM orders WHERE total > $2 GROUP BY user_id) AS o " +
+		"ON o.user_id = u.id AND o.total < $3 " +
+		"CROSS JOIN (generate_series(1, $4)) AS n " +
+		"WHERE u.id = $5"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{true, 10, 1000, 3, 5}, args)
+}
+
 func TestSelectBuilderToSqlErr(t *testing.T) {
 	_, _, err := Select().From("x").ToSql()
 	assert.Error(t, err)
@@ -110,6 +132,9 @@ func TestSelectBuilderPlaceholders(t *testing.T) {
 
 	sql, _, _ = b.PlaceholderFormat(AtP).ToSql()
 	assert.Equal(t, "SELECT test WHERE x = @p1 AND y = @p2", sql)
+
+	sql, _, _ = b.PlaceholderFormat(DollarP).ToSql()
+	assert.Equal(t, "SELECT test WHERE x = $p1 AND y = $p2", sql)
 }
 
 func TestSelectBuilderRunners(t *testing.T) {
@@ -461,3 +486,518 @@ func TestRemoveColumns(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "SELECT name FROM users", sql)
 }
+
+func TestSelectBuilderLimitOffsetParam(t *testing.T) {
+	sql, args, err := Select("*").
+		From("foo").
+		Where("x = ?", 1).
+		LimitParam(10).
+		OffsetParam(20).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM foo WHERE x = $1 LIMIT $2 OFFSET $3", sql)
+	assert.Equal(t, []interface{}{1, uint64(10), uint64(20)}, args)
+
+	sql, args, err = Select("*").From("foo").LimitParam(10).RemoveLimit().ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM foo", sql)
+	assert.Empty(t, args)
+}
+
+func TestSelectBuilderPagination(t *testing.T) {
+	sql, args, err := Select("*").From("foo").OrderBy("id").
+		LimitParam(10).
+		OffsetParam(20).
+		Pagination(PaginationOffsetFetch).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM foo ORDER BY id OFFSET $1 ROWS FETCH NEXT $2 ROWS ONLY", sql)
+	assert.Equal(t, []interface{}{uint64(20), uint64(10)}, args)
+
+	sql, _, err = Select("*").From("foo").OrderBy("id").Limit(10).Pagination(PaginationOffsetFetch).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM foo ORDER BY id OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", sql)
+
+	sql, args, err = Select("id").Distinct().From("foo").Where("x = ?", 1).
+		LimitParam(5).
+		Pagination(PaginationTop).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT TOP (?) id FROM foo WHERE x = ?", sql)
+	assert.Equal(t, []interface{}{uint64(5), 1}, args)
+
+	_, _, err = Select("*").From("foo").Limit(5).Offset(5).Pagination(PaginationTop).ToSql()
+	assert.EqualError(t, err, "select builder: TOP: can't be used with Offset; use PaginationOffsetFetch")
+
+	_, _, err = Select("*").From("foo").Pagination(Pagination(9)).ToSql()
+	assert.EqualError(t, err, "select builder: invalid pagination 9")
+}
+
+func TestSelectBuilderDialectPagination(t *testing.T) {
+	mssql := StatementBuilder.Dialect(SQLServer)
+
+	sql, _, err := mssql.Select("*").From("foo").Limit(10).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT TOP (10) * FROM [foo]", sql)
+
+	sql, _, err = mssql.Select("*").From("foo").OrderBy("id").Limit(10).Offset(20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM [foo] ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", sql)
+
+	sql, _, err = StatementBuilder.Dialect(Oracle).Select("*").From("foo").Limit(10).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "foo" OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY`, sql)
+
+	sql, _, err = StatementBuilder.Dialect(Oracle).Select("*").From("foo").Limit(10).Pagination(PaginationLimitOffset).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM "foo" LIMIT 10`, sql)
+
+	// without a Dialect the placeholder format doesn't change the syntax
+	sql, _, err = Select("*").From("foo").Limit(10).PlaceholderFormat(AtP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM foo LIMIT 10", sql)
+}
+
+func TestSelectBuilderWith(t *testing.T) {
+	recent := Select("id").From("orders").Where("age < ?", 7).PlaceholderFormat(Dollar)
+	big := Select("id").From("orders").Where(Gt{"total": 100})
+
+	sql, args, err := Select("*").
+		With("recent", recent).
+		With("big", big).
+		From("recent").
+		Join("big USING (id)").
+		Where("id > ?", 3).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH recent AS (SELECT id FROM orders WHERE age < $1), " +
+		"big AS (SELECT id FROM orders WHERE total > $2) " +
+		"SELECT * FROM recent JOIN big USING (id) WHERE id > $3"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{7, 100, 3}, args)
+}
+
+func TestSelectBuilderWithRecursive(t *testing.T) {
+	tree := Select("id", "parent_id").From("nodes").Where(Eq{"id": 1}).
+		Suffix("UNION ALL SELECT n.id, n.parent_id FROM nodes n JOIN tree t ON n.parent_id = t.id")
+
+	sql, args, err := Select("id").WithRecursive("tree", tree).From("tree").ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "WITH RECURSIVE tree AS (SELECT id, parent_id FROM nodes WHERE id = ? " +
+		"UNION ALL SELECT n.id, n.parent_id FROM nodes n JOIN tree t ON n.parent_id = t.id) " +
+		"SELECT id FROM tree"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestSelectBuilderSetOps(t *testing.T) {
+	q1 := Select("a").From("t1").Where("b = ?", 1)
+	q2 := Select("a").From("t2").Where("b = ?", 2).PlaceholderFormat(Dollar)
+	q3 := Select("a").From("t3").Where("b = ?", 3)
+	q4 := Select("a").From("t4").Where("b = ?", 4)
+	q5 := Select("a").From("t5").Where("b = ?", 5)
+
+	sql, args, err := q1.
+		Union(q2).
+		UnionAll(q3).
+		Intersect(q4).
+		Except(q5).
+		OrderBy("a").
+		LimitParam(10).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT a FROM t1 WHERE b = $1 " +
+		"UNION SELECT a FROM t2 WHERE b = $2 " +
+		"UNION ALL SELECT a FROM t3 WHERE b = $3 " +
+		"INTERSECT SELECT a FROM t4 WHERE b = $4 " +
+		"EXCEPT SELECT a FROM t5 WHERE b = $5 " +
+		"ORDER BY a LIMIT $6"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3, 4, 5, uint64(10)}, args)
+}
+
+func TestSelectBuilderSetOpsErr(t *testing.T) {
+	_, _, err := Select("a").Union(Select()).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderViewIndex(t *testing.T) {
+	sql, args, err := Select("id").
+		From("users").
+		ViewIndex("users_by_email").
+		Where(Eq{"email": "a@b.c"}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users VIEW users_by_email WHERE email = ?", sql)
+	assert.Equal(t, []interface{}{"a@b.c"}, args)
+}
+
+func TestSelectBuilderFlattenBy(t *testing.T) {
+	sql, args, err := Select("id", "tag").
+		From("posts").
+		FlattenBy("tags AS tag").
+		Where(Eq{"tag": "go"}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, tag FROM posts FLATTEN BY tags AS tag WHERE tag = ?", sql)
+	assert.Equal(t, []interface{}{"go"}, args)
+
+	sql, _, err = Select("*").
+		From("posts p").
+		FlattenListBy("tags", "authors").
+		Join("users u ON u.id = p.authors").
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM posts p FLATTEN LIST BY (tags, authors) JOIN users u ON u.id = p.authors", sql)
+
+	sql, _, err = Select("*").From("t").FlattenDictBy("d").FlattenOptionalBy("o").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t FLATTEN OPTIONAL BY o", sql)
+}
+
+func TestSelectBuilderFromAsTable(t *testing.T) {
+	keys := []map[string]interface{}{{"id": 1}, {"id": 2}}
+
+	sqlStr, args, err := Select("t.*").
+		FromAsTable("$keys", keys).
+		Join("items t ON t.id = id").
+		Where(Eq{"t.active": true}).
+		PlaceholderFormat(NamedPlaceholders("$")).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT t.* FROM AS_TABLE($keys) JOIN items t ON t.id = id WHERE t.active = $p2", sqlStr)
+	assert.Equal(t, []interface{}{sql.Named("keys", keys), sql.Named("p2", true)}, args)
+
+	sqlStr, args, err = Select("*").FromAsTable("keys", keys).PlaceholderFormat(DollarP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM AS_TABLE($p1)", sqlStr)
+	assert.Equal(t, []interface{}{keys}, args)
+}
+
+func TestSelectBuilderImmutable(t *testing.T) {
+	base := Select("a").From("t").Where("x = ?", 1)
+	b1 := base.Where("y = ?", 2).OrderBy("a")
+	b2 := base.Where("z = ?", 3)
+
+	sql, args, err := base.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE x = ?", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = b1.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE x = ? AND y = ? ORDER BY a", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+
+	sql, args, err = b2.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE x = ? AND z = ?", sql)
+	assert.Equal(t, []interface{}{1, 3}, args)
+}
+
+func BenchmarkSelectBuilder(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Select("id", "name").
+			From("users").
+			Join("emails USING (email_id)").
+			Where(Eq{"id": i}).
+			OrderBy("name").
+			Limit(10).
+			PlaceholderFormat(Dollar).
+			ToSql()
+	}
+}
+
+func TestSelectBuilderSeekAfter(t *testing.T) {
+	b := Select("*").From("events")
+
+	sql, args, err := b.SeekAfter([]string{"created_at", "id"}, nil).Limit(2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events ORDER BY created_at, id LIMIT 2", sql)
+	assert.Empty(t, args)
+
+	sql, args, err = b.Where("kind = ?", "click").
+		SeekAfter([]string{"created_at", "id"}, []interface{}{10, 5}).
+		Limit(2).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE kind = ? AND (created_at, id) > (?, ?) "+
+		"ORDER BY created_at, id LIMIT 2", sql)
+	assert.Equal(t, []interface{}{"click", 10, 5}, args)
+
+	sql, args, err = b.SeekAfter([]string{"id DESC"}, []interface{}{5}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE id < ? ORDER BY id DESC", sql)
+	assert.Equal(t, []interface{}{5}, args)
+
+	sql, args, err = b.SeekAfter([]string{"score DESC", "id"}, []interface{}{7, 5}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM events WHERE (score < ? OR (score = ? AND id > ?)) "+
+		"ORDER BY score DESC, id", sql)
+	assert.Equal(t, []interface{}{7, 7, 5}, args)
+
+	_, _, err = b.SeekAfter([]string{"a", "b"}, []interface{}{1}).ToSql()
+	assert.Error(t, err)
+}
+
+func TestCountOf(t *testing.T) {
+	b := Select("id", "name").
+		From("users u").
+		Join("teams t ON t.id = u.team_id").
+		Where(Eq{"u.active": true}).
+		OrderBy("name").
+		Limit(20).
+		Offset(40).
+		PlaceholderFormat(Dollar)
+
+	sql, args, err := b.ToCountSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users u JOIN teams t ON t.id = u.team_id WHERE u.active = $1", sql)
+	assert.Equal(t, []interface{}{true}, args)
+
+	// the original query is unchanged
+	sql, _, err = b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name FROM users u JOIN teams t ON t.id = u.team_id "+
+		"WHERE u.active = $1 ORDER BY name LIMIT 20 OFFSET 40", sql)
+}
+
+func TestCountOfGrouped(t *testing.T) {
+	b := Select("team_id", "COUNT(*)").
+		From("users").
+		Where("age > ?", 18).
+		GroupBy("team_id").
+		Having("COUNT(*) > ?", 2).
+		OrderBy("team_id").
+		PlaceholderFormat(Dollar).
+		Pragma("TablePathPrefix", "/Root")
+
+	sql, args, err := CountOf(b).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "PRAGMA TablePathPrefix(\"/Root\"); SELECT COUNT(*) FROM ("+
+		"SELECT team_id, COUNT(*) FROM users WHERE age > $1 GROUP BY team_id HAVING COUNT(*) > $2"+
+		") AS count_query", sql)
+	assert.Equal(t, []interface{}{18, 2}, args)
+
+	sql, _, err = Select("a").Distinct().From("t").ToCountSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT a FROM t) AS count_query", sql)
+}
+
+func TestCountOfGroupedOptions(t *testing.T) {
+	b := StatementBuilder.QuoteIdentifiers(DoubleQuoteQuoter).
+		Select("team_id").From("users").GroupBy("team_id").Comment("teams")
+
+	sql, _, err := b.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `/* teams */ SELECT "team_id" FROM "users" GROUP BY team_id`, sql)
+
+	sql, _, err = CountOf(b).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `/* teams */ SELECT COUNT(*) FROM (SELECT "team_id" FROM "users" GROUP BY team_id) AS count_query`, sql)
+}
+
+func TestCountOfMiddleware(t *testing.T) {
+	sb := StatementBuilder.WithMiddleware(func(b interface{}) interface{} {
+		if sel, ok := b.(SelectBuilder); ok {
+			return sel.Where(Eq{"tenant_id": 7})
+		}
+		return b
+	})
+
+	sql, args, err := CountOf(sb.Select("user_id").From("events").GroupBy("user_id")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT user_id FROM events WHERE tenant_id = ? GROUP BY user_id) AS count_query", sql)
+	assert.Equal(t, []interface{}{7}, args)
+
+	sql, args, err = CountOf(sb.Select("user_id").From("events")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM events WHERE tenant_id = ?", sql)
+	assert.Equal(t, []interface{}{7}, args)
+}
+
+func TestCountOfRunWith(t *testing.T) {
+	db := &DBStub{}
+	var total int
+	err := CountOf(Select("id").From("users").RunWith(db)).Scan(&total)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM users", db.LastQueryRowSql)
+}
+
+func TestSelectBuilderDistinctOn(t *testing.T) {
+	b := Select("user_id", "kind").
+		From("events").
+		DistinctOn("user_id").
+		Where("kind <> ?", "noop").
+		OrderBy("user_id", "created_at DESC")
+
+	sql, args, err := b.PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT ON (user_id) user_id, kind FROM events WHERE kind <> $1 "+
+		"ORDER BY user_id, created_at DESC", sql)
+	assert.Equal(t, []interface{}{"noop"}, args)
+
+	_, _, err = b.ToSql()
+	assert.Error(t, err)
+	_, _, err = b.PlaceholderFormat(DollarP).ToSql()
+	assert.Error(t, err)
+
+	sql, _, err = CountOf(b.PlaceholderFormat(Dollar)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM (SELECT DISTINCT ON (user_id) user_id, kind FROM events "+
+		"WHERE kind <> $1) AS count_query", sql)
+
+	// subqueries are checked against the dialect of the outermost query
+	_, _, err = StatementBuilder.Dialect(MySQL).Select("*").FromSelect(b, "e").ToSql()
+	assert.EqualError(t, err, "select builder: FROM: select builder: DISTINCT ON: only supported by PostgreSQL; use the Dollar placeholder format")
+	_, _, err = StatementBuilder.Dialect(MySQL).Select("id").Column(Alias(b.Columns("1").Limit(1), "k")).From("t").ToSql()
+	assert.EqualError(t, err, "select builder: SELECT: select builder: DISTINCT ON: only supported by PostgreSQL; use the Dollar placeholder format")
+
+	sql, _, err = StatementBuilder.Dialect(Postgres).Select("*").FromSelect(b, "e").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM (SELECT DISTINCT ON (user_id) user_id, kind FROM events `+
+		`WHERE kind <> $1 ORDER BY user_id, created_at DESC) AS e`, sql)
+}
+
+func TestSelectBuilderJoinUsing(t *testing.T) {
+	sql, args, err := Select("*").
+		From("orders").
+		JoinUsing("customers", "customer_id", "region").
+		LeftJoinUsing("refunds", "order_id").
+		Where("total > ?", 10).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM orders JOIN customers USING (customer_id, region) "+
+		"LEFT JOIN refunds USING (order_id) WHERE total > ?", sql)
+	assert.Equal(t, []interface{}{10}, args)
+
+	sql, _, err = StatementBuilder.TablePathPrefix("/Root/db").
+		Select("*").From("a").InnerJoinUsing("b", "id").RightJoinUsing("c", "id").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `/Root/db/a` INNER JOIN `/Root/db/b` USING (id) RIGHT JOIN `/Root/db/c` USING (id)", sql)
+}
+
+func TestSelectBuilderGroupingSets(t *testing.T) {
+	sql, _, err := Select("year", "month", "SUM(total)").From("sales").GroupByRollup("year", "month").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT year, month, SUM(total) FROM sales GROUP BY ROLLUP(year, month)", sql)
+
+	sql, _, err = Select("a", "b", "c", "COUNT(*)").From("t").GroupBy("a").GroupByCube("b", "c").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a, b, c, COUNT(*) FROM t GROUP BY a, CUBE(b, c)", sql)
+
+	sql, _, err = Select("year", "month", "region", "SUM(total)").
+		From("sales").
+		GroupByGroupingSets([]string{"year", "month"}, []string{"region"}, nil).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT year, month, region, SUM(total) FROM sales "+
+		"GROUP BY GROUPING SETS ((year, month), (region), ())", sql)
+}
+
+func TestSelectBuilderGroupByClauseAndHavingMaps(t *testing.T) {
+	sqlStr, args, err := Select("COUNT(*)").
+		From("orders").
+		GroupByClause("DATE_TRUNC(?, created_at)", "month").
+		GroupByClause(Expr("region")).
+		Having(Gt{"COUNT(*)": 5}).
+		Having(Eq{"region": []string{"eu", "us"}}).
+		Having(nil).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM orders "+
+		"GROUP BY DATE_TRUNC($1, created_at), region "+
+		"HAVING COUNT(*) > $2 AND region IN ($3,$4)", sqlStr)
+	assert.Equal(t, []interface{}{"month", 5, "eu", "us"}, args)
+}
+
+func TestSelectBuilderHint(t *testing.T) {
+	sqlStr, _, err := Select("*").From("t1").Join("t2 USING (id)").
+		Hint("JOIN_ORDER(t2, t1)", "NO_ICP(t1)").
+		Distinct().
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT /*+ JOIN_ORDER(t2, t1) NO_ICP(t1) */ DISTINCT * FROM t1 JOIN t2 USING (id)", sqlStr)
+
+	sqlStr, _, err = Select("*").From("t").Where(Eq{"id": 1}).
+		Hint("MAXDOP 1", "RECOMPILE").
+		PlaceholderFormat(AtP).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t WHERE id = @p1 OPTION (MAXDOP 1, RECOMPILE)", sqlStr)
+
+	sqlStr, _, err = Select("*").From("t").
+		Pragma("TablePathPrefix", "/Root").
+		Hint(`ydb.CostBasedOptimizer("on")`).
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `PRAGMA TablePathPrefix("/Root"); PRAGMA ydb.CostBasedOptimizer("on"); SELECT * FROM t`, sqlStr)
+
+	sqlStr, _, err = StatementBuilder.Dialect(SQLServer).Select("*").From("t").Hint("MAXDOP 1").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM [t] OPTION (MAXDOP 1)", sqlStr)
+
+	// neither OPTION nor PRAGMA can go in a subquery
+	for _, f := range []PlaceholderFormat{AtP, DollarP} {
+		sub := Select("id").From("t").Hint("x")
+		_, _, err = Select("*").FromSelect(sub, "s").PlaceholderFormat(f).ToSql()
+		assert.EqualError(t, err, "select builder: FROM: select builder: hint: only supported on the outermost query on this database")
+
+		_, _, err = Select("*").From("u").Where(Expr("id IN (?)", sub)).PlaceholderFormat(f).ToSql()
+		assert.Error(t, err)
+	}
+
+	sqlStr, _, err = Select("*").FromSelect(Select("id").From("t").Hint("x"), "s").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (SELECT /*+ x */ id FROM t) AS s", sqlStr)
+}
+
+func TestSelectBuilderHintInjection(t *testing.T) {
+	bad := []string{
+		"/*/ DROP TABLE t; --",
+		"bad */ DROP TABLE t; /*",
+		"MAXDOP 1) DROP TABLE t (",
+		"MAXDOP 1); DROP TABLE t; --",
+		`x("on"); DROP TABLE t; --")`,
+		`x("*/")`,
+		`x("a\")`,
+		`x("unterminated)`,
+		"a -- b",
+		"",
+	}
+	for _, h := range bad {
+		for _, f := range []PlaceholderFormat{Question, AtP, DollarP} {
+			_, _, err := Select("*").From("t").Hint(h).PlaceholderFormat(f).ToSql()
+			assert.EqualError(t, err, fmt.Sprintf("select builder: hint: invalid hint %q", h), h)
+		}
+	}
+
+	// nested queries are checked too
+	_, _, err := Select("*").FromSelect(Select("*").From("t").Hint("a; b"), "s").ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderWhen(t *testing.T) {
+	build := func(team string, limit uint64) string {
+		sqlStr, _, err := Select("*").From("users").
+			When(team != "", func(b SelectBuilder) SelectBuilder {
+				return b.Join("teams t ON t.id = users.team_id").Where(Eq{"t.name": team})
+			}).
+			When(limit > 0, func(b SelectBuilder) SelectBuilder { return b.Limit(limit) }).
+			ToSql()
+		assert.NoError(t, err)
+		return sqlStr
+	}
+	assert.Equal(t, "SELECT * FROM users", build("", 0))
+	assert.Equal(t, "SELECT * FROM users JOIN teams t ON t.id = users.team_id WHERE t.name = ? LIMIT 10", build("core", 10))
+}
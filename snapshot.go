@@ -0,0 +1,223 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Snapshot is a rendered query that can be serialized and executed later,
+// possibly in another process.
+//
+// Builders hold arbitrary Sqlizers and runners, so they cannot be encoded
+// directly. A Snapshot instead keeps the final SQL string and its args, the
+// latter normalized to database/sql/driver.Value types so that they survive a
+// JSON or gob round trip with their types intact.
+//
+// Ex:
+//     snap, err := NewSnapshot(Select("*").From("users").Where(Eq{"id": 1}))
+//     payload, err := json.Marshal(snap)
+//     // ... in a worker:
+//     var snap Snapshot
+//     err = json.Unmarshal(payload, &snap)
+//     rows, err := snap.RunWith(db).Query()
+type Snapshot struct {
+	Sql  string
+	Args []interface{}
+
+	runWith BaseRunner
+}
+
+// NewSnapshot calls ToSql on s and returns the result as a Snapshot.
+//
+// Args are converted with driver.DefaultParameterConverter, so driver.Valuer
+// implementations are resolved at this point. The value of a sql.NamedArg,
+// as passed by NamedPlaceholders, is converted and kept under its name. An
+// error is returned if any arg cannot be converted to a driver.Value.
+func NewSnapshot(s Sqlizer) (Snapshot, error) {
+	sqlStr, args, err := s.ToSql()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		named, isNamed := arg.(sql.NamedArg)
+		if isNamed {
+			arg = named.Value
+		}
+		v, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("cannot snapshot arg %d: %v", i, err)
+		}
+		if isNamed {
+			values[i] = sql.Named(named.Name, v)
+		} else {
+			values[i] = v
+		}
+	}
+
+	return Snapshot{Sql: sqlStr, Args: values}, nil
+}
+
+// ToSql returns the stored SQL string and args.
+func (s Snapshot) ToSql() (string, []interface{}, error) {
+	return s.Sql, s.Args, nil
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+//
+// The runner is not serialized.
+func (s Snapshot) RunWith(runner BaseRunner) Snapshot {
+	s.runWith = wrapRunner(runner)
+	return s
+}
+
+// Exec Execs the stored query with the Runner set by RunWith.
+func (s Snapshot) Exec() (sql.Result, error) {
+	if s.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	return ExecWith(s.runWith, s)
+}
+
+// Query Querys the stored query with the Runner set by RunWith.
+func (s Snapshot) Query() (*sql.Rows, error) {
+	if s.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	return QueryWith(s.runWith, s)
+}
+
+// QueryRow QueryRows the stored query with the Runner set by RunWith.
+func (s Snapshot) QueryRow() RowScanner {
+	if s.runWith == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	queryRower, ok := s.runWith.(QueryRower)
+	if !ok {
+		return &Row{err: RunnerNotQueryRunner}
+	}
+	return QueryRowWith(queryRower, s)
+}
+
+// Scan is a shortcut for QueryRow().Scan.
+func (s Snapshot) Scan(dest ...interface{}) error {
+	return s.QueryRow().Scan(dest...)
+}
+
+// snapshotArg is the wire representation of a single Snapshot arg. The type
+// name is kept alongside the value because JSON alone cannot tell an int64
+// from a float64 or a string from a []byte.
+type snapshotArg struct {
+	Name  string          `json:"name,omitempty"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type snapshotJSON struct {
+	Sql  string        `json:"sql"`
+	Args []snapshotArg `json:"args"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	out := snapshotJSON{Sql: s.Sql, Args: make([]snapshotArg, len(s.Args))}
+	for i, arg := range s.Args {
+		var name string
+		if named, ok := arg.(sql.NamedArg); ok {
+			name, arg = named.Name, named.Value
+		}
+		var typ string
+		switch arg.(type) {
+		case nil:
+			out.Args[i] = snapshotArg{Name: name, Type: "null"}
+			continue
+		case int64:
+			typ = "int64"
+		case float64:
+			typ = "float64"
+		case bool:
+			typ = "bool"
+		case string:
+			typ = "string"
+		case []byte:
+			typ = "bytes"
+		case time.Time:
+			typ = "time"
+		default:
+			return nil, fmt.Errorf("cannot serialize arg %d of type %T", i, arg)
+		}
+		value, err := json.Marshal(arg)
+		if err != nil {
+			return nil, err
+		}
+		out.Args[i] = snapshotArg{Name: name, Type: typ, Value: value}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	var in snapshotJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	args := make([]interface{}, len(in.Args))
+	for i, arg := range in.Args {
+		var err error
+		switch arg.Type {
+		case "null":
+			// args[i] is already nil
+		case "int64":
+			var v int64
+			err = json.Unmarshal(arg.Value, &v)
+			args[i] = v
+		case "float64":
+			var v float64
+			err = json.Unmarshal(arg.Value, &v)
+			args[i] = v
+		case "bool":
+			var v bool
+			err = json.Unmarshal(arg.Value, &v)
+			args[i] = v
+		case "string":
+			var v string
+			err = json.Unmarshal(arg.Value, &v)
+			args[i] = v
+		case "bytes":
+			var v []byte
+			err = json.Unmarshal(arg.Value, &v)
+			args[i] = v
+		case "time":
+			var v time.Time
+			err = json.Unmarshal(arg.Value, &v)
+			args[i] = v
+		default:
+			err = fmt.Errorf("unknown type %q", arg.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot deserialize arg %d: %v", i, err)
+		}
+		if arg.Name != "" {
+			args[i] = sql.Named(arg.Name, args[i])
+		}
+	}
+
+	s.Sql = in.Sql
+	s.Args = args
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (s Snapshot) GobEncode() ([]byte, error) {
+	return s.MarshalJSON()
+}
+
+// GobDecode implements gob.GobDecoder.
+func (s *Snapshot) GobDecode(data []byte) error {
+	return s.UnmarshalJSON(data)
+}
@@ -0,0 +1,52 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+// ExecContext ExecContexts the stored query with the Runner set by RunWith.
+func (s Snapshot) ExecContext(ctx context.Context) (sql.Result, error) {
+	if s.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := s.runWith.(ExecerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return ExecContextWith(ctx, ctxRunner, s)
+}
+
+// QueryContext QueryContexts the stored query with the Runner set by RunWith.
+func (s Snapshot) QueryContext(ctx context.Context) (*sql.Rows, error) {
+	if s.runWith == nil {
+		return nil, RunnerNotSet
+	}
+	ctxRunner, ok := s.runWith.(QueryerContext)
+	if !ok {
+		return nil, NoContextSupport
+	}
+	return QueryContextWith(ctx, ctxRunner, s)
+}
+
+// QueryRowContext QueryRowContexts the stored query with the Runner set by RunWith.
+func (s Snapshot) QueryRowContext(ctx context.Context) RowScanner {
+	if s.runWith == nil {
+		return &Row{err: RunnerNotSet}
+	}
+	queryRower, ok := s.runWith.(QueryRowerContext)
+	if !ok {
+		if _, ok := s.runWith.(QueryerContext); !ok {
+			return &Row{err: RunnerNotQueryRunner}
+		}
+		return &Row{err: NoContextSupport}
+	}
+	return QueryRowContextWith(ctx, queryRower, s)
+}
+
+// ScanContext is a shortcut for QueryRowContext().Scan.
+func (s Snapshot) ScanContext(ctx context.Context, dest ...interface{}) error {
+	return s.QueryRowContext(ctx).Scan(dest...)
+}
@@ -0,0 +1,126 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	b := Select("*").From("users").
+		Where(Eq{"id": 1}).
+		Where("name = ?", sql.NullString{String: "moe", Valid: true}).
+		PlaceholderFormat(Dollar)
+
+	snap, err := NewSnapshot(b)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1 AND name = $2", snap.Sql)
+	assert.Equal(t, []interface{}{int64(1), "moe"}, snap.Args)
+}
+
+func TestNewSnapshotErr(t *testing.T) {
+	_, err := NewSnapshot(Select())
+	assert.Error(t, err)
+
+	_, err = NewSnapshot(Expr("x = ?", struct{}{}))
+	assert.Error(t, err)
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	snap, err := NewSnapshot(Expr("?,?,?,?,?,?,?", nil, 1, 1.5, true, "s", []byte("b"), now))
+	assert.NoError(t, err)
+
+	payload, err := json.Marshal(snap)
+	assert.NoError(t, err)
+
+	var decoded Snapshot
+	err = json.Unmarshal(payload, &decoded)
+	assert.NoError(t, err)
+
+	assert.Equal(t, snap.Sql, decoded.Sql)
+	assert.Equal(t, []interface{}{nil, int64(1), 1.5, true, "s", []byte("b"), now}, decoded.Args)
+}
+
+func TestSnapshotNamedArgs(t *testing.T) {
+	b := Select("*").From("users").
+		Where(Expr("id = :id", NamedArgs{"id": 1})).
+		Where("name = ?", sql.NullString{String: "moe", Valid: true}).
+		PlaceholderFormat(NamedPlaceholders("@"))
+
+	snap, err := NewSnapshot(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = @id AND name = @p2", snap.Sql)
+	assert.Equal(t, []interface{}{sql.Named("id", int64(1)), sql.Named("p2", "moe")}, snap.Args)
+
+	payload, err := json.Marshal(snap)
+	assert.NoError(t, err)
+
+	var decoded Snapshot
+	err = json.Unmarshal(payload, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, snap.Args, decoded.Args)
+}
+
+func TestSnapshotJSONBadType(t *testing.T) {
+	err := json.Unmarshal([]byte(`{"sql":"?","args":[{"type":"foo"}]}`), &Snapshot{})
+	assert.Error(t, err)
+}
+
+func TestSnapshotGob(t *testing.T) {
+	snap, err := NewSnapshot(Insert("test").Values(1, "x"))
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = gob.NewEncoder(buf).Encode(snap)
+	assert.NoError(t, err)
+
+	var decoded Snapshot
+	err = gob.NewDecoder(buf).Decode(&decoded)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "INSERT INTO test VALUES (?,?)", decoded.Sql)
+	assert.Equal(t, []interface{}{int64(1), "x"}, decoded.Args)
+}
+
+func TestSnapshotRunners(t *testing.T) {
+	db := &DBStub{}
+	snap, err := NewSnapshot(Select("test").Where("x = ?", 1))
+	assert.NoError(t, err)
+
+	b := snap.RunWith(db)
+
+	expectedSql := "SELECT test WHERE x = ?"
+
+	b.Exec()
+	assert.Equal(t, expectedSql, db.LastExecSql)
+	assert.Equal(t, []interface{}{int64(1)}, db.LastExecArgs)
+
+	b.Query()
+	assert.Equal(t, expectedSql, db.LastQuerySql)
+
+	b.QueryRow()
+	assert.Equal(t, expectedSql, db.LastQueryRowSql)
+
+	err = b.Scan()
+	assert.NoError(t, err)
+}
+
+func TestSnapshotNoRunner(t *testing.T) {
+	snap := Snapshot{Sql: "SELECT 1"}
+
+	_, err := snap.Exec()
+	assert.Equal(t, RunnerNotSet, err)
+
+	_, err = snap.Query()
+	assert.Equal(t, RunnerNotSet, err)
+
+	err = snap.Scan()
+	assert.Equal(t, RunnerNotSet, err)
+}
@@ -0,0 +1,37 @@
+package squirrel
+
+// sqlx.DB and sqlx.Tx (github.com/jmoiron/sqlx) embed their database/sql
+// counterparts, so they can already be passed to RunWith. The functions
+// below hand the rendered query to their own struct scanning instead, which
+// maps columns with sqlx's "db" tags and name mapper.
+
+// StructQueryer is the interface that wraps the struct scanning methods of
+// sqlx.DB and sqlx.Tx.
+type StructQueryer interface {
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+}
+
+// QueryStructWith scans the first row returned by the SQL of s into dest, a
+// pointer to a struct, with db's Get.
+//
+// Ex:
+//     var u User
+//     err := QueryStructWith(sqlxDB, &u, Select("*").From("users").Where(Eq{"id": id}))
+func QueryStructWith(db StructQueryer, dest interface{}, s Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return db.Get(dest, query, args...)
+}
+
+// QueryStructsWith scans all the rows returned by the SQL of s into dest, a
+// pointer to a slice, with db's Select.
+func QueryStructsWith(db StructQueryer, dest interface{}, s Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return db.Select(dest, query, args...)
+}
@@ -0,0 +1,34 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+)
+
+// StructQueryerContext is the interface that wraps the context struct
+// scanning methods of sqlx.DB and sqlx.Tx.
+type StructQueryerContext interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// QueryStructContextWith scans the first row returned by the SQL of s into
+// dest with db's GetContext.
+func QueryStructContextWith(ctx context.Context, db StructQueryerContext, dest interface{}, s Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return db.GetContext(ctx, dest, query, args...)
+}
+
+// QueryStructsContextWith scans all the rows returned by the SQL of s into
+// dest with db's SelectContext.
+func QueryStructsContextWith(ctx context.Context, db StructQueryerContext, dest interface{}, s Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	return db.SelectContext(ctx, dest, query, args...)
+}
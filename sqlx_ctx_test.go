@@ -0,0 +1,34 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *structQueryerStub) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.record("GetContext", dest, query, args)
+}
+
+func (s *structQueryerStub) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.record("SelectContext", dest, query, args)
+}
+
+func TestQueryStructContextWith(t *testing.T) {
+	db := &structQueryerStub{}
+	var u scanTestUser
+	err := QueryStructContextWith(ctx, db, &u, Select("*").From("users").Where(Eq{"id": 1}))
+	assert.NoError(t, err)
+	assert.Equal(t, "GetContext", db.method)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", db.query)
+	assert.Equal(t, []interface{}{1}, db.args)
+
+	var users []scanTestUser
+	err = QueryStructsContextWith(ctx, db, &users, Select("*").From("users"))
+	assert.NoError(t, err)
+	assert.Equal(t, "SelectContext", db.method)
+	assert.Equal(t, &users, db.dest)
+}
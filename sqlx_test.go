@@ -0,0 +1,52 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// structQueryerStub records the queries passed to the sqlx style struct
+// scanning methods.
+type structQueryerStub struct {
+	method string
+	dest   interface{}
+	query  string
+	args   []interface{}
+}
+
+func (s *structQueryerStub) record(method string, dest interface{}, query string, args []interface{}) error {
+	s.method, s.dest, s.query, s.args = method, dest, query, args
+	return nil
+}
+
+func (s *structQueryerStub) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.record("Get", dest, query, args)
+}
+
+func (s *structQueryerStub) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.record("Select", dest, query, args)
+}
+
+func TestQueryStructWith(t *testing.T) {
+	db := &structQueryerStub{}
+	var u scanTestUser
+	err := QueryStructWith(db, &u, Select("*").From("users").Where(Eq{"id": 1}).PlaceholderFormat(Dollar))
+	assert.NoError(t, err)
+	assert.Equal(t, "Get", db.method)
+	assert.Equal(t, &u, db.dest)
+	assert.Equal(t, "SELECT * FROM users WHERE id = $1", db.query)
+	assert.Equal(t, []interface{}{1}, db.args)
+
+	var users []scanTestUser
+	err = QueryStructsWith(db, &users, Select("*").From("users"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Select", db.method)
+	assert.Equal(t, &users, db.dest)
+	assert.Equal(t, "SELECT * FROM users", db.query)
+
+	db = &structQueryerStub{}
+	err = QueryStructsWith(db, &users, Select().From("users"))
+	assert.Error(t, err)
+	assert.Equal(t, "", db.method)
+}
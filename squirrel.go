@@ -4,12 +4,9 @@
 package squirrel
 
 import (
-	"bytes"
 	"database/sql"
 	"fmt"
 	"strings"
-
-	"github.com/lann/builder"
 )
 
 // Sqlizer is the interface that wraps the ToSql method.
@@ -82,14 +79,16 @@ func (r *stdsqlRunner) QueryRow(query string, args ...interface{}) RowScanner {
 	return r.StdSql.QueryRow(query, args...)
 }
 
-func setRunWith(b interface{}, runner BaseRunner) interface{} {
+// wrapRunner wraps standard library types (e.g. *sql.DB) so that they
+// implement the runner interfaces squirrel expects.
+func wrapRunner(runner BaseRunner) BaseRunner {
 	switch r := runner.(type) {
 	case StdSqlCtx:
-		runner = WrapStdSqlCtx(r)
+		return WrapStdSqlCtx(r)
 	case StdSql:
-		runner = WrapStdSql(r)
+		return WrapStdSql(r)
 	}
-	return builder.Set(b, "RunWith", runner)
+	return runner
 }
 
 // RunnerNotSet is returned by methods that need a Runner if it isn't set.
@@ -124,6 +123,13 @@ func QueryRowWith(db QueryRower, s Sqlizer) RowScanner {
 
 // DebugSqlizer calls ToSql on s and shows the approximate SQL to be executed
 //
+// Args are written as literals in place of their placeholders. For builders,
+// the PlaceholderFormat tells DebugSqlizer how to find the placeholders (e.g.
+// $1 for Dollar) and which database to write literals for: Dollar for
+// Postgres, AtP for SQL Server, Colon for Oracle and DollarP for YQL. Strings
+// are quoted and escaped, nil is written as NULL, and []byte and time.Time
+// values use the literal syntax of that database.
+//
 // If ToSql returns an error, the result of this method will look like:
 // "[ToSql error: %s]" or "[DebugSqlizer error: %s]"
 //
@@ -137,47 +143,34 @@ func DebugSqlizer(s Sqlizer) string {
 		return fmt.Sprintf("[ToSql error: %s]", err)
 	}
 
-	var placeholder string
-	downCast, ok := s.(placeholderDebugger)
-	if !ok {
-		placeholder = "?"
-	} else {
-		placeholder = downCast.debugPlaceholder()
+	format, placeholder := sqlizerPlaceholder(s)
+	dialect := dialectKindOf(format)
+	ip := interpolator{
+		placeholder: placeholder,
+		literal: func(v interface{}) (string, error) {
+			return debugLiteral(dialect, v), nil
+		},
 	}
-	// TODO: dedupe this with placeholder.go
-	buf := &bytes.Buffer{}
-	i := 0
-	for {
-		p := strings.Index(sql, placeholder)
-		if p == -1 {
-			break
-		}
-		if len(sql[p:]) > 1 && sql[p:p+2] == "??" { // escape ?? => ?
-			buf.WriteString(sql[:p])
-			buf.WriteString("?")
-			if len(sql[p:]) == 1 {
-				break
-			}
-			sql = sql[p+2:]
-		} else {
-			if i+1 > len(args) {
-				return fmt.Sprintf(
-					"[DebugSqlizer error: too many placeholders in %#v for %d args]",
-					sql, len(args))
-			}
-			buf.WriteString(sql[:p])
-			fmt.Fprintf(buf, "'%v'", args[i])
-			// advance our sql string "cursor" beyond the arg we placed
-			sql = sql[p+1:]
-			i++
+	sql, err = ip.interpolate(sql, args)
+	if err != nil {
+		return fmt.Sprintf("[DebugSqlizer error: %s]", err)
+	}
+	return sql
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
 		}
 	}
-	if i < len(args) {
-		return fmt.Sprintf(
-			"[DebugSqlizer error: not enough placeholders in %#v for %d args]",
-			sql, len(args))
+	return s != ""
+}
+
+// indexFrom is strings.Index(s[from:], substr) as an index into s.
+func indexFrom(s, substr string, from int) int {
+	if p := strings.Index(s[from:], substr); p != -1 {
+		return from + p
 	}
-	// "append" any remaning sql that won't need interpolating
-	buf.WriteString(sql)
-	return buf.String()
+	return -1
 }
@@ -200,3 +200,16 @@ func TestDebugSqlizerErrors(t *testing.T) {
 	errorMsg = DebugSqlizer(Lt{"x": nil}) // Cannot use nil values with Lt
 	assert.True(t, strings.HasPrefix(errorMsg, "[ToSql error: "))
 }
+
+func BenchmarkDebugSqlizer(b *testing.B) {
+	values := make([]interface{}, 1000)
+	for i := range values {
+		values[i] = i
+	}
+	sqlizer := Insert("t").Columns("id").Values(values...)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = DebugSqlizer(sqlizer)
+	}
+}
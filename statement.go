@@ -1,55 +1,398 @@
 package squirrel
 
-import "github.com/lann/builder"
+import (
+	"path"
+	"strconv"
+	"strings"
+)
 
 // StatementBuilderType is the type of StatementBuilder.
-type StatementBuilderType builder.Builder
+type StatementBuilderType struct {
+	placeholderFormat PlaceholderFormat
+	runWith           BaseRunner
+	pragmas           []pragma
+	comments          []string
+	whereParts        []Sqlizer
+	builderOptions
+}
+
+// builderOptions holds the settings a StatementBuilderType passes down to its
+// child builders that are not part of the rendered statement.
+type builderOptions struct {
+	tablePathPrefix string
+	quoter          Quoter
+	quoterSet       bool
+	bindLimits      bool
+	middlewares     []Middleware
+}
+
+// Builders are immutable: every method works on a copy of its receiver. The
+// copy shares slices with the receiver, so appending in place could write into
+// an array the receiver (or another copy) still uses. These helpers always
+// append into a fresh array.
+
+func appendSqlizers(s []Sqlizer, parts ...Sqlizer) []Sqlizer {
+	return append(s[:len(s):len(s)], parts...)
+}
+
+func appendStrings(s []string, strs ...string) []string {
+	return append(s[:len(s):len(s)], strs...)
+}
+
+func appendPragma(s []pragma, name, value string) []pragma {
+	return append(s[:len(s):len(s)], pragma{name: name, value: value})
+}
 
 // Select returns a SelectBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Select(columns ...string) SelectBuilder {
-	return SelectBuilder(b).Columns(columns...)
+	sb := SelectBuilder{builderOptions: b.builderOptions}
+	sb.data.PlaceholderFormat = b.placeholderFormat
+	sb.data.RunWith = b.runWith
+	sb.data.Pragmas = b.pragmas
+	sb.data.Comments = b.comments
+	sb.data.WhereParts = b.whereParts
+	return sb.Columns(columns...)
 }
 
 // Insert returns a InsertBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Insert(into string) InsertBuilder {
-	return InsertBuilder(b).Into(into)
+	return b.insert().Into(into)
+}
+
+// Replace returns a ReplaceBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Replace(into string) ReplaceBuilder {
+	return b.insert().statementKeyword("REPLACE").Into(into)
 }
 
-// Replace returns a InsertBuilder for this StatementBuilderType with the
-// statement keyword set to "REPLACE".
-func (b StatementBuilderType) Replace(into string) InsertBuilder {
-	return InsertBuilder(b).statementKeyword("REPLACE").Into(into)
+// Upsert returns a UpsertBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Upsert(into string) UpsertBuilder {
+	return b.insert().statementKeyword("UPSERT").Into(into)
+}
+
+func (b StatementBuilderType) insert() InsertBuilder {
+	ib := InsertBuilder{builderOptions: b.builderOptions}
+	ib.data.PlaceholderFormat = b.placeholderFormat
+	ib.data.RunWith = b.runWith
+	ib.data.Pragmas = b.pragmas
+	ib.data.Comments = b.comments
+	return ib
 }
 
 // Update returns a UpdateBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Update(table string) UpdateBuilder {
-	return UpdateBuilder(b).Table(table)
+	ub := UpdateBuilder{builderOptions: b.builderOptions}
+	ub.data.PlaceholderFormat = b.placeholderFormat
+	ub.data.RunWith = b.runWith
+	ub.data.Pragmas = b.pragmas
+	ub.data.Comments = b.comments
+	ub.data.WhereParts = b.whereParts
+	return ub.Table(table)
 }
 
 // Delete returns a DeleteBuilder for this StatementBuilderType.
 func (b StatementBuilderType) Delete(from string) DeleteBuilder {
-	return DeleteBuilder(b).From(from)
+	db := DeleteBuilder{builderOptions: b.builderOptions}
+	db.data.PlaceholderFormat = b.placeholderFormat
+	db.data.RunWith = b.runWith
+	db.data.Pragmas = b.pragmas
+	db.data.Comments = b.comments
+	db.data.WhereParts = b.whereParts
+	return db.From(from)
+}
+
+// Merge returns a MergeBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Merge(into string) MergeBuilder {
+	mb := MergeBuilder{builderOptions: b.builderOptions}
+	mb.data.PlaceholderFormat = b.placeholderFormat
+	mb.data.RunWith = b.runWith
+	mb.data.Pragmas = b.pragmas
+	mb.data.Comments = b.comments
+	return mb.Into(into)
+}
+
+// CreateTable returns a CreateTableBuilder for this StatementBuilderType.
+func (b StatementBuilderType) CreateTable(table string) CreateTableBuilder {
+	cb := CreateTableBuilder{builderOptions: b.builderOptions}
+	cb.data.PlaceholderFormat = b.placeholderFormat
+	cb.data.RunWith = b.runWith
+	cb.data.Pragmas = b.pragmas
+	cb.data.Comments = b.comments
+	return cb.Table(table)
+}
+
+// AlterTable returns a AlterTableBuilder for this StatementBuilderType.
+func (b StatementBuilderType) AlterTable(table string) AlterTableBuilder {
+	ab := AlterTableBuilder{builderOptions: b.builderOptions}
+	ab.data.PlaceholderFormat = b.placeholderFormat
+	ab.data.RunWith = b.runWith
+	ab.data.Pragmas = b.pragmas
+	ab.data.Comments = b.comments
+	return ab.Table(table)
+}
+
+// CreateIndex returns a CreateIndexBuilder for this StatementBuilderType.
+func (b StatementBuilderType) CreateIndex(name string) CreateIndexBuilder {
+	cb := CreateIndexBuilder{builderOptions: b.builderOptions}
+	cb.data.PlaceholderFormat = b.placeholderFormat
+	cb.data.RunWith = b.runWith
+	cb.data.Pragmas = b.pragmas
+	cb.data.Comments = b.comments
+	return cb.Name(name)
+}
+
+// DropTable returns a DropTableBuilder for this StatementBuilderType.
+func (b StatementBuilderType) DropTable(table string) DropTableBuilder {
+	db := DropTableBuilder{builderOptions: b.builderOptions}
+	db.data.PlaceholderFormat = b.placeholderFormat
+	db.data.RunWith = b.runWith
+	db.data.Pragmas = b.pragmas
+	db.data.Comments = b.comments
+	return db.Table(table)
+}
+
+// Truncate returns a TruncateBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Truncate(table string) TruncateBuilder {
+	tb := TruncateBuilder{builderOptions: b.builderOptions}
+	tb.data.PlaceholderFormat = b.placeholderFormat
+	tb.data.RunWith = b.runWith
+	tb.data.Pragmas = b.pragmas
+	tb.data.Comments = b.comments
+	return tb.Table(table)
+}
+
+// Batch returns a BatchBuilder for this StatementBuilderType.
+func (b StatementBuilderType) Batch(statements ...Sqlizer) BatchBuilder {
+	var bb BatchBuilder
+	bb.data.PlaceholderFormat = b.placeholderFormat
+	bb.data.RunWith = b.runWith
+	bb.data.Pragmas = b.pragmas
+	return bb.Add(statements...)
 }
 
 // PlaceholderFormat sets the PlaceholderFormat field for any child builders.
 func (b StatementBuilderType) PlaceholderFormat(f PlaceholderFormat) StatementBuilderType {
-	return builder.Set(b, "PlaceholderFormat", f).(StatementBuilderType)
+	b.placeholderFormat = f
+	return b
+}
+
+// Dialect sets the PlaceholderFormat of any child builders to that of d, and
+// makes them write their SQL for d.
+//
+// It also quotes identifiers with d.Quoter(), unless QuoteIdentifiers was
+// called before; QuoteIdentifiers(nil) turns quoting off.
+func (b StatementBuilderType) Dialect(d Dialect) StatementBuilderType {
+	if !b.quoterSet {
+		b.quoter = d.Quoter()
+	}
+	return b.PlaceholderFormat(d.PlaceholderFormat())
 }
 
 // RunWith sets the RunWith field for any child builders.
 func (b StatementBuilderType) RunWith(runner BaseRunner) StatementBuilderType {
-	return setRunWith(b, runner).(StatementBuilderType)
+	b.runWith = wrapRunner(runner)
+	return b
+}
+
+// Pragma adds a YQL pragma to any child builders.
+//
+// See SelectBuilder.Pragma for more information.
+func (b StatementBuilderType) Pragma(name, value string) StatementBuilderType {
+	b.pragmas = appendPragma(b.pragmas, name, value)
+	return b
+}
+
+// Comment adds a comment before the queries of any child builders.
+//
+// See SelectBuilder.Comment for more information.
+func (b StatementBuilderType) Comment(comment string) StatementBuilderType {
+	b.comments = appendStrings(b.comments, comment)
+	return b
 }
 
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
 func (b StatementBuilderType) Where(pred interface{}, args ...interface{}) StatementBuilderType {
-	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(StatementBuilderType)
+	b.whereParts = appendSqlizers(b.whereParts, newWherePart(pred, args...))
+	return b
+}
+
+// TablePathPrefix sets a YDB table path prefix for any child builders.
+//
+// Table names passed to From, Into, Table and the Join methods (but not
+// JoinClause) are then qualified relative to prefix and quoted with
+// backticks, e.g. with prefix "/Root/db", From("users u") renders as
+// FROM `/Root/db/users` u. Names that are already quoted or parenthesized are
+// left untouched.
+func (b StatementBuilderType) TablePathPrefix(prefix string) StatementBuilderType {
+	b.tablePathPrefix = prefix
+	return b
+}
+
+// QuoteIdentifiers sets a Quoter for the builders created from this
+// StatementBuilderType.
+//
+// Table names passed to From, Into, Table and the Join methods, and column
+// names passed to Columns, Column, Set and SetMap are then quoted, so that
+// names which are reserved words, like "order" or "user", work:
+//     sb := StatementBuilder.QuoteIdentifiers(DoubleQuoteQuoter)
+//     sb.Select("id", "u.name AS user").From("order o")
+//     // SELECT "id", "u"."name" AS "user" FROM "order" o
+//
+// Only plain, optionally dotted or aliased, names are quoted. Anything else,
+// e.g. "COUNT(*) AS n", is taken to be a raw expression and left as it is; use
+// Expr or Column with a Sqlizer to make that explicit. Note that quoted names
+// are case sensitive in some databases, e.g. PostgreSQL.
+func (b StatementBuilderType) QuoteIdentifiers(q Quoter) StatementBuilderType {
+	b.quoter = q
+	b.quoterSet = true
+	return b
+}
+
+// BindLimits makes Limit and Offset of any child builders bind their values
+// as placeholder args, like LimitParam and OffsetParam, instead of writing
+// them into the SQL. Queries that differ only in their page then have the
+// same text, so the database can reuse its plan, and YDB its compiled query.
+func (b StatementBuilderType) BindLimits() StatementBuilderType {
+	b.bindLimits = true
+	return b
+}
+
+// limitPart returns a LIMIT or OFFSET value n, bound as an arg if
+// BindLimits is set.
+func (o builderOptions) limitPart(n uint64) Sqlizer {
+	if o.bindLimits {
+		return newPart("?", n)
+	}
+	return newPart(strconv.FormatUint(n, 10))
+}
+
+// qualifyTable qualifies the table name at the start of clause with the
+// TablePathPrefix set on the StatementBuilderType, if any, or else quotes it
+// with the Quoter set by QuoteIdentifiers.
+func (o builderOptions) qualifyTable(clause string) string {
+	if o.tablePathPrefix == "" {
+		if o.quoter != nil {
+			return quoteTable(o.quoter, clause)
+		}
+		return clause
+	}
+
+	trimmed := strings.TrimLeft(clause, " ")
+	if trimmed == "" || trimmed[0] == '`' || trimmed[0] == '(' {
+		return clause
+	}
+
+	name, rest := trimmed, ""
+	if i := strings.IndexAny(trimmed, " \t\n"); i >= 0 {
+		name, rest = trimmed[:i], trimmed[i:]
+	}
+	return "`" + path.Join(o.tablePathPrefix, name) + "`" + rest
+}
+
+// Middleware transforms a builder right before it is rendered.
+//
+// It receives a SelectBuilder, InsertBuilder, UpdateBuilder or DeleteBuilder
+// and must return a value of the same type.
+type Middleware func(b interface{}) interface{}
+
+// WithMiddleware adds middlewares that are applied, in order, to every builder
+// created from this StatementBuilderType before it is rendered.
+//
+// This is useful for cross-cutting concerns like tenant filters or soft-delete
+// predicates:
+//     sb := StatementBuilder.WithMiddleware(func(b interface{}) interface{} {
+//         if sel, ok := b.(SelectBuilder); ok {
+//             return sel.Where(Eq{"tenant_id": tenantID})
+//         }
+//         return b
+//     })
+func (b StatementBuilderType) WithMiddleware(mws ...Middleware) StatementBuilderType {
+	b.middlewares = append(b.middlewares[:len(b.middlewares):len(b.middlewares)], mws...)
+	return b
+}
+
+// applyMiddlewares runs mws on b, a builder that no longer holds them, so a
+// middleware may render the builder it is given without recursing.
+func applyMiddlewares(b interface{}, mws []Middleware) interface{} {
+	for _, mw := range mws {
+		b = mw(b)
+	}
+	return b
+}
+
+// QueryRewriter rewrites the SELECT, UPDATE and DELETE queries built from a
+// StatementBuilderType right before they are rendered, e.g. to enforce tenant
+// isolation or soft-delete filters in one place.
+type QueryRewriter interface {
+	RewriteSelect(b SelectBuilder) SelectBuilder
+	RewriteUpdate(b UpdateBuilder) UpdateBuilder
+	RewriteDelete(b DeleteBuilder) DeleteBuilder
+}
+
+// QueryRewriterFuncs implements QueryRewriter with a func per kind of query.
+// Queries whose func is nil are left as they are.
+//
+// Ex:
+//     notDeleted := sq.QueryRewriterFuncs{
+//         Select: func(b sq.SelectBuilder) sq.SelectBuilder {
+//             return b.Where("deleted_at IS NULL")
+//         },
+//     }
+//     sb := sq.StatementBuilder.WithRewriter(notDeleted)
+type QueryRewriterFuncs struct {
+	Select func(b SelectBuilder) SelectBuilder
+	Update func(b UpdateBuilder) UpdateBuilder
+	Delete func(b DeleteBuilder) DeleteBuilder
+}
+
+// RewriteSelect calls f.Select if it is set.
+func (f QueryRewriterFuncs) RewriteSelect(b SelectBuilder) SelectBuilder {
+	if f.Select == nil {
+		return b
+	}
+	return f.Select(b)
+}
+
+// RewriteUpdate calls f.Update if it is set.
+func (f QueryRewriterFuncs) RewriteUpdate(b UpdateBuilder) UpdateBuilder {
+	if f.Update == nil {
+		return b
+	}
+	return f.Update(b)
+}
+
+// RewriteDelete calls f.Delete if it is set.
+func (f QueryRewriterFuncs) RewriteDelete(b DeleteBuilder) DeleteBuilder {
+	if f.Delete == nil {
+		return b
+	}
+	return f.Delete(b)
+}
+
+// WithRewriter adds a middleware that passes every Select, Update and Delete
+// builder created from this StatementBuilderType through r before it is
+// rendered. Subqueries are rewritten too if they are built from the same
+// StatementBuilderType. Use the builder accessors, e.g.
+// SelectBuilder.GetFrom, to decide whether a query needs rewriting.
+//
+// See WithMiddleware.
+func (b StatementBuilderType) WithRewriter(r QueryRewriter) StatementBuilderType {
+	return b.WithMiddleware(func(b interface{}) interface{} {
+		switch b := b.(type) {
+		case SelectBuilder:
+			return r.RewriteSelect(b)
+		case UpdateBuilder:
+			return r.RewriteUpdate(b)
+		case DeleteBuilder:
+			return r.RewriteDelete(b)
+		}
+		return b
+	})
 }
 
 // StatementBuilder is a parent builder for other builders, e.g. SelectBuilder.
-var StatementBuilder = StatementBuilderType(builder.EmptyBuilder).PlaceholderFormat(Question)
+var StatementBuilder = StatementBuilderType{}.PlaceholderFormat(Question)
 
 // Select returns a new SelectBuilder, optionally setting some result columns.
 //
@@ -65,14 +408,20 @@ func Insert(into string) InsertBuilder {
 	return StatementBuilder.Insert(into)
 }
 
-// Replace returns a new InsertBuilder with the statement keyword set to
-// "REPLACE" and with the given table name.
+// Replace returns a new ReplaceBuilder with the given table name.
 //
 // See InsertBuilder.Into.
-func Replace(into string) InsertBuilder {
+func Replace(into string) ReplaceBuilder {
 	return StatementBuilder.Replace(into)
 }
 
+// Upsert returns a new UpsertBuilder with the given table name.
+//
+// See InsertBuilder.Into.
+func Upsert(into string) UpsertBuilder {
+	return StatementBuilder.Upsert(into)
+}
+
 // Update returns a new UpdateBuilder with the given table name.
 //
 // See UpdateBuilder.Table.
@@ -87,10 +436,52 @@ func Delete(from string) DeleteBuilder {
 	return StatementBuilder.Delete(from)
 }
 
+// Merge returns a new MergeBuilder with the given target table name.
+//
+// See MergeBuilder.Into.
+func Merge(into string) MergeBuilder {
+	return StatementBuilder.Merge(into)
+}
+
+// CreateTable returns a new CreateTableBuilder with the given table name.
+//
+// See CreateTableBuilder.Table.
+func CreateTable(table string) CreateTableBuilder {
+	return StatementBuilder.CreateTable(table)
+}
+
+// AlterTable returns a new AlterTableBuilder with the given table name.
+//
+// See AlterTableBuilder.Table.
+func AlterTable(table string) AlterTableBuilder {
+	return StatementBuilder.AlterTable(table)
+}
+
+// CreateIndex returns a new CreateIndexBuilder with the given index name.
+//
+// See CreateIndexBuilder.On.
+func CreateIndex(name string) CreateIndexBuilder {
+	return StatementBuilder.CreateIndex(name)
+}
+
+// DropTable returns a new DropTableBuilder with the given table name.
+//
+// See DropTableBuilder.Table.
+func DropTable(table string) DropTableBuilder {
+	return StatementBuilder.DropTable(table)
+}
+
+// Truncate returns a new TruncateBuilder with the given table name.
+//
+// See TruncateBuilder.Table.
+func Truncate(table string) TruncateBuilder {
+	return StatementBuilder.Truncate(table)
+}
+
 // Case returns a new CaseBuilder
 // "what" represents case value
 func Case(what ...interface{}) CaseBuilder {
-	b := CaseBuilder(builder.EmptyBuilder)
+	b := CaseBuilder{}
 
 	switch len(what) {
 	case 0:
@@ -102,3 +493,20 @@ func Case(what ...interface{}) CaseBuilder {
 	}
 	return b
 }
+
+// CaseWhen returns a new CaseBuilder for a searched CASE, i.e. one without a
+// case value, with a WHEN ... THEN ... part for each of whens, in order.
+//
+// Ex:
+//     CaseWhen(
+//         WhenThen{Lt{"age": 13}, Expr("?", "child")},
+//         WhenThen{Lt{"age": 20}, Expr("?", "teen")},
+//     ).Else(Expr("?", "adult"))
+//     // CASE WHEN age < ? THEN ? WHEN age < ? THEN ? ELSE ? END
+func CaseWhen(whens ...WhenThen) CaseBuilder {
+	b := CaseBuilder{}
+	for _, w := range whens {
+		b = b.When(w.When, w.Then)
+	}
+	return b
+}
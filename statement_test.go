@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"testing"
 
-	"github.com/lann/builder"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,10 +26,10 @@ func TestStatementBuilderPlaceholderFormat(t *testing.T) {
 func TestRunWithDB(t *testing.T) {
 	db := &sql.DB{}
 	assert.NotPanics(t, func() {
-		builder.GetStruct(Select().RunWith(db))
-		builder.GetStruct(Insert("t").RunWith(db))
-		builder.GetStruct(Update("t").RunWith(db))
-		builder.GetStruct(Delete("t").RunWith(db))
+		_ = Select().RunWith(db).data
+		_ = Insert("t").RunWith(db).data
+		_ = Update("t").RunWith(db).data
+		_ = Delete("t").RunWith(db).data
 	}, "RunWith(*sql.DB) should not panic")
 
 }
@@ -38,10 +37,10 @@ func TestRunWithDB(t *testing.T) {
 func TestRunWithTx(t *testing.T) {
 	tx := &sql.Tx{}
 	assert.NotPanics(t, func() {
-		builder.GetStruct(Select().RunWith(tx))
-		builder.GetStruct(Insert("t").RunWith(tx))
-		builder.GetStruct(Update("t").RunWith(tx))
-		builder.GetStruct(Delete("t").RunWith(tx))
+		_ = Select().RunWith(tx).data
+		_ = Insert("t").RunWith(tx).data
+		_ = Update("t").RunWith(tx).data
+		_ = Delete("t").RunWith(tx).data
 	}, "RunWith(*sql.Tx) should not panic")
 }
 
@@ -79,3 +78,155 @@ func TestStatementBuilderWhere(t *testing.T) {
 	expectedArgs := []interface{}{1, 2}
 	assert.Equal(t, expectedArgs, args)
 }
+
+func TestStatementBuilderWithMiddleware(t *testing.T) {
+	sb := StatementBuilder.WithMiddleware(func(b interface{}) interface{} {
+		switch b := b.(type) {
+		case SelectBuilder:
+			return b.Where(Eq{"tenant_id": 1})
+		case UpdateBuilder:
+			return b.Where(Eq{"tenant_id": 1})
+		case InsertBuilder:
+			return b.Suffix("RETURNING id")
+		}
+		return b
+	}).PlaceholderFormat(Dollar)
+
+	sql, args, err := sb.Select("a").From("t").Where("b = ?", 2).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT a FROM t WHERE b = $1 AND tenant_id = $2", sql)
+	assert.Equal(t, []interface{}{2, 1}, args)
+
+	sql, _, err = sb.Update("t").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = $1 WHERE tenant_id = $2", sql)
+
+	sql, _, err = sb.Insert("t").Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO t VALUES ($1) RETURNING id", sql)
+
+	sql, _, err = sb.Delete("t").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM t", sql)
+}
+
+func TestStatementBuilderWithMiddlewareOrder(t *testing.T) {
+	var calls []string
+	sb := StatementBuilder.
+		WithMiddleware(func(b interface{}) interface{} {
+			calls = append(calls, "first")
+			// Rendering inside a middleware must not recurse.
+			b.(SelectBuilder).ToSql()
+			return b
+		}).
+		WithMiddleware(func(b interface{}) interface{} {
+			calls = append(calls, "second")
+			return b
+		})
+
+	_, _, err := sb.Select("a").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestStatementBuilderWithMiddlewareRunners(t *testing.T) {
+	db := &DBStub{}
+	sb := StatementBuilder.RunWith(db).WithMiddleware(func(b interface{}) interface{} {
+		return b.(SelectBuilder).Where("deleted_at IS NULL")
+	})
+
+	sb.Select("a").From("t").Exec()
+	assert.Equal(t, "SELECT a FROM t WHERE deleted_at IS NULL", db.LastExecSql)
+}
+
+func TestStatementBuilderWithRewriter(t *testing.T) {
+	tenantTables := map[string]bool{"orders": true}
+	sb := StatementBuilder.WithRewriter(QueryRewriterFuncs{
+		Select: func(b SelectBuilder) SelectBuilder {
+			if from, _, _ := b.GetFrom().ToSql(); tenantTables[from] {
+				return b.Where(Eq{"tenant_id": 7})
+			}
+			return b
+		},
+		Delete: func(b DeleteBuilder) DeleteBuilder {
+			return b.Where("deleted_at IS NULL")
+		},
+	})
+
+	sql, args, err := sb.Select("*").From("orders").Where(Expr("id IN (?)", sb.Select("order_id").From("orders"))).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM orders WHERE id IN (SELECT order_id FROM orders WHERE tenant_id = ?) AND tenant_id = ?", sql)
+	assert.Equal(t, []interface{}{7, 7}, args)
+
+	sql, _, err = sb.Select("*").From("items").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM items", sql)
+
+	sql, _, err = sb.Update("orders").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE orders SET a = ?", sql)
+
+	sql, _, err = sb.Delete("orders").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM orders WHERE deleted_at IS NULL", sql)
+}
+
+func TestStatementBuilderTablePathPrefix(t *testing.T) {
+	sb := StatementBuilder.TablePathPrefix("/Root/db/")
+
+	sql, _, err := sb.Select("*").
+		From("users u").
+		Join("orders o ON o.user_id = u.id").
+		LeftJoin("`/Root/other/t` ON true").
+		JoinClause("CROSS JOIN raw").
+		ToSql()
+	assert.NoError(t, err)
+	expectedSql := "SELECT * FROM `/Root/db/users` u " +
+		"JOIN `/Root/db/orders` o ON o.user_id = u.id " +
+		"LEFT JOIN `/Root/other/t` ON true " +
+		"CROSS JOIN raw"
+	assert.Equal(t, expectedSql, sql)
+
+	sql, _, err = sb.Insert("users").Values(1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `/Root/db/users` VALUES (?)", sql)
+
+	sql, _, err = sb.Update("users").Set("a", 1).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `/Root/db/users` SET a = ?", sql)
+
+	sql, _, err = sb.Delete("dir/users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `/Root/db/dir/users`", sql)
+
+	sql, _, err = StatementBuilder.Select("*").From("users").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users", sql)
+}
+
+func TestStatementBuilderBindLimits(t *testing.T) {
+	sb := StatementBuilder.Dialect(YDB).BindLimits()
+
+	sql, args, err := sb.Select("*").From("t").Where("a = ?", 1).Limit(10).Offset(20).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM `t` WHERE a = $p1 LIMIT $p2 OFFSET $p3", sql)
+	assert.Equal(t, []interface{}{1, uint64(10), uint64(20)}, args)
+
+	limit, ok := sb.Select("*").From("t").Limit(10).GetLimit()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(10), limit)
+
+	sql, args, err = sb.Update("t").Set("a", 1).Limit(5).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE `t` SET `a` = $p1 LIMIT $p2", sql)
+	assert.Equal(t, []interface{}{1, uint64(5)}, args)
+
+	sql, args, err = sb.Delete("t").Limit(5).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `t` LIMIT $p1", sql)
+	assert.Equal(t, []interface{}{uint64(5)}, args)
+
+	sql, _, err = Select("*").From("t").Limit(10).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM t LIMIT 10", sql)
+}
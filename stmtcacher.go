@@ -1,6 +1,7 @@
 package squirrel
 
 import (
+	"container/list"
 	"database/sql"
 	"fmt"
 	"sync"
@@ -28,54 +29,157 @@ type DBProxy interface {
 // It also automatically prepares all statements sent to the underlying Preparer calls
 // for Exec, Query and QueryRow and caches the returns *sql.Stmt using the provided
 // query as the key. So that it can be automatically re-used.
+//
+// A StmtCache created with NewStmtCacheLRU holds at most a fixed number of
+// statements, closing the least recently used one when it is full. A
+// statement evicted while an Exec, Query or QueryRow is running it is closed
+// once they are done; one returned by Prepare or PrepareContext is never
+// closed by eviction, as the caller may still use it.
 type StmtCache struct {
 	prep  Preparer
-	cache map[string]*sql.Stmt
+	cache map[string]*cachedStmt
 	mu    sync.Mutex
+
+	// maxSize is the LRU capacity; 0 means unbounded
+	maxSize int
+	lru     *list.List
+	elems   map[string]*list.Element
 }
 
-// Prepare delegates down to the underlying Preparer and caches the result
-// using the provided query as a key
-func (sc *StmtCache) Prepare(query string) (*sql.Stmt, error) {
+// cachedStmt is a statement of a StmtCache with the count of the Execs,
+// Querys and QueryRows running it.
+type cachedStmt struct {
+	stmt *sql.Stmt
+	refs int
+	// handedOut is set once Prepare returned stmt to a caller
+	handedOut bool
+	// evicted is set once stmt is no longer in the cache
+	evicted bool
+}
+
+// get returns the cached statement for query, marking it as recently used.
+// sc.mu must be held.
+func (sc *StmtCache) get(query string) (*cachedStmt, bool) {
+	cs, ok := sc.cache[query]
+	if ok && sc.maxSize > 0 {
+		sc.lru.MoveToFront(sc.elems[query])
+	}
+	return cs, ok
+}
+
+// put caches stmt for query, evicting the least recently used statement if
+// the cache is full. sc.mu must be held.
+func (sc *StmtCache) put(query string, stmt *sql.Stmt) *cachedStmt {
+	cs := &cachedStmt{stmt: stmt}
+	sc.cache[query] = cs
+	if sc.maxSize <= 0 {
+		return cs
+	}
+
+	sc.elems[query] = sc.lru.PushFront(query)
+	for sc.lru.Len() > sc.maxSize {
+		oldest := sc.lru.Back()
+		key := sc.lru.Remove(oldest).(string)
+		evicted := sc.cache[key]
+		delete(sc.cache, key)
+		delete(sc.elems, key)
+		evicted.evicted = true
+		if evicted.refs == 0 && !evicted.handedOut {
+			evicted.close()
+		}
+	}
+	return cs
+}
+
+func (cs *cachedStmt) close() error {
+	if cs.stmt == nil {
+		return nil
+	}
+	return cs.stmt.Close()
+}
+
+// acquire returns the statement for query, preparing it with prepare if it
+// isn't cached, and holds it open until release is called.
+func (sc *StmtCache) acquire(query string, prepare func() (*sql.Stmt, error)) (*cachedStmt, error) {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
-	stmt, ok := sc.cache[query]
-	if ok {
-		return stmt, nil
+	cs, ok := sc.get(query)
+	if !ok {
+		stmt, err := prepare()
+		if err != nil {
+			return nil, err
+		}
+		cs = sc.put(query, stmt)
 	}
-	stmt, err := sc.prep.Prepare(query)
-	if err == nil {
-		sc.cache[query] = stmt
+	cs.refs++
+	return cs, nil
+}
+
+// release lets cs be closed, if it has been evicted and nothing else holds
+// it.
+func (sc *StmtCache) release(cs *cachedStmt) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	cs.refs--
+	if cs.refs == 0 && cs.evicted && !cs.handedOut {
+		// the statement is gone from the cache either way, so a failed
+		// Close only leaks what the driver failed to release
+		_ = cs.close()
+	}
+}
+
+// handOut returns the statement of cs to a caller of Prepare, which makes
+// eviction leave it open.
+func (sc *StmtCache) handOut(cs *cachedStmt) *sql.Stmt {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	cs.handedOut = true
+	cs.refs--
+	return cs.stmt
+}
+
+// Prepare delegates down to the underlying Preparer and caches the result
+// using the provided query as a key
+func (sc *StmtCache) Prepare(query string) (*sql.Stmt, error) {
+	cs, err := sc.acquire(query, func() (*sql.Stmt, error) { return sc.prep.Prepare(query) })
+	if err != nil {
+		return nil, err
 	}
-	return stmt, err
+	return sc.handOut(cs), nil
 }
 
 // Exec delegates down to the underlying Preparer using a prepared statement
 func (sc *StmtCache) Exec(query string, args ...interface{}) (res sql.Result, err error) {
-	stmt, err := sc.Prepare(query)
+	cs, err := sc.acquire(query, func() (*sql.Stmt, error) { return sc.prep.Prepare(query) })
 	if err != nil {
 		return
 	}
-	return stmt.Exec(args...)
+	defer sc.release(cs)
+	return cs.stmt.Exec(args...)
 }
 
 // Query delegates down to the underlying Preparer using a prepared statement
 func (sc *StmtCache) Query(query string, args ...interface{}) (rows *sql.Rows, err error) {
-	stmt, err := sc.Prepare(query)
+	cs, err := sc.acquire(query, func() (*sql.Stmt, error) { return sc.prep.Prepare(query) })
 	if err != nil {
 		return
 	}
-	return stmt.Query(args...)
+	// database/sql defers closing the statement until the rows are closed
+	defer sc.release(cs)
+	return cs.stmt.Query(args...)
 }
 
 // QueryRow delegates down to the underlying Preparer using a prepared statement
 func (sc *StmtCache) QueryRow(query string, args ...interface{}) RowScanner {
-	stmt, err := sc.Prepare(query)
+	cs, err := sc.acquire(query, func() (*sql.Stmt, error) { return sc.prep.Prepare(query) })
 	if err != nil {
 		return &Row{err: err}
 	}
-	return stmt.QueryRow(args...)
+	defer sc.release(cs)
+	return cs.stmt.QueryRow(args...)
 }
 
 // Clear removes and closes all the currently cached prepared statements
@@ -83,14 +187,21 @@ func (sc *StmtCache) Clear() (err error) {
 	sc.mu.Lock()
 	defer sc.mu.Unlock()
 
-	for key, stmt := range sc.cache {
+	if sc.maxSize > 0 {
+		sc.lru.Init()
+		sc.elems = make(map[string]*list.Element)
+	}
+
+	for key, cs := range sc.cache {
 		delete(sc.cache, key)
 
-		if stmt == nil {
+		// statements in use are closed when they are released
+		cs.evicted = true
+		cs.handedOut = false
+		if cs.refs > 0 {
 			continue
 		}
-
-		if cerr := stmt.Close(); cerr != nil {
+		if cerr := cs.close(); cerr != nil {
 			err = cerr
 		}
 	}
@@ -3,6 +3,7 @@
 package squirrel
 
 import (
+	"container/list"
 	"context"
 	"database/sql"
 )
@@ -28,7 +29,23 @@ type DBProxyContext interface {
 //
 // Stmts are cached based on the string value of their queries.
 func NewStmtCache(prep PreparerContext) *StmtCache {
-	return &StmtCache{prep: prep, cache: make(map[string]*sql.Stmt)}
+	return &StmtCache{prep: prep, cache: make(map[string]*cachedStmt)}
+}
+
+// NewStmtCacheLRU returns a *StmtCache like NewStmtCache that holds at most
+// size Prepared Stmts. When it is full, the least recently used Stmt is
+// closed and evicted to make room for a new one.
+//
+// Use it in long-running services that build many distinct queries, which
+// would otherwise accumulate prepared statements without limit.
+func NewStmtCacheLRU(prep PreparerContext, size int) *StmtCache {
+	return &StmtCache{
+		prep:    prep,
+		cache:   make(map[string]*cachedStmt),
+		maxSize: size,
+		lru:     list.New(),
+		elems:   make(map[string]*list.Element),
+	}
 }
 
 // NewStmtCacher is deprecated
@@ -41,46 +58,47 @@ func NewStmtCacher(prep PreparerContext) DBProxyContext {
 // PrepareContext delegates down to the underlying PreparerContext and caches the result
 // using the provided query as a key
 func (sc *StmtCache) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	cs, err := sc.acquireContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return sc.handOut(cs), nil
+}
+
+func (sc *StmtCache) acquireContext(ctx context.Context, query string) (*cachedStmt, error) {
 	ctxPrep, ok := sc.prep.(PreparerContext)
 	if !ok {
 		return nil, NoContextSupport
 	}
-	sc.mu.Lock()
-	defer sc.mu.Unlock()
-	stmt, ok := sc.cache[query]
-	if ok {
-		return stmt, nil
-	}
-	stmt, err := ctxPrep.PrepareContext(ctx, query)
-	if err == nil {
-		sc.cache[query] = stmt
-	}
-	return stmt, err
+	return sc.acquire(query, func() (*sql.Stmt, error) { return ctxPrep.PrepareContext(ctx, query) })
 }
 
 // ExecContext delegates down to the underlying PreparerContext using a prepared statement
 func (sc *StmtCache) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
-	stmt, err := sc.PrepareContext(ctx, query)
+	cs, err := sc.acquireContext(ctx, query)
 	if err != nil {
 		return
 	}
-	return stmt.ExecContext(ctx, args...)
+	defer sc.release(cs)
+	return cs.stmt.ExecContext(ctx, args...)
 }
 
 // QueryContext delegates down to the underlying PreparerContext using a prepared statement
 func (sc *StmtCache) QueryContext(ctx context.Context, query string, args ...interface{}) (rows *sql.Rows, err error) {
-	stmt, err := sc.PrepareContext(ctx, query)
+	cs, err := sc.acquireContext(ctx, query)
 	if err != nil {
 		return
 	}
-	return stmt.QueryContext(ctx, args...)
+	defer sc.release(cs)
+	return cs.stmt.QueryContext(ctx, args...)
 }
 
 // QueryRowContext delegates down to the underlying PreparerContext using a prepared statement
 func (sc *StmtCache) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
-	stmt, err := sc.PrepareContext(ctx, query)
+	cs, err := sc.acquireContext(ctx, query)
 	if err != nil {
 		return &Row{err: err}
 	}
-	return stmt.QueryRowContext(ctx, args...)
+	defer sc.release(cs)
+	return cs.stmt.QueryRowContext(ctx, args...)
 }
@@ -19,3 +19,14 @@ func TestStmtCacherPrepareContext(t *testing.T) {
 	sc.PrepareContext(ctx, query)
 	assert.Equal(t, 1, db.PrepareCount, "expected 1 Prepare, got %d", db.PrepareCount)
 }
+
+func TestStmtCacheLRUPrepareContext(t *testing.T) {
+	db := &DBStub{}
+	sc := NewStmtCacheLRU(db, 1)
+
+	sc.PrepareContext(ctx, "SELECT 1")
+	sc.PrepareContext(ctx, "SELECT 2")
+	sc.PrepareContext(ctx, "SELECT 1")
+	assert.Equal(t, 3, db.PrepareCount)
+	assert.Len(t, sc.cache, 1)
+}
@@ -3,14 +3,26 @@
 package squirrel
 
 import (
-	"database/sql"
+	"container/list"
 )
 
 // NewStmtCacher returns a DBProxy wrapping prep that caches Prepared Stmts.
 //
 // Stmts are cached based on the string value of their queries.
 func NewStmtCache(prep Preparer) *StmtCache {
-	return &StmtCacher{prep: prep, cache: make(map[string]*sql.Stmt)}
+	return &StmtCacher{prep: prep, cache: make(map[string]*cachedStmt)}
+}
+
+// NewStmtCacheLRU returns a *StmtCache like NewStmtCache that holds at most
+// size Prepared Stmts, closing the least recently used one when it is full.
+func NewStmtCacheLRU(prep Preparer, size int) *StmtCache {
+	return &StmtCache{
+		prep:    prep,
+		cache:   make(map[string]*cachedStmt),
+		maxSize: size,
+		lru:     list.New(),
+		elems:   make(map[string]*list.Element),
+	}
 }
 
 // NewStmtCacher is deprecated
@@ -1,6 +1,11 @@
 package squirrel
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,3 +29,114 @@ func TestStmtCachePrepare(t *testing.T) {
 	sc.Prepare(query)
 	assert.Equal(t, 2, db.PrepareCount, "expected 2 Prepare, got %d", db.PrepareCount)
 }
+
+func TestStmtCacheLRU(t *testing.T) {
+	db := &DBStub{}
+	sc := NewStmtCacheLRU(db, 2)
+
+	sc.Prepare("SELECT 1")
+	sc.Prepare("SELECT 2")
+	sc.Prepare("SELECT 1") // hit; SELECT 2 is now least recently used
+	assert.Equal(t, 2, db.PrepareCount)
+
+	sc.Prepare("SELECT 3") // evicts SELECT 2
+	assert.Equal(t, 3, db.PrepareCount)
+
+	sc.Prepare("SELECT 1")
+	assert.Equal(t, 3, db.PrepareCount)
+
+	sc.Prepare("SELECT 2")
+	assert.Equal(t, 4, db.PrepareCount)
+	assert.Len(t, sc.cache, 2)
+
+	assert.Nil(t, sc.Clear())
+	assert.Equal(t, 0, sc.lru.Len())
+
+	sc.Prepare("SELECT 2")
+	assert.Equal(t, 5, db.PrepareCount)
+}
+
+// stmtCacheDriverStub is a database/sql driver whose statements Exec and
+// Query without doing anything, safe for concurrent use.
+type stmtCacheDriverStub struct{}
+
+func (stmtCacheDriverStub) Open(name string) (driver.Conn, error) { return stmtCacheConnStub{}, nil }
+
+type stmtCacheConnStub struct{}
+
+func (stmtCacheConnStub) Prepare(query string) (driver.Stmt, error) { return stmtCacheStmtStub{}, nil }
+func (stmtCacheConnStub) Close() error                              { return nil }
+func (stmtCacheConnStub) Begin() (driver.Tx, error)                 { return nil, errors.New("no transactions") }
+
+type stmtCacheStmtStub struct{}
+
+func (stmtCacheStmtStub) Close() error  { return nil }
+func (stmtCacheStmtStub) NumInput() int { return -1 }
+
+func (stmtCacheStmtStub) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (stmtCacheStmtStub) Query(args []driver.Value) (driver.Rows, error) {
+	return &txRowsStub{n: 1}, nil
+}
+
+func init() {
+	sql.Register("squirrel-stmtcache-stub", stmtCacheDriverStub{})
+}
+
+func TestStmtCacheLRUConcurrent(t *testing.T) {
+	db, err := sql.Open("squirrel-stmtcache-stub", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// a cache of 2 statements shared by 8 goroutines running 4 queries
+	// evicts statements that other goroutines are running
+	sc := NewStmtCacheLRU(db, 2)
+	var wg sync.WaitGroup
+	errs := make(chan error, 8*200)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				query := fmt.Sprintf("SELECT %d", (g+i)%4)
+				if _, err := sc.Exec(query); err != nil {
+					errs <- err
+				}
+				rows, err := sc.Query(query)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				for rows.Next() {
+				}
+				rows.Close()
+				var n int64
+				if err := sc.QueryRow(query).Scan(&n); err != nil {
+					errs <- err
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestStmtCacheLRUKeepsPreparedStmtsOpen(t *testing.T) {
+	db, err := sql.Open("squirrel-stmtcache-stub", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sc := NewStmtCacheLRU(db, 1)
+	stmt, err := sc.Prepare("SELECT 1")
+	assert.NoError(t, err)
+	_, err = sc.Exec("SELECT 2") // evicts SELECT 1
+	assert.NoError(t, err)
+
+	_, err = stmt.Exec()
+	assert.NoError(t, err)
+}
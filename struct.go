@@ -0,0 +1,173 @@
+package squirrel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// structField is a struct field mapped to a column
+type structField struct {
+	name      string
+	column    string
+	index     []int
+	omitEmpty bool
+}
+
+var structFieldsCache sync.Map // map[reflect.Type][]structField
+
+// structFields returns the column mapping of the struct type t.
+//
+// Columns are named by the "db" tag of each exported field, or by the
+// lowercased field name if it has none. Fields tagged `db:"-"` are skipped,
+// and the ",omitempty" tag option skips a field when it holds its zero value.
+// Untagged embedded structs are flattened into the outer struct.
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+
+		if f.Anonymous && !hasTag {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, inner := range structFields(ft) {
+					inner.index = append([]int{i}, inner.index...)
+					fields = append(fields, inner)
+				}
+				continue
+			}
+		}
+
+		if f.PkgPath != "" { // unexported
+			continue
+		}
+
+		column, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			column, opts = tag[:i], tag[i+1:]
+		}
+		if column == "" {
+			column = strings.ToLower(f.Name)
+		}
+
+		fields = append(fields, structField{
+			name:      f.Name,
+			column:    column,
+			index:     []int{i},
+			omitEmpty: opts == "omitempty",
+		})
+	}
+
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// StructOption changes which struct fields SetStruct maps to columns.
+type StructOption func(*structOptions)
+
+type structOptions struct {
+	skipZero bool
+	only     map[string]bool
+	skip     map[string]bool
+}
+
+// SkipZero makes SetStruct skip fields that hold their zero value, e.g. for
+// partial updates from a request with optional fields.
+func SkipZero() StructOption {
+	return func(o *structOptions) {
+		o.skipZero = true
+	}
+}
+
+// OnlyFields makes SetStruct map only the given fields, named either by
+// field name or by column.
+func OnlyFields(fields ...string) StructOption {
+	return func(o *structOptions) {
+		if o.only == nil {
+			o.only = make(map[string]bool, len(fields))
+		}
+		for _, f := range fields {
+			o.only[f] = true
+		}
+	}
+}
+
+// SkipFields makes SetStruct skip the given fields, named either by field name
+// or by column.
+func SkipFields(fields ...string) StructOption {
+	return func(o *structOptions) {
+		if o.skip == nil {
+			o.skip = make(map[string]bool, len(fields))
+		}
+		for _, f := range fields {
+			o.skip[f] = true
+		}
+	}
+}
+
+// structValues returns the columns and values of the struct (or pointer to
+// struct) v, in field order, as selected by opts. It panics if v is not a
+// struct.
+func structValues(v interface{}, opts []StructOption) (columns []string, values []interface{}) {
+	var o structOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("expected a struct or a pointer to a struct, not %T", v))
+	}
+
+	for _, f := range structFields(rv.Type()) {
+		if o.only != nil && !o.only[f.name] && !o.only[f.column] {
+			continue
+		}
+		if o.skip[f.name] || o.skip[f.column] {
+			continue
+		}
+
+		fv, ok := fieldByIndex(rv, f.index)
+		if !ok {
+			// field of a nil embedded pointer
+			continue
+		}
+		if (o.skipZero || f.omitEmpty) && fv.IsZero() {
+			continue
+		}
+
+		columns = append(columns, f.column)
+		values = append(values, fv.Interface())
+	}
+	return
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex that reports nil embedded
+// pointers instead of panicking.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
@@ -0,0 +1,50 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structTestBase struct {
+	ID int64 `db:"id,omitempty"`
+}
+
+type structTestUser struct {
+	structTestBase
+	Name     string `db:"name"`
+	Email    string
+	Password string `db:"-"`
+	Age      int    `db:"age"`
+	internal string
+}
+
+func TestStructValues(t *testing.T) {
+	u := &structTestUser{Name: "moe", Email: "moe@example.com", Password: "x", internal: "y"}
+
+	cols, vals := structValues(u, nil)
+	assert.Equal(t, []string{"name", "email", "age"}, cols)
+	assert.Equal(t, []interface{}{"moe", "moe@example.com", 0}, vals)
+
+	u.ID = 7
+	cols, vals = structValues(u, []StructOption{SkipZero(), SkipFields("Email")})
+	assert.Equal(t, []string{"id", "name"}, cols)
+	assert.Equal(t, []interface{}{int64(7), "moe"}, vals)
+
+	cols, _ = structValues(u, []StructOption{OnlyFields("age", "Name")})
+	assert.Equal(t, []string{"name", "age"}, cols)
+}
+
+func TestStructValuesNotStruct(t *testing.T) {
+	assert.Panics(t, func() { structValues(1, nil) })
+}
+
+func TestStructValuesNilEmbeddedPointer(t *testing.T) {
+	type withPtr struct {
+		*structTestBase
+		Name string `db:"name"`
+	}
+	cols, vals := structValues(withPtr{Name: "a"}, nil)
+	assert.Equal(t, []string{"name"}, cols)
+	assert.Equal(t, []interface{}{"a"}, vals)
+}
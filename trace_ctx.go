@@ -0,0 +1,70 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+)
+
+// QuerySpanInfo describes a query traced by TraceRunner.
+type QuerySpanInfo struct {
+	// Operation is the runner method: "Exec", "Query" or "QueryRow".
+	Operation string
+	// Statement is the generated SQL.
+	Statement string
+	// StatementHash is a short hash of Statement, for tracing backends where
+	// the full SQL is too large or too sensitive to record.
+	StatementHash string
+	// ArgCount is the number of bound args. The args themselves are never
+	// passed to the Tracer.
+	ArgCount int
+}
+
+// QuerySpan is a span started by a Tracer.
+type QuerySpan interface {
+	// End ends the span. err is the error of the query, or nil.
+	End(err error)
+}
+
+// Tracer starts a span per query run by a TraceRunner.
+//
+// Adapting an OpenTelemetry tracer takes a few lines:
+//     func (t otelTracer) StartQuerySpan(ctx context.Context, info sq.QuerySpanInfo) (context.Context, sq.QuerySpan) {
+//         ctx, span := t.tracer.Start(ctx, "sql."+info.Operation, trace.WithAttributes(
+//             attribute.String("db.statement", info.Statement),
+//             attribute.Int("db.args", info.ArgCount)))
+//         return ctx, otelSpan{span}
+//     }
+type Tracer interface {
+	// StartQuerySpan starts a span for the query described by info. The
+	// returned context is passed to the underlying runner.
+	StartQuerySpan(ctx context.Context, info QuerySpanInfo) (context.Context, QuerySpan)
+}
+
+// TraceRunner returns a runner that starts a span with tracer around each
+// Exec, Query and QueryRow of base, with or without a context. The span of a
+// QueryRow ends when the returned row is scanned.
+//
+// Use it with RunWith:
+//     db := sq.TraceRunner(sqlDB, tracer)
+//     sq.Select("*").From("users").RunWith(db).QueryRowContext(ctx)
+func TraceRunner(base BaseRunner, tracer Tracer) RunnerContext {
+	return newInstrumentedRunner(base, func(ctx context.Context, op, query string, args []interface{}) (context.Context, func(err error)) {
+		ctx, span := tracer.StartQuerySpan(ctx, QuerySpanInfo{
+			Operation:     op,
+			Statement:     query,
+			StatementHash: statementHash(query),
+			ArgCount:      len(args),
+		})
+		return ctx, span.End
+	})
+}
+
+// statementHash returns the FNV-1a hash of query as 16 hex digits.
+func statementHash(query string) string {
+	h := fnv.New64a()
+	h.Write([]byte(query))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -0,0 +1,106 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tracerStub struct {
+	spans []*querySpanStub
+}
+
+type querySpanStub struct {
+	info  QuerySpanInfo
+	ended bool
+	err   error
+}
+
+func (s *querySpanStub) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type tracerCtxKey struct{}
+
+func (t *tracerStub) StartQuerySpan(ctx context.Context, info QuerySpanInfo) (context.Context, QuerySpan) {
+	span := &querySpanStub{info: info}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, tracerCtxKey{}, span), span
+}
+
+// baseRunnerStub has no context or QueryRow support.
+type baseRunnerStub struct {
+	err error
+}
+
+func (r baseRunnerStub) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return nil, r.err
+}
+
+func (r baseRunnerStub) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, r.err
+}
+
+func TestTraceRunner(t *testing.T) {
+	db := &DBStub{}
+	tracer := &tracerStub{}
+	runner := TraceRunner(db, tracer)
+
+	_, err := Update("users").Set("a", 1).Where(Eq{"id": 2}).RunWith(runner).ExecContext(ctx)
+	assert.NoError(t, err)
+	_, err = Select("*").From("users").RunWith(runner).Query()
+	assert.NoError(t, err)
+
+	assert.Len(t, tracer.spans, 2)
+	assert.Equal(t, "UPDATE users SET a = ? WHERE id = ?", db.LastExecSql)
+	assert.Equal(t, QuerySpanInfo{
+		Operation:     "Exec",
+		Statement:     "UPDATE users SET a = ? WHERE id = ?",
+		StatementHash: statementHash("UPDATE users SET a = ? WHERE id = ?"),
+		ArgCount:      2,
+	}, tracer.spans[0].info)
+	assert.True(t, tracer.spans[0].ended)
+	assert.Equal(t, "Query", tracer.spans[1].info.Operation)
+	assert.True(t, tracer.spans[1].ended)
+}
+
+func TestTraceRunnerQueryRow(t *testing.T) {
+	tracer := &tracerStub{}
+	runner := TraceRunner(&DBStub{}, tracer)
+
+	row := Select("a").From("b").RunWith(runner).QueryRowContext(ctx)
+	assert.Len(t, tracer.spans, 1)
+	assert.False(t, tracer.spans[0].ended)
+
+	assert.NoError(t, row.Scan())
+	assert.True(t, tracer.spans[0].ended)
+}
+
+func TestTraceRunnerError(t *testing.T) {
+	tracer := &tracerStub{}
+	runner := TraceRunner(baseRunnerStub{err: errors.New("boom")}, tracer)
+
+	_, err := runner.Exec("DELETE FROM a")
+	assert.EqualError(t, err, "boom")
+	assert.EqualError(t, tracer.spans[0].err, "boom")
+
+	_, err = runner.ExecContext(ctx, "DELETE FROM a")
+	assert.Equal(t, NoContextSupport, err)
+	assert.Equal(t, NoContextSupport, tracer.spans[1].err)
+
+	err = runner.QueryRow("SELECT 1").Scan()
+	assert.Equal(t, RunnerNotQueryRunner, err)
+	assert.Equal(t, RunnerNotQueryRunner, tracer.spans[2].err)
+}
+
+func TestStatementHash(t *testing.T) {
+	assert.Len(t, statementHash("SELECT 1"), 16)
+	assert.Equal(t, statementHash("SELECT 1"), statementHash("SELECT 1"))
+	assert.NotEqual(t, statementHash("SELECT 1"), statementHash("SELECT 2"))
+}
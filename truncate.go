@@ -0,0 +1,139 @@
+package squirrel
+
+import (
+	"bytes"
+	"database/sql"
+)
+
+type truncateData struct {
+	PlaceholderFormat PlaceholderFormat
+	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
+	Table             string
+	RestartIdentity   bool
+	Cascade           bool
+}
+
+func (d *truncateData) ToSql() (sqlStr string, args []interface{}, err error) {
+	if len(d.Table) == 0 {
+		err = builderError("truncate", "no table")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+
+	switch dialect := dialectKindOf(d.PlaceholderFormat); dialect {
+	case dialectSQLite, dialectYQL:
+		// no TRUNCATE; an unconditional DELETE empties the table too
+		if d.RestartIdentity || d.Cascade {
+			err = builderError("truncate", "RestartIdentity and Cascade are not supported by this database")
+			return
+		}
+		sql.WriteString("DELETE FROM ")
+		sql.WriteString(d.Table)
+	default:
+		if dialect != dialectPostgres && (d.RestartIdentity || d.Cascade) {
+			err = builderError("truncate", "RestartIdentity and Cascade are only supported by PostgreSQL")
+			return
+		}
+		sql.WriteString("TRUNCATE TABLE ")
+		sql.WriteString(d.Table)
+		if d.RestartIdentity {
+			sql.WriteString(" RESTART IDENTITY")
+		}
+		if d.Cascade {
+			sql.WriteString(" CASCADE")
+		}
+	}
+
+	sqlStr, args, err = finalizeDDL(d.PlaceholderFormat, d.Comments, d.Pragmas, sql.String(), args)
+	return
+}
+
+// TruncateBuilder builds statements that delete all the rows of a table:
+//     Truncate("events")
+//     // TRUNCATE TABLE events
+//     Truncate("events").RestartIdentity().Cascade().PlaceholderFormat(Dollar)
+//     // TRUNCATE TABLE events RESTART IDENTITY CASCADE
+//
+// On databases without TRUNCATE, SQLite and YDB, it falls back to an
+// unconditional DELETE; set the Dialect of the StatementBuilderType to
+// target them:
+//     StatementBuilder.Dialect(SQLite).Truncate("events")
+//     // DELETE FROM "events"
+type TruncateBuilder struct {
+	data truncateData
+	builderOptions
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// query.
+func (b TruncateBuilder) PlaceholderFormat(f PlaceholderFormat) TruncateBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
+func (b TruncateBuilder) RunWith(runner BaseRunner) TruncateBuilder {
+	b.data.RunWith = wrapRunner(runner)
+	return b
+}
+
+// Exec builds and Execs the query with the Runner set by RunWith.
+func (b TruncateBuilder) Exec() (sql.Result, error) {
+	data := b.data
+	return ddlExec(data.RunWith, &data)
+}
+
+// ToSql builds the query into a SQL string and bound args.
+func (b TruncateBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// MustSql builds the query into a SQL string and bound args.
+// It panics if there are any errors.
+func (b TruncateBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query.
+//
+// See SelectBuilder.Pragma.
+func (b TruncateBuilder) Pragma(name, value string) TruncateBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query.
+//
+// See SelectBuilder.Comment.
+func (b TruncateBuilder) Comment(comment string) TruncateBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
+// Table sets the table to be truncated.
+func (b TruncateBuilder) Table(table string) TruncateBuilder {
+	b.data.Table = b.qualifyTable(table)
+	return b
+}
+
+// RestartIdentity adds the PostgreSQL RESTART IDENTITY, which resets the
+// sequences of the table's identity columns.
+func (b TruncateBuilder) RestartIdentity() TruncateBuilder {
+	b.data.RestartIdentity = true
+	return b
+}
+
+// Cascade adds the PostgreSQL CASCADE, which also truncates the tables with
+// foreign keys to the table.
+func (b TruncateBuilder) Cascade() TruncateBuilder {
+	b.data.Cascade = true
+	return b
+}
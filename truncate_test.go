@@ -0,0 +1,49 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateBuilderToSql(t *testing.T) {
+	sql, args, err := Truncate("events").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE events", sql)
+	assert.Nil(t, args)
+
+	sql, _, err = Truncate("events").RestartIdentity().Cascade().PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE events RESTART IDENTITY CASCADE", sql)
+
+	sql, _, err = StatementBuilder.Dialect(SQLite).Truncate("events").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `DELETE FROM "events"`, sql)
+
+	sql, _, err = StatementBuilder.TablePathPrefix("/Root").Truncate("events").PlaceholderFormat(DollarP).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM `/Root/events`", sql)
+}
+
+func TestTruncateBuilderToSqlErrors(t *testing.T) {
+	_, _, err := Truncate("").ToSql()
+	assert.EqualError(t, err, "truncate builder: no table")
+
+	_, _, err = Truncate("events").Cascade().PlaceholderFormat(AtP).ToSql()
+	assert.EqualError(t, err, "truncate builder: RestartIdentity and Cascade are only supported by PostgreSQL")
+
+	_, _, err = StatementBuilder.Dialect(YDB).Truncate("events").RestartIdentity().ToSql()
+	assert.EqualError(t, err, "truncate builder: RestartIdentity and Cascade are not supported by this database")
+
+	assert.Panics(t, func() { Truncate("").MustSql() })
+}
+
+func TestTruncateBuilderExec(t *testing.T) {
+	db := &DBStub{}
+	_, err := Truncate("events").RunWith(db).Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, "TRUNCATE TABLE events", db.LastExecSql)
+
+	_, err = Truncate("events").Exec()
+	assert.Equal(t, RunnerNotSet, err)
+}
@@ -0,0 +1,58 @@
+package squirrel
+
+import (
+	"database/sql"
+)
+
+// TxBeginner is the interface that wraps the Begin method, as implemented by
+// *sql.DB.
+type TxBeginner interface {
+	Begin() (*sql.Tx, error)
+}
+
+// RunInTx runs fn in a transaction begun with db. The transaction is
+// committed if fn returns nil, and rolled back if fn returns an error or
+// panics.
+//
+// Ex:
+//     err := sq.RunInTx(db, func(tx sq.Runner) error {
+//         _, err := sq.ExecAllWith(tx,
+//             sq.Update("accounts").Set("balance", sq.Expr("balance - ?", 10)).Where(sq.Eq{"id": 1}),
+//             sq.Update("accounts").Set("balance", sq.Expr("balance + ?", 10)).Where(sq.Eq{"id": 2}),
+//         )
+//         return err
+//     })
+func RunInTx(db TxBeginner, fn func(tx Runner) error) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	return fn(WrapStdSql(tx))
+}
+
+// ExecAllWith Execs the SQL returned by each of sqlizers with db, in order.
+// It stops at the first error, returning the results of the statements run
+// until then.
+func ExecAllWith(db Execer, sqlizers ...Sqlizer) (results []sql.Result, err error) {
+	results = make([]sql.Result, 0, len(sqlizers))
+	for _, s := range sqlizers {
+		var res sql.Result
+		res, err = ExecWith(db, s)
+		if err != nil {
+			return
+		}
+		results = append(results, res)
+	}
+	return
+}
@@ -0,0 +1,54 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+)
+
+// TxBeginnerContext is the interface that wraps the BeginTx method, as
+// implemented by *sql.DB and *sql.Conn.
+type TxBeginnerContext interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// RunInTxContext runs fn in a transaction begun with db, ctx and opts. The
+// transaction is committed if fn returns nil, and rolled back if fn returns
+// an error or panics.
+//
+// See RunInTx.
+func RunInTxContext(ctx context.Context, db TxBeginnerContext, opts *sql.TxOptions, fn func(tx RunnerContext) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	return fn(WrapStdSqlCtx(tx))
+}
+
+// ExecAllContextWith ExecContexts the SQL returned by each of sqlizers with
+// db, in order. It stops at the first error, returning the results of the
+// statements run until then.
+func ExecAllContextWith(ctx context.Context, db ExecerContext, sqlizers ...Sqlizer) (results []sql.Result, err error) {
+	results = make([]sql.Result, 0, len(sqlizers))
+	for _, s := range sqlizers {
+		var res sql.Result
+		res, err = ExecContextWith(ctx, db, s)
+		if err != nil {
+			return
+		}
+		results = append(results, res)
+	}
+	return
+}
@@ -0,0 +1,21 @@
+// +build go1.8
+
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunInTxContext(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	err := RunInTxContext(ctx, db, nil, func(tx RunnerContext) error {
+		_, err := ExecAllContextWith(ctx, tx, Insert("a").Values(1), Delete("fail"))
+		return err
+	})
+	assert.EqualError(t, err, "exec failed")
+	assert.Equal(t, []string{"BEGIN", "INSERT INTO a VALUES (?)", "ROLLBACK"}, txDriver.log)
+}
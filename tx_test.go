@@ -0,0 +1,174 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// txDriverStub is a database/sql driver that logs begins, execs, commits and
+// rollbacks. Execs of statements containing "fail" return an error, and
+// queries containing "SELECT n FROM numbers" return the rows 1, 2 and 3, and
+// "SELECT word FROM numbers" returns them as text.
+type txDriverStub struct {
+	log []string
+}
+
+func (d *txDriverStub) Open(name string) (driver.Conn, error) {
+	return &txConnStub{d}, nil
+}
+
+type txConnStub struct {
+	d *txDriverStub
+}
+
+func (c *txConnStub) Prepare(query string) (driver.Stmt, error) {
+	return &txStmtStub{c.d, query}, nil
+}
+
+func (c *txConnStub) Close() error { return nil }
+
+func (c *txConnStub) Begin() (driver.Tx, error) {
+	c.d.log = append(c.d.log, "BEGIN")
+	return c, nil
+}
+
+func (c *txConnStub) Commit() error {
+	c.d.log = append(c.d.log, "COMMIT")
+	return nil
+}
+
+func (c *txConnStub) Rollback() error {
+	c.d.log = append(c.d.log, "ROLLBACK")
+	return nil
+}
+
+type txStmtStub struct {
+	d     *txDriverStub
+	query string
+}
+
+func (s *txStmtStub) Close() error  { return nil }
+func (s *txStmtStub) NumInput() int { return -1 }
+
+func (s *txStmtStub) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.Contains(s.query, "fail") {
+		return nil, errors.New("exec failed")
+	}
+	s.d.log = append(s.d.log, s.query)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *txStmtStub) Query(args []driver.Value) (driver.Rows, error) {
+	if strings.Contains(s.query, "SELECT n FROM numbers") {
+		return &txRowsStub{n: 3}, nil
+	}
+	if strings.Contains(s.query, "SELECT word FROM numbers") {
+		return &txRowsStub{n: 3, text: true}, nil
+	}
+	return nil, io.EOF
+}
+
+// txRowsStub returns the numbers 1 to n in a column "n", or as text in a
+// column "word".
+type txRowsStub struct {
+	n, i int64
+	text bool
+}
+
+func (r *txRowsStub) Columns() []string {
+	if r.text {
+		return []string{"word"}
+	}
+	return []string{"n"}
+}
+
+func (r *txRowsStub) Close() error { return nil }
+
+func (r *txRowsStub) ColumnTypeScanType(index int) reflect.Type {
+	if r.text {
+		return reflect.TypeOf("")
+	}
+	return reflect.TypeOf(int64(0))
+}
+
+func (r *txRowsStub) Next(dest []driver.Value) error {
+	if r.i >= r.n {
+		return io.EOF
+	}
+	r.i++
+	if r.text {
+		dest[0] = []byte(strconv.FormatInt(r.i, 10))
+	} else {
+		dest[0] = r.i
+	}
+	return nil
+}
+
+var txDriver = &txDriverStub{}
+
+func init() {
+	sql.Register("squirrel-tx-stub", txDriver)
+}
+
+func openTxStub(t *testing.T) *sql.DB {
+	txDriver.log = nil
+	db, err := sql.Open("squirrel-tx-stub", "")
+	assert.NoError(t, err)
+	return db
+}
+
+func TestRunInTx(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	var results []sql.Result
+	err := RunInTx(db, func(tx Runner) (err error) {
+		results, err = ExecAllWith(tx, Delete("a"), Update("b").Set("c", 1))
+		return
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, []string{"BEGIN", "DELETE FROM a", "UPDATE b SET c = ?", "COMMIT"}, txDriver.log)
+}
+
+func TestRunInTxRollback(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	var results []sql.Result
+	err := RunInTx(db, func(tx Runner) (err error) {
+		results, err = ExecAllWith(tx, Delete("a"), Delete("fail"), Delete("c"))
+		return
+	})
+	assert.EqualError(t, err, "exec failed")
+	assert.Len(t, results, 1)
+	assert.Equal(t, []string{"BEGIN", "DELETE FROM a", "ROLLBACK"}, txDriver.log)
+}
+
+func TestRunInTxPanic(t *testing.T) {
+	db := openTxStub(t)
+	defer db.Close()
+
+	assert.Panics(t, func() {
+		RunInTx(db, func(tx Runner) error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, []string{"BEGIN", "ROLLBACK"}, txDriver.log)
+}
+
+func TestExecAllWithToSqlError(t *testing.T) {
+	db := &DBStub{}
+	results, err := ExecAllWith(db, Delete("a"), Delete(""))
+	assert.Error(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "DELETE FROM a", db.LastExecSql)
+}
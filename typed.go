@@ -0,0 +1,82 @@
+//go:build go1.18
+// +build go1.18
+
+package squirrel
+
+// Column is a column whose values have the Go type T. Its methods build
+// conditions that only accept values of that type, so comparing a column
+// with a value of the wrong type fails to compile rather than at the
+// database:
+//     var (
+//         userAge  = Col[int]("age")
+//         userName = Col[string]("name")
+//     )
+//     Select("*").From("users").Where(And{userAge.Between(18, 65), userName.In("moe", "larry")})
+//     // SELECT * FROM users WHERE (age BETWEEN ? AND ? AND name IN (?,?))
+type Column[T any] string
+
+// Col returns the Column named name with values of type T.
+func Col[T any](name string) Column[T] {
+	return Column[T](name)
+}
+
+// Name returns the name of the column.
+func (c Column[T]) Name() string {
+	return string(c)
+}
+
+// Eq returns "column = v".
+func (c Column[T]) Eq(v T) Sqlizer {
+	return Eq{string(c): v}
+}
+
+// NotEq returns "column <> v".
+func (c Column[T]) NotEq(v T) Sqlizer {
+	return NotEq{string(c): v}
+}
+
+// Lt returns "column < v".
+func (c Column[T]) Lt(v T) Sqlizer {
+	return Lt{string(c): v}
+}
+
+// LtOrEq returns "column <= v".
+func (c Column[T]) LtOrEq(v T) Sqlizer {
+	return LtOrEq{string(c): v}
+}
+
+// Gt returns "column > v".
+func (c Column[T]) Gt(v T) Sqlizer {
+	return Gt{string(c): v}
+}
+
+// GtOrEq returns "column >= v".
+func (c Column[T]) GtOrEq(v T) Sqlizer {
+	return GtOrEq{string(c): v}
+}
+
+// In returns "column IN (vs...)". As with Eq, no values give a condition that
+// is always false.
+func (c Column[T]) In(vs ...T) Sqlizer {
+	return Eq{string(c): vs}
+}
+
+// NotIn returns "column NOT IN (vs...)".
+func (c Column[T]) NotIn(vs ...T) Sqlizer {
+	return NotEq{string(c): vs}
+}
+
+// Between returns "column BETWEEN lo AND hi".
+func (c Column[T]) Between(lo, hi T) Sqlizer {
+	return Between{string(c): [2]interface{}{lo, hi}}
+}
+
+// IsNull returns "column IS NULL".
+func (c Column[T]) IsNull() Sqlizer {
+	return Eq{string(c): nil}
+}
+
+// IsNotNull returns "column IS NOT NULL".
+func (c Column[T]) IsNotNull() Sqlizer {
+	return NotEq{string(c): nil}
+}
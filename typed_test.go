@@ -0,0 +1,55 @@
+//go:build go1.18
+// +build go1.18
+
+package squirrel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumn(t *testing.T) {
+	age := Col[int]("age")
+	name := Col[string]("name")
+	created := Col[time.Time]("created_at")
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		cond Sqlizer
+		sql  string
+		args []interface{}
+	}{
+		{age.Eq(3), "age = ?", []interface{}{3}},
+		{age.NotEq(3), "age <> ?", []interface{}{3}},
+		{age.Lt(3), "age < ?", []interface{}{3}},
+		{age.LtOrEq(3), "age <= ?", []interface{}{3}},
+		{age.Gt(3), "age > ?", []interface{}{3}},
+		{age.GtOrEq(3), "age >= ?", []interface{}{3}},
+		{age.Between(18, 65), "age BETWEEN ? AND ?", []interface{}{18, 65}},
+		{name.In("moe", "larry"), "name IN (?,?)", []interface{}{"moe", "larry"}},
+		{name.NotIn("curly"), "name NOT IN (?)", []interface{}{"curly"}},
+		{name.In(), "(1=0)", nil},
+		{created.IsNull(), "created_at IS NULL", nil},
+		{created.IsNotNull(), "created_at IS NOT NULL", nil},
+		{created.Gt(now), "created_at > ?", []interface{}{now}},
+	}
+	for _, test := range tests {
+		sql, args, err := test.cond.ToSql()
+		assert.NoError(t, err)
+		assert.Equal(t, test.sql, sql)
+		assert.Len(t, args, len(test.args))
+		if len(test.args) > 0 {
+			assert.Equal(t, test.args, args)
+		}
+	}
+	assert.Equal(t, "age", age.Name())
+
+	sql, args, err := Select("*").From("users").
+		Where(And{age.Between(18, 65), name.In("moe", "larry")}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE (age BETWEEN ? AND ? AND name IN (?,?))", sql)
+	assert.Equal(t, []interface{}{18, 65, "moe", "larry"}, args)
+}
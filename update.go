@@ -1,26 +1,28 @@
 package squirrel
 
 import (
-	"bytes"
 	"database/sql"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
-
-	"github.com/lann/builder"
 )
 
 type updateData struct {
 	PlaceholderFormat PlaceholderFormat
 	RunWith           BaseRunner
+	Pragmas           []pragma
+	Comments          []string
 	Prefixes          []Sqlizer
 	Table             string
 	SetClauses        []setClause
 	From              Sqlizer
+	Joins             []Sqlizer
 	WhereParts        []Sqlizer
 	OrderBys          []string
-	Limit             string
-	Offset            string
+	Limit             Sqlizer
+	Offset            Sqlizer
+	Returning         []Sqlizer
 	Suffixes          []Sqlizer
 }
 
@@ -29,6 +31,32 @@ type setClause struct {
 	value  interface{}
 }
 
+// appendSetClauses writes "col = value" pairs separated by commas to w.
+func appendSetClauses(clauses []setClause, w io.Writer, args []interface{}) ([]interface{}, error) {
+	setSqls := make([]string, len(clauses))
+	for i, setClause := range clauses {
+		var valSql string
+		if vs, ok := setClause.value.(Sqlizer); ok {
+			vsql, vargs, err := nestedToSql(vs)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := vs.(SelectBuilder); ok {
+				valSql = fmt.Sprintf("(%s)", vsql)
+			} else {
+				valSql = vsql
+			}
+			args = append(args, vargs...)
+		} else {
+			valSql = "?"
+			args = append(args, setClause.value)
+		}
+		setSqls[i] = fmt.Sprintf("%s = %s", setClause.column, valSql)
+	}
+	_, err := io.WriteString(w, strings.Join(setSqls, ", "))
+	return args, err
+}
+
 func (d *updateData) Exec() (sql.Result, error) {
 	if d.RunWith == nil {
 		return nil, RunnerNotSet
@@ -55,20 +83,78 @@ func (d *updateData) QueryRow() RowScanner {
 }
 
 func (d *updateData) ToSql() (sqlStr string, args []interface{}, err error) {
+	dialect := dialectKindOf(d.PlaceholderFormat)
+	// write expressions like ILike for the database
+	_, explicit := d.PlaceholderFormat.(dialectFormat)
+	written := d.forDialect(dialect, explicit)
+	d = &written
+
+	sqlStr, args, err = d.toSqlRaw()
+	if err != nil {
+		return
+	}
+
+	sqlStr, args, err = replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+	if err != nil {
+		return
+	}
+
+	sqlStr = commentsToSql(d.Comments) + pragmasToSql(d.Pragmas) + sqlStr
+	return
+}
+
+// forDialect returns d with its expressions, including those in subqueries,
+// written for the database dialect.
+func (d updateData) forDialect(dialect dialectKind, explicit bool) updateData {
+	d.Prefixes = forDialect(d.Prefixes, dialect, explicit)
+	d.SetClauses = setClausesForDialect(d.SetClauses, dialect, explicit)
+	if d.From != nil {
+		d.From, _ = partForDialect(d.From, dialect, explicit)
+	}
+	d.Joins = forDialect(d.Joins, dialect, explicit)
+	d.WhereParts = forDialect(d.WhereParts, dialect, explicit)
+	d.Returning = forDialect(d.Returning, dialect, explicit)
+	d.Suffixes = forDialect(d.Suffixes, dialect, explicit)
+	return d
+}
+
+// setClausesForDialect returns clauses with their Sqlizer values written for
+// the database dialect.
+func setClausesForDialect(clauses []setClause, dialect dialectKind, explicit bool) []setClause {
+	var rewritten []setClause
+	for i, c := range clauses {
+		value, ok := argForDialect(c.value, dialect, explicit)
+		if !ok {
+			continue
+		}
+		if rewritten == nil {
+			rewritten = append([]setClause(nil), clauses...)
+		}
+		rewritten[i] = setClause{column: c.column, value: value}
+	}
+	if rewritten == nil {
+		return clauses
+	}
+	return rewritten
+}
+
+func (d *updateData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
 	if len(d.Table) == 0 {
-		err = fmt.Errorf("update statements must specify a table")
+		err = builderError("update", "no table")
 		return
 	}
 	if len(d.SetClauses) == 0 {
-		err = fmt.Errorf("update statements must have at least one Set clause")
+		err = builderError("update", "no Set clauses")
 		return
 	}
 
-	sql := &bytes.Buffer{}
+	sql := getBuffer()
+	defer putBuffer(sql)
 
 	if len(d.Prefixes) > 0 {
 		args, err = appendToSql(d.Prefixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("update", "prefix", err)
 			return
 		}
 
@@ -78,41 +164,46 @@ func (d *updateData) ToSql() (sqlStr string, args []interface{}, err error) {
 	sql.WriteString("UPDATE ")
 	sql.WriteString(d.Table)
 
-	sql.WriteString(" SET ")
-	setSqls := make([]string, len(d.SetClauses))
-	for i, setClause := range d.SetClauses {
-		var valSql string
-		if vs, ok := setClause.value.(Sqlizer); ok {
-			vsql, vargs, err := vs.ToSql()
-			if err != nil {
-				return "", nil, err
-			}
-			if _, ok := vs.(SelectBuilder); ok {
-				valSql = fmt.Sprintf("(%s)", vsql)
-			} else {
-				valSql = vsql
-			}
-			args = append(args, vargs...)
-		} else {
-			valSql = "?"
-			args = append(args, setClause.value)
+	// without FROM, joins are MySQL style: UPDATE t JOIN u ON ... SET ...
+	if d.From == nil && len(d.Joins) > 0 {
+		sql.WriteString(" ")
+		args, err = appendToSql(d.Joins, sql, " ", args)
+		if err != nil {
+			err = clauseError("update", "JOIN", err)
+			return
 		}
-		setSqls[i] = fmt.Sprintf("%s = %s", setClause.column, valSql)
 	}
-	sql.WriteString(strings.Join(setSqls, ", "))
+
+	sql.WriteString(" SET ")
+	args, err = appendSetClauses(d.SetClauses, sql, args)
+	if err != nil {
+		err = clauseError("update", "SET", err)
+		return
+	}
 
 	if d.From != nil {
 		sql.WriteString(" FROM ")
 		args, err = appendToSql([]Sqlizer{d.From}, sql, "", args)
 		if err != nil {
+			err = clauseError("update", "FROM", err)
 			return
 		}
+
+		if len(d.Joins) > 0 {
+			sql.WriteString(" ")
+			args, err = appendToSql(d.Joins, sql, " ", args)
+			if err != nil {
+				err = clauseError("update", "JOIN", err)
+				return
+			}
+		}
 	}
 
 	if len(d.WhereParts) > 0 {
 		sql.WriteString(" WHERE ")
 		args, err = appendToSql(d.WhereParts, sql, " AND ", args)
 		if err != nil {
+			err = clauseError("update", "WHERE", err)
 			return
 		}
 	}
@@ -122,35 +213,63 @@ func (d *updateData) ToSql() (sqlStr string, args []interface{}, err error) {
 		sql.WriteString(strings.Join(d.OrderBys, ", "))
 	}
 
-	if len(d.Limit) > 0 {
+	if d.Limit != nil {
 		sql.WriteString(" LIMIT ")
-		sql.WriteString(d.Limit)
+		args, err = appendToSql([]Sqlizer{d.Limit}, sql, "", args)
+		if err != nil {
+			err = clauseError("update", "LIMIT", err)
+			return
+		}
 	}
 
-	if len(d.Offset) > 0 {
+	if d.Offset != nil {
 		sql.WriteString(" OFFSET ")
-		sql.WriteString(d.Offset)
+		args, err = appendToSql([]Sqlizer{d.Offset}, sql, "", args)
+		if err != nil {
+			err = clauseError("update", "OFFSET", err)
+			return
+		}
+	}
+
+	if len(d.Returning) > 0 {
+		sql.WriteString(" RETURNING ")
+		args, err = appendToSql(d.Returning, sql, ", ", args)
+		if err != nil {
+			err = clauseError("update", "RETURNING", err)
+			return
+		}
 	}
 
 	if len(d.Suffixes) > 0 {
 		sql.WriteString(" ")
 		args, err = appendToSql(d.Suffixes, sql, " ", args)
 		if err != nil {
+			err = clauseError("update", "suffix", err)
 			return
 		}
 	}
 
-	sqlStr, err = d.PlaceholderFormat.ReplacePlaceholders(sql.String())
+	sqlStr = sql.String()
 	return
 }
 
 // Builder
 
 // UpdateBuilder builds SQL UPDATE statements.
-type UpdateBuilder builder.Builder
+type UpdateBuilder struct {
+	data updateData
+	builderOptions
+}
 
-func init() {
-	builder.Register(UpdateBuilder{}, updateData{})
+// build applies the middlewares set with WithMiddleware and returns the data
+// to render.
+func (b UpdateBuilder) build() updateData {
+	if len(b.middlewares) > 0 {
+		mws := b.middlewares
+		b.middlewares = nil
+		b = applyMiddlewares(b, mws).(UpdateBuilder)
+	}
+	return b.data
 }
 
 // Format methods
@@ -158,29 +277,31 @@ func init() {
 // PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
 // query.
 func (b UpdateBuilder) PlaceholderFormat(f PlaceholderFormat) UpdateBuilder {
-	return builder.Set(b, "PlaceholderFormat", f).(UpdateBuilder)
+	b.data.PlaceholderFormat = f
+	return b
 }
 
 // Runner methods
 
 // RunWith sets a Runner (like database/sql.DB) to be used with e.g. Exec.
 func (b UpdateBuilder) RunWith(runner BaseRunner) UpdateBuilder {
-	return setRunWith(b, runner).(UpdateBuilder)
+	b.data.RunWith = wrapRunner(runner)
+	return b
 }
 
 // Exec builds and Execs the query with the Runner set by RunWith.
 func (b UpdateBuilder) Exec() (sql.Result, error) {
-	data := builder.GetStruct(b).(updateData)
+	data := b.build()
 	return data.Exec()
 }
 
 func (b UpdateBuilder) Query() (*sql.Rows, error) {
-	data := builder.GetStruct(b).(updateData)
+	data := b.build()
 	return data.Query()
 }
 
 func (b UpdateBuilder) QueryRow() RowScanner {
-	data := builder.GetStruct(b).(updateData)
+	data := b.build()
 	return data.QueryRow()
 }
 
@@ -192,10 +313,22 @@ func (b UpdateBuilder) Scan(dest ...interface{}) error {
 
 // ToSql builds the query into a SQL string and bound args.
 func (b UpdateBuilder) ToSql() (string, []interface{}, error) {
-	data := builder.GetStruct(b).(updateData)
+	data := b.build()
 	return data.ToSql()
 }
 
+func (b UpdateBuilder) toSqlRaw() (string, []interface{}, error) {
+	data := b.build()
+	return data.toSqlRaw()
+}
+
+// forDialect writes the expressions of b for the database d, when b is a
+// subquery.
+func (b UpdateBuilder) forDialect(d dialectKind, explicit bool) Sqlizer {
+	b.data = b.data.forDialect(d, explicit)
+	return b
+}
+
 // MustSql builds the query into a SQL string and bound args.
 // It panics if there are any errors.
 func (b UpdateBuilder) MustSql() (string, []interface{}) {
@@ -206,6 +339,56 @@ func (b UpdateBuilder) MustSql() (string, []interface{}) {
 	return sql, args
 }
 
+// When calls fn with b and returns its result if cond is true, and returns b
+// otherwise, to add optional clauses without breaking the chain:
+//     Update("users").Set("name", req.Name).
+//         When(req.Email != "", func(b UpdateBuilder) UpdateBuilder { return b.Set("email", req.Email) }).
+//         Where(Eq{"id": req.ID})
+func (b UpdateBuilder) When(cond bool, fn func(UpdateBuilder) UpdateBuilder) UpdateBuilder {
+	if !cond {
+		return b
+	}
+	return fn(b)
+}
+
+// ToSqlInterpolated builds the query into a SQL string with the args inlined
+// as literals.
+//
+// See SelectBuilder.ToSqlInterpolated.
+func (b UpdateBuilder) ToSqlInterpolated() (string, error) {
+	return InterpolateSqlizer(b)
+}
+
+// Freeze renders the query once and returns it as a FrozenQuery, which keeps
+// the Runner set by RunWith.
+//
+// See FrozenQuery.
+func (b UpdateBuilder) Freeze() FrozenQuery {
+	data := b.build()
+	q := Freeze(&data)
+	q.runWith = data.RunWith
+	return q
+}
+
+// Pragma adds a YQL "PRAGMA name(value);" statement before the query, e.g.
+// Pragma("TablePathPrefix", "/Root/db"). An empty value omits the parentheses.
+//
+// Pragmas are only rendered for the outermost query; those set on nested
+// builders are ignored.
+func (b UpdateBuilder) Pragma(name, value string) UpdateBuilder {
+	b.data.Pragmas = appendPragma(b.data.Pragmas, name, value)
+	return b
+}
+
+// Comment adds a "/* comment */" before the query, e.g. to tag it with the
+// call site or trace metadata for DBAs.
+//
+// See SelectBuilder.Comment.
+func (b UpdateBuilder) Comment(comment string) UpdateBuilder {
+	b.data.Comments = appendStrings(b.data.Comments, comment)
+	return b
+}
+
 // Prefix adds an expression to the beginning of the query
 func (b UpdateBuilder) Prefix(sql string, args ...interface{}) UpdateBuilder {
 	return b.PrefixExpr(Expr(sql, args...))
@@ -213,17 +396,29 @@ func (b UpdateBuilder) Prefix(sql string, args ...interface{}) UpdateBuilder {
 
 // PrefixExpr adds an expression to the very beginning of the query
 func (b UpdateBuilder) PrefixExpr(expr Sqlizer) UpdateBuilder {
-	return builder.Append(b, "Prefixes", expr).(UpdateBuilder)
+	b.data.Prefixes = appendSqlizers(b.data.Prefixes, expr)
+	return b
+}
+
+// WithNamed adds a YQL named expression, "$name = expr;", in front of the
+// query.
+//
+// See SelectBuilder.WithNamed.
+func (b UpdateBuilder) WithNamed(name string, expr Sqlizer) UpdateBuilder {
+	return b.PrefixExpr(yqlNamedExpr{name: name, expr: expr, terminated: true})
 }
 
 // Table sets the table to be updated.
 func (b UpdateBuilder) Table(table string) UpdateBuilder {
-	return builder.Set(b, "Table", table).(UpdateBuilder)
+	b.data.Table = b.qualifyTable(table)
+	return b
 }
 
 // Set adds SET clauses to the query.
 func (b UpdateBuilder) Set(column string, value interface{}) UpdateBuilder {
-	return builder.Append(b, "SetClauses", setClause{column: column, value: value}).(UpdateBuilder)
+	clauses := b.data.SetClauses
+	b.data.SetClauses = append(clauses[:len(clauses):len(clauses)], setClause{column: b.quoteColumn(column), value: value})
+	return b
 }
 
 // SetMap is a convenience method which calls .Set for each key/value pair in clauses.
@@ -242,39 +437,141 @@ func (b UpdateBuilder) SetMap(clauses map[string]interface{}) UpdateBuilder {
 	return b
 }
 
+// SetStruct is a convenience method which calls .Set for each field of v, a
+// struct or a pointer to one, in field order.
+//
+// Fields map to columns as in InsertBuilder.SetStruct. For partial updates,
+// pass OnlyFields to set just the given columns or SkipZero to leave out
+// fields that weren't filled in.
+//
+// Ex:
+//     Update("users").
+//         SetStruct(req, SkipZero(), SkipFields("id")).
+//         Where(Eq{"id": req.ID})
+func (b UpdateBuilder) SetStruct(v interface{}, opts ...StructOption) UpdateBuilder {
+	cols, vals := structValues(v, opts)
+	for i, col := range cols {
+		b = b.Set(col, vals[i])
+	}
+	return b
+}
+
 // From adds FROM clause to the query
 // FROM is valid construct in postgresql only.
+//
+// Ex:
+//     Update("orders o").Set("status", "void").
+//         From("customers c").Where("c.id = o.customer_id AND c.banned")
+//     // UPDATE orders o SET status = ? FROM customers c WHERE c.id = o.customer_id AND c.banned
 func (b UpdateBuilder) From(from string) UpdateBuilder {
-	return builder.Set(b, "From", newPart(from)).(UpdateBuilder)
+	b.data.From = newPart(b.qualifyTable(from))
+	return b
 }
 
 // FromSelect sets a subquery into the FROM clause of the query.
 func (b UpdateBuilder) FromSelect(from SelectBuilder, alias string) UpdateBuilder {
 	// Prevent misnumbered parameters in nested selects (#183).
 	from = from.PlaceholderFormat(Question)
-	return builder.Set(b, "From", Alias(from, alias)).(UpdateBuilder)
+	b.data.From = Alias(from, alias)
+	return b
+}
+
+// FromValues sets a VALUES list of literal rows into the FROM clause of the
+// query. values should be named with ValuesBuilder.As.
+func (b UpdateBuilder) FromValues(values ValuesBuilder) UpdateBuilder {
+	b.data.From = values
+	return b
+}
+
+// FromExpr sets a table expression, e.g. a ValuesBuilder or a function call,
+// into the FROM clause of the query.
+func (b UpdateBuilder) FromExpr(from Sqlizer) UpdateBuilder {
+	b.data.From = from
+	return b
+}
+
+// JoinClause adds a join clause to the query.
+//
+// With a FROM clause, joins follow it, as in PostgreSQL:
+//     UPDATE t SET ... FROM a JOIN b ON ... WHERE ...
+// Without one, joins follow the table, as in MySQL:
+//     UPDATE t JOIN b ON ... SET ... WHERE ...
+func (b UpdateBuilder) JoinClause(pred interface{}, args ...interface{}) UpdateBuilder {
+	b.data.Joins = appendSqlizers(b.data.Joins, newPart(pred, args...))
+	return b
+}
+
+// Join adds a JOIN clause to the query.
+func (b UpdateBuilder) Join(join string, rest ...interface{}) UpdateBuilder {
+	return b.JoinClause("JOIN "+b.qualifyTable(join), rest...)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query.
+func (b UpdateBuilder) LeftJoin(join string, rest ...interface{}) UpdateBuilder {
+	return b.JoinClause("LEFT JOIN "+b.qualifyTable(join), rest...)
+}
+
+// InnerJoin adds a INNER JOIN clause to the query.
+func (b UpdateBuilder) InnerJoin(join string, rest ...interface{}) UpdateBuilder {
+	return b.JoinClause("INNER JOIN "+b.qualifyTable(join), rest...)
 }
 
 // Where adds WHERE expressions to the query.
 //
 // See SelectBuilder.Where for more information.
 func (b UpdateBuilder) Where(pred interface{}, args ...interface{}) UpdateBuilder {
-	return builder.Append(b, "WhereParts", newWherePart(pred, args...)).(UpdateBuilder)
+	b.data.WhereParts = appendSqlizers(b.data.WhereParts, newWherePart(pred, args...))
+	return b
 }
 
 // OrderBy adds ORDER BY expressions to the query.
 func (b UpdateBuilder) OrderBy(orderBys ...string) UpdateBuilder {
-	return builder.Extend(b, "OrderBys", orderBys).(UpdateBuilder)
+	b.data.OrderBys = appendStrings(b.data.OrderBys, orderBys...)
+	return b
 }
 
 // Limit sets a LIMIT clause on the query.
 func (b UpdateBuilder) Limit(limit uint64) UpdateBuilder {
-	return builder.Set(b, "Limit", fmt.Sprintf("%d", limit)).(UpdateBuilder)
+	b.data.Limit = b.limitPart(limit)
+	return b
+}
+
+// LimitParam sets a LIMIT clause on the query with the limit bound as a
+// placeholder arg rather than inlined into the SQL.
+func (b UpdateBuilder) LimitParam(limit uint64) UpdateBuilder {
+	b.data.Limit = newPart("?", limit)
+	return b
 }
 
 // Offset sets a OFFSET clause on the query.
 func (b UpdateBuilder) Offset(offset uint64) UpdateBuilder {
-	return builder.Set(b, "Offset", fmt.Sprintf("%d", offset)).(UpdateBuilder)
+	b.data.Offset = b.limitPart(offset)
+	return b
+}
+
+// OffsetParam sets an OFFSET clause on the query with the offset bound as a
+// placeholder arg rather than inlined into the SQL.
+func (b UpdateBuilder) OffsetParam(offset uint64) UpdateBuilder {
+	b.data.Offset = newPart("?", offset)
+	return b
+}
+
+// Returning adds RETURNING expressions to the query.
+func (b UpdateBuilder) Returning(columns ...string) UpdateBuilder {
+	parts := make([]Sqlizer, 0, len(columns))
+	for _, str := range columns {
+		parts = append(parts, newPart(str))
+	}
+	b.data.Returning = appendSqlizers(b.data.Returning, parts...)
+	return b
+}
+
+// ReturningSelect adds a subquery to the RETURNING clause of the query.
+func (b UpdateBuilder) ReturningSelect(from SelectBuilder, alias string) UpdateBuilder {
+	// Prevent misnumbered parameters in nested selects (#183).
+	from = from.PlaceholderFormat(Question)
+	b.data.Returning = appendSqlizers(b.data.Returning, Alias(from, alias))
+	return b
 }
 
 // Suffix adds an expression to the end of the query
@@ -284,5 +581,6 @@ func (b UpdateBuilder) Suffix(sql string, args ...interface{}) UpdateBuilder {
 
 // SuffixExpr adds an expression to the end of the query
 func (b UpdateBuilder) SuffixExpr(expr Sqlizer) UpdateBuilder {
-	return builder.Append(b, "Suffixes", expr).(UpdateBuilder)
+	b.data.Suffixes = appendSqlizers(b.data.Suffixes, expr)
+	return b
 }
@@ -5,8 +5,6 @@ package squirrel
 import (
 	"context"
 	"database/sql"
-
-	"github.com/lann/builder"
 )
 
 func (d *updateData) ExecContext(ctx context.Context) (sql.Result, error) {
@@ -47,19 +45,19 @@ func (d *updateData) QueryRowContext(ctx context.Context) RowScanner {
 
 // ExecContext builds and ExecContexts the query with the Runner set by RunWith.
 func (b UpdateBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
-	data := builder.GetStruct(b).(updateData)
+	data := b.build()
 	return data.ExecContext(ctx)
 }
 
 // QueryContext builds and QueryContexts the query with the Runner set by RunWith.
 func (b UpdateBuilder) QueryContext(ctx context.Context) (*sql.Rows, error) {
-	data := builder.GetStruct(b).(updateData)
+	data := b.build()
 	return data.QueryContext(ctx)
 }
 
 // QueryRowContext builds and QueryRowContexts the query with the Runner set by RunWith.
 func (b UpdateBuilder) QueryRowContext(ctx context.Context) RowScanner {
-	data := builder.GetStruct(b).(updateData)
+	data := b.build()
 	return data.QueryRowContext(ctx)
 }
 
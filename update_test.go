@@ -105,3 +105,121 @@ func TestUpdateBuilderFromSelect(t *testing.T) {
 			"WHERE employees.account_id = subquery.id"
 	assert.Equal(t, expectedSql, sql)
 }
+
+func TestUpdateBuilderLimitOffsetParam(t *testing.T) {
+	sql, args, err := Update("t").Set("a", 1).LimitParam(2).OffsetParam(3).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = ? LIMIT ? OFFSET ?", sql)
+	assert.Equal(t, []interface{}{1, uint64(2), uint64(3)}, args)
+}
+
+func TestUpdateBuilderReturning(t *testing.T) {
+	sql, args, err := Update("a").
+		Set("b", 1).
+		Where("c = ?", 2).
+		Returning("b").
+		ReturningSelect(Select("count(*)").From("d").Where("d.a = ?", 3), "n").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "UPDATE a SET b = $1 WHERE c = $2 RETURNING b, (SELECT count(*) FROM d WHERE d.a = $3) AS n"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+}
+
+func TestUpdateBuilderNestedPlaceholders(t *testing.T) {
+	sub := Select("max(b)").From("c").Where("c.d = ?", 1).PlaceholderFormat(DollarP)
+	sql, args, err := Update("a").
+		Set("b", sub).
+		Set("e", Expr("e + ?", Expr("?", 2))).
+		Where("f = ?", 3).
+		PlaceholderFormat(DollarP).
+		ToSql()
+	assert.NoError(t, err)
+
+	assert.Equal(t, "UPDATE a SET b = (SELECT max(b) FROM c WHERE c.d = $p1), e = e + $p2 WHERE f = $p3", sql)
+	assert.Equal(t, []interface{}{1, 2, 3}, args)
+}
+
+func TestUpdateBuilderSetStruct(t *testing.T) {
+	type user struct {
+		ID    int64  `db:"id"`
+		Name  string `db:"name"`
+		Email string `db:"email"`
+		Age   int    `db:"age"`
+	}
+	u := user{ID: 1, Name: "moe", Age: 30}
+
+	sql, args, err := Update("users").
+		SetStruct(u, SkipZero(), SkipFields("id")).
+		Where(Eq{"id": u.ID}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ?, age = ? WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{"moe", 30, int64(1)}, args)
+
+	sql, args, err = Update("users").SetStruct(&u, OnlyFields("Email")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET email = ?", sql)
+	assert.Equal(t, []interface{}{""}, args)
+}
+
+func TestUpdateBuilderFromJoins(t *testing.T) {
+	sql, args, err := Update("orders o").
+		Set("status", "void").
+		From("customers c").
+		Join("regions r ON r.id = c.region_id AND r.name = ?", "north").
+		Where("c.id = o.customer_id AND c.banned = ?", true).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE orders o SET status = $1 FROM customers c "+
+		"JOIN regions r ON r.id = c.region_id AND r.name = $2 "+
+		"WHERE c.id = o.customer_id AND c.banned = $3", sql)
+	assert.Equal(t, []interface{}{"void", "north", true}, args)
+
+	sql, args, err = Update("orders o").
+		InnerJoin("customers c ON c.id = o.customer_id").
+		LeftJoin("regions r ON r.id = c.region_id").
+		Set("o.status", "void").
+		Where(Eq{"c.banned": true}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE orders o INNER JOIN customers c ON c.id = o.customer_id "+
+		"LEFT JOIN regions r ON r.id = c.region_id SET o.status = ? WHERE c.banned = ?", sql)
+	assert.Equal(t, []interface{}{"void", true}, args)
+}
+
+func TestUpdateBuilderFromExpr(t *testing.T) {
+	sql, args, err := Update("t").
+		Set("n", Expr("s.n")).
+		FromExpr(Expr("generate_series(1, ?) AS s (n)", 3)).
+		Where("t.id = s.n").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET n = s.n FROM generate_series(1, $1) AS s (n) WHERE t.id = s.n", sql)
+	assert.Equal(t, []interface{}{3}, args)
+
+	sql, args, err = Update("t").
+		Set("a", 1).
+		FromSelect(Select("id").From("u").Where("x = ?", 2), "s").
+		Where("t.id = s.id").
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE t SET a = $1 FROM (SELECT id FROM u WHERE x = $2) AS s WHERE t.id = s.id", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestUpdateBuilderWhen(t *testing.T) {
+	email := ""
+	sql, args, err := Update("users").Set("name", "ann").
+		When(email != "", func(b UpdateBuilder) UpdateBuilder { return b.Set("email", email) }).
+		Where(Eq{"id": 1}).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", sql)
+	assert.Equal(t, []interface{}{"ann", 1}, args)
+}
@@ -0,0 +1,256 @@
+package squirrel
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// validate returns the structural problems of a statement. If there are
+// none, it renders s to check its clauses, and the types of its args when s
+// is written for YQL.
+func validate(builder string, f PlaceholderFormat, problems []error, s Sqlizer) []error {
+	if len(problems) > 0 {
+		return problems
+	}
+	_, args, err := s.ToSql()
+	if err != nil {
+		return []error{err}
+	}
+	if dialectKindOf(f) == dialectYQL {
+		for i, arg := range args {
+			if !yqlArgSupported(arg) {
+				problems = append(problems, &BuilderError{
+					Builder: builder,
+					Err:     fmt.Errorf("arg %d: type %T is not supported by YQL", i+1, arg),
+				})
+			}
+		}
+	}
+	return problems
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+	valuerType   = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// yqlArgSupported tells whether v can be bound as a YQL parameter: nil,
+// bools, numbers, strings, []byte, time.Time, time.Duration, driver.Valuers,
+// and lists of those.
+func yqlArgSupported(v interface{}) bool {
+	if named, ok := v.(sql.NamedArg); ok {
+		v = named.Value
+	}
+	if v == nil {
+		return true
+	}
+	return yqlTypeSupported(reflect.TypeOf(v))
+}
+
+func yqlTypeSupported(t reflect.Type) bool {
+	if t == timeType || t == durationType || t.Implements(valuerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return yqlTypeSupported(t.Elem())
+	}
+	return false
+}
+
+// Validate reports every structural problem of the query at once, e.g. a
+// missing table or a row of values with too many columns, without running
+// it. It returns nil if the query is valid.
+//
+// If the structure is valid, Validate renders the query to check its
+// clauses, and for the DollarP placeholder format (YQL) checks that each arg
+// has a type YDB can bind.
+//
+// Ex:
+//     func TestListUsersQuery(t *testing.T) {
+//         if errs := listUsersQuery(filter).Validate(); errs != nil {
+//             t.Fatal(errs)
+//         }
+//     }
+func (b SelectBuilder) Validate() []error {
+	d := b.build()
+	var problems []error
+	if len(d.Columns) == 0 {
+		problems = append(problems, builderError("select", "no result columns"))
+	}
+	if len(d.DistinctOn) > 0 && dialectKindOf(d.PlaceholderFormat) != dialectPostgres {
+		problems = append(problems, builderError("select", "DISTINCT ON is only supported by PostgreSQL"))
+	}
+	return validate("select", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the query at once.
+//
+// See SelectBuilder.Validate.
+func (b InsertBuilder) Validate() []error {
+	d := b.build()
+	var problems []error
+	if len(d.Into) == 0 {
+		problems = append(problems, builderError("insert", "no table"))
+	}
+	if len(d.Values) == 0 && d.Select == nil {
+		problems = append(problems, builderError("insert", "no values or select"))
+	}
+	if len(d.Values) > 0 && d.Select != nil {
+		problems = append(problems, builderError("insert", "both values and select are set"))
+	}
+	for i, row := range d.Values {
+		if len(d.Columns) > 0 && len(row) != len(d.Columns) {
+			problems = append(problems, builderError("insert",
+				fmt.Sprintf("row %d has %d values for %d columns", i, len(row), len(d.Columns))))
+		} else if len(d.Columns) == 0 && len(row) != len(d.Values[0]) {
+			problems = append(problems, builderError("insert",
+				fmt.Sprintf("row %d has %d values, expected %d", i, len(row), len(d.Values[0]))))
+		}
+	}
+	return validate("insert", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the query at once.
+//
+// See SelectBuilder.Validate.
+func (b UpdateBuilder) Validate() []error {
+	d := b.build()
+	var problems []error
+	if len(d.Table) == 0 {
+		problems = append(problems, builderError("update", "no table"))
+	}
+	if len(d.SetClauses) == 0 {
+		problems = append(problems, builderError("update", "no Set clauses"))
+	}
+	return validate("update", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the query at once.
+//
+// See SelectBuilder.Validate.
+func (b DeleteBuilder) Validate() []error {
+	d := b.build()
+	var problems []error
+	if len(d.From) == 0 {
+		problems = append(problems, builderError("delete", "no From table"))
+	}
+	return validate("delete", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the query at once.
+//
+// See SelectBuilder.Validate.
+func (b MergeBuilder) Validate() []error {
+	d := b.data
+	var problems []error
+	if len(d.Into) == 0 {
+		problems = append(problems, builderError("merge", "no target table (Into)"))
+	}
+	if d.Using == nil {
+		problems = append(problems, builderError("merge", "no source (Using)"))
+	}
+	if d.On == nil {
+		problems = append(problems, builderError("merge", "no join condition (On)"))
+	}
+	if len(d.Whens) == 0 {
+		problems = append(problems, builderError("merge", "no WHEN clauses"))
+	}
+	return validate("merge", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the VALUES list at once.
+//
+// See SelectBuilder.Validate.
+func (b ValuesBuilder) Validate() []error {
+	d := b.data
+	var problems []error
+	if len(d.Rows) == 0 {
+		problems = append(problems, builderError("values", "no rows"))
+	}
+	for i, row := range d.Rows {
+		if len(row) != len(d.Rows[0]) {
+			problems = append(problems, builderError("values",
+				fmt.Sprintf("row %d has %d values, expected %d", i, len(row), len(d.Rows[0]))))
+		}
+	}
+	return validate("values", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the CASE expression at once.
+//
+// See SelectBuilder.Validate.
+func (b CaseBuilder) Validate() []error {
+	d := b.data
+	var problems []error
+	if len(d.WhenParts) == 0 {
+		problems = append(problems, builderError("case", "no WHEN clauses"))
+	}
+	return validate("case", nil, problems, &d)
+}
+
+// Validate reports every structural problem of the statement at once.
+//
+// See SelectBuilder.Validate.
+func (b CreateTableBuilder) Validate() []error {
+	d := b.data
+	var problems []error
+	if len(d.Table) == 0 {
+		problems = append(problems, builderError("create table", "no table"))
+	}
+	if len(d.Columns) == 0 {
+		problems = append(problems, builderError("create table", "no columns"))
+	}
+	return validate("create table", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the statement at once.
+//
+// See SelectBuilder.Validate.
+func (b AlterTableBuilder) Validate() []error {
+	d := b.data
+	var problems []error
+	if len(d.Table) == 0 {
+		problems = append(problems, builderError("alter table", "no table"))
+	}
+	if len(d.Actions) == 0 {
+		problems = append(problems, builderError("alter table", "no actions"))
+	}
+	return validate("alter table", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the statement at once.
+//
+// See SelectBuilder.Validate.
+func (b DropTableBuilder) Validate() []error {
+	d := b.data
+	var problems []error
+	if len(d.Table) == 0 {
+		problems = append(problems, builderError("drop table", "no table"))
+	}
+	return validate("drop table", d.PlaceholderFormat, problems, &d)
+}
+
+// Validate reports every structural problem of the statement at once.
+//
+// See SelectBuilder.Validate.
+func (b CreateIndexBuilder) Validate() []error {
+	d := b.data
+	var problems []error
+	if len(d.Table) == 0 {
+		problems = append(problems, builderError("create index", "no table"))
+	}
+	if len(d.Columns) == 0 {
+		problems = append(problems, builderError("create index", "no columns"))
+	}
+	return validate("create index", d.PlaceholderFormat, problems, &d)
+}
@@ -0,0 +1,77 @@
+package squirrel
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func errorStrings(errs []error) []string {
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return msgs
+}
+
+func TestValidateValid(t *testing.T) {
+	assert.Nil(t, Select("*").From("t").Where(Eq{"a": 1}).Validate())
+	assert.Nil(t, Insert("t").Columns("a", "b").Values(1, 2).Validate())
+	assert.Nil(t, Update("t").Set("a", 1).Validate())
+	assert.Nil(t, Delete("t").Validate())
+	assert.Nil(t, Values([]interface{}{1}).Validate())
+	assert.Nil(t, CreateTable("t").Column("a", "Int32").Validate())
+	assert.Nil(t, DropTable("t").Validate())
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	errs := Insert("").Columns("a", "b").Values(1).Values(1, 2, 3).Select(Select("1")).Validate()
+	assert.Equal(t, []string{
+		"insert builder: no table",
+		"insert builder: both values and select are set",
+		"insert builder: row 0 has 1 values for 2 columns",
+		"insert builder: row 1 has 3 values for 2 columns",
+	}, errorStrings(errs))
+
+	assert.Equal(t, []string{
+		"update builder: no table",
+		"update builder: no Set clauses",
+	}, errorStrings(Update("").SetMap(map[string]interface{}{}).Validate()))
+
+	assert.Equal(t, []string{"delete builder: no From table"}, errorStrings(Delete("").Validate()))
+	assert.Equal(t, []string{
+		"merge builder: no target table (Into)",
+		"merge builder: no source (Using)",
+		"merge builder: no join condition (On)",
+		"merge builder: no WHEN clauses",
+	}, errorStrings(Merge("").Validate()))
+	assert.Equal(t, []string{
+		"create table builder: no table",
+		"create table builder: no columns",
+	}, errorStrings(CreateTable("").Validate()))
+	assert.Equal(t, []string{"case builder: no WHEN clauses"}, errorStrings(Case().Validate()))
+}
+
+func TestValidateClauses(t *testing.T) {
+	errs := Select("*").From("t").Where(Like{"a": nil}).Validate()
+	assert.Equal(t, []string{"select builder: WHERE: cannot use null with like operators"}, errorStrings(errs))
+}
+
+func TestValidateYQLArgs(t *testing.T) {
+	b := Select("*").From("t").
+		Where(Eq{"a": 1, "b": []string{"x"}}).
+		Where("c = ? AND d = ? AND e = ?", time.Now(), sql.NullString{}, []byte("x")).
+		PlaceholderFormat(DollarP)
+	assert.Nil(t, b.Validate())
+
+	b = Select("*").From("t").Where("a = ? AND b = ?", map[string]int{}, struct{}{})
+	assert.Nil(t, b.Validate())
+
+	errs := b.PlaceholderFormat(DollarP).Validate()
+	assert.Equal(t, []string{
+		"select builder: arg 1: type map[string]int is not supported by YQL",
+		"select builder: arg 2: type struct {} is not supported by YQL",
+	}, errorStrings(errs))
+}
@@ -0,0 +1,143 @@
+package squirrel
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// valuesData holds all the data required to build a VALUES table expression
+type valuesData struct {
+	PlaceholderFormat PlaceholderFormat
+	Rows              [][]interface{}
+	Alias             string
+	Columns           []string
+}
+
+func (d *valuesData) ToSql() (sqlStr string, args []interface{}, err error) {
+	sqlStr, args, err = d.toSqlRaw()
+	if err != nil {
+		return
+	}
+	return replacePlaceholders(d.PlaceholderFormat, sqlStr, args)
+}
+
+func (d *valuesData) toSqlRaw() (sqlStr string, args []interface{}, err error) {
+	if len(d.Rows) == 0 {
+		err = builderError("values", "no rows")
+		return
+	}
+
+	sql := &bytes.Buffer{}
+	if d.Alias != "" {
+		sql.WriteString("(")
+	}
+	sql.WriteString("VALUES ")
+
+	for r, row := range d.Rows {
+		if len(row) != len(d.Rows[0]) {
+			err = builderError("values", fmt.Sprintf("row %d has %d values, expected %d", r, len(row), len(d.Rows[0])))
+			return
+		}
+		if r > 0 {
+			sql.WriteString(",")
+		}
+		sql.WriteString("(")
+		for v, val := range row {
+			if v > 0 {
+				sql.WriteString(",")
+			}
+			if vs, ok := val.(Sqlizer); ok {
+				vsql, vargs, err := nestedToSql(vs)
+				if err != nil {
+					return "", nil, clauseError("values", fmt.Sprintf("row %d", r), err)
+				}
+				sql.WriteString(vsql)
+				args = append(args, vargs...)
+			} else {
+				sql.WriteString("?")
+				args = append(args, val)
+			}
+		}
+		sql.WriteString(")")
+	}
+
+	if d.Alias != "" {
+		sql.WriteString(") AS ")
+		sql.WriteString(d.Alias)
+		if len(d.Columns) > 0 {
+			sql.WriteString(" (")
+			sql.WriteString(strings.Join(d.Columns, ", "))
+			sql.WriteString(")")
+		}
+	}
+
+	sqlStr = sql.String()
+	return
+}
+
+// ValuesBuilder builds VALUES lists of literal rows, for use as a table in
+// FROM and JOIN clauses.
+type ValuesBuilder struct {
+	data valuesData
+}
+
+// Values returns a new ValuesBuilder with the given rows.
+//
+// Ex:
+//     v := Values([]interface{}{1, "a"}, []interface{}{2, "b"}).As("v", "id", "name")
+//     Update("items i").Set("name", Expr("v.name")).FromValues(v).Where("i.id = v.id")
+//     // UPDATE items i SET name = v.name FROM (VALUES (?,?),(?,?)) AS v (id, name) WHERE i.id = v.id
+func Values(rows ...[]interface{}) ValuesBuilder {
+	return ValuesBuilder{}.PlaceholderFormat(Question).Rows(rows...)
+}
+
+// PlaceholderFormat sets PlaceholderFormat (e.g. Question or Dollar) for the
+// expression.
+func (b ValuesBuilder) PlaceholderFormat(f PlaceholderFormat) ValuesBuilder {
+	b.data.PlaceholderFormat = f
+	return b
+}
+
+// ToSql builds the expression into a SQL string and bound args.
+func (b ValuesBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+func (b ValuesBuilder) toSqlRaw() (string, []interface{}, error) {
+	data := b.data
+	return data.toSqlRaw()
+}
+
+// MustSql builds the expression into a SQL string and bound args.
+// It panics if there are any errors.
+func (b ValuesBuilder) MustSql() (string, []interface{}) {
+	sql, args, err := b.ToSql()
+	if err != nil {
+		panic(err)
+	}
+	return sql, args
+}
+
+// Rows adds rows to the expression. All rows must have the same number of
+// values. Sqlizer values are written in place, others are bound as args.
+func (b ValuesBuilder) Rows(rows ...[]interface{}) ValuesBuilder {
+	r := b.data.Rows
+	b.data.Rows = append(r[:len(r):len(r)], rows...)
+	return b
+}
+
+// Row adds a row to the expression.
+func (b ValuesBuilder) Row(values ...interface{}) ValuesBuilder {
+	return b.Rows(values)
+}
+
+// As names the expression and, optionally, its columns:
+// "(VALUES ...) AS alias (columns)". An alias is required to use the
+// expression in FROM and JOIN clauses.
+func (b ValuesBuilder) As(alias string, columns ...string) ValuesBuilder {
+	b.data.Alias = alias
+	b.data.Columns = columns
+	return b
+}
@@ -0,0 +1,54 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuesBuilderToSql(t *testing.T) {
+	sql, args, err := Values([]interface{}{1, "a"}).Row(2, Expr("lower(?)", "B")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "VALUES (?,?),(?,lower(?))", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "B"}, args)
+
+	sql, _, err = Values().Row(1, "a").As("v", "id", "name").PlaceholderFormat(Dollar).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "(VALUES ($1,$2)) AS v (id, name)", sql)
+
+	_, _, err = Values().ToSql()
+	assert.Error(t, err)
+	_, _, err = Values().Row(1, 2).Row(3).ToSql()
+	assert.Error(t, err)
+	assert.Panics(t, func() { Values().MustSql() })
+}
+
+func TestValuesBuilderInQueries(t *testing.T) {
+	v := Values().Row(1, "a").Row(2, "b").As("v", "id", "name")
+
+	sql, args, err := Update("items i").
+		Set("name", Expr("v.name")).
+		FromValues(v).
+		Where("i.id = v.id AND i.owner = ?", 7).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE items i SET name = v.name FROM (VALUES ($1,$2),($3,$4)) AS v (id, name) "+
+		"WHERE i.id = v.id AND i.owner = $5", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b", 7}, args)
+
+	sql, args, err = Select("t.*", "v.name").
+		From("t").
+		JoinClause(ConcatExpr("JOIN ", v, " ON v.id = t.id")).
+		Where("t.x > ?", 0).
+		PlaceholderFormat(Dollar).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT t.*, v.name FROM t JOIN (VALUES ($1,$2),($3,$4)) AS v (id, name) "+
+		"ON v.id = t.id WHERE t.x > $5", sql)
+	assert.Equal(t, []interface{}{1, "a", 2, "b", 0}, args)
+
+	sql, _, err = Select("*").FromValues(v.PlaceholderFormat(Dollar)).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM (VALUES (?,?),(?,?)) AS v (id, name)", sql)
+}
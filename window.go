@@ -0,0 +1,144 @@
+package squirrel
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// windowData holds all the data required to build a window specification
+type windowData struct {
+	PartitionBys []string
+	OrderBys     []string
+	Frame        string
+}
+
+// ToSql implements Sqlizer. It renders the window specification without the
+// surrounding parentheses.
+func (d *windowData) ToSql() (sqlStr string, args []interface{}, err error) {
+	var parts []string
+
+	if len(d.PartitionBys) > 0 {
+		parts = append(parts, "PARTITION BY "+strings.Join(d.PartitionBys, ", "))
+	}
+
+	if len(d.OrderBys) > 0 {
+		parts = append(parts, "ORDER BY "+strings.Join(d.OrderBys, ", "))
+	}
+
+	if len(d.Frame) > 0 {
+		parts = append(parts, d.Frame)
+	}
+
+	sqlStr = strings.Join(parts, " ")
+	return
+}
+
+// WindowBuilder builds window specifications used by OVER and WINDOW clauses.
+type WindowBuilder struct {
+	data windowData
+}
+
+// Window returns a new, empty WindowBuilder.
+func Window() WindowBuilder {
+	return WindowBuilder{}
+}
+
+// ToSql builds the window specification into a SQL string and bound args.
+func (b WindowBuilder) ToSql() (string, []interface{}, error) {
+	data := b.data
+	return data.ToSql()
+}
+
+// PartitionBy adds PARTITION BY expressions to the window.
+func (b WindowBuilder) PartitionBy(partitionBys ...string) WindowBuilder {
+	b.data.PartitionBys = appendStrings(b.data.PartitionBys, partitionBys...)
+	return b
+}
+
+// OrderBy adds ORDER BY expressions to the window.
+func (b WindowBuilder) OrderBy(orderBys ...string) WindowBuilder {
+	b.data.OrderBys = appendStrings(b.data.OrderBys, orderBys...)
+	return b
+}
+
+// Rows sets a "ROWS BETWEEN start AND end" frame clause on the window.
+//
+// Ex:
+//     Window().OrderBy("day").Rows("6 PRECEDING", "CURRENT ROW")
+func (b WindowBuilder) Rows(start, end string) WindowBuilder {
+	return b.frame("ROWS", start, end)
+}
+
+// Range sets a "RANGE BETWEEN start AND end" frame clause on the window.
+func (b WindowBuilder) Range(start, end string) WindowBuilder {
+	return b.frame("RANGE", start, end)
+}
+
+func (b WindowBuilder) frame(unit, start, end string) WindowBuilder {
+	frame := fmt.Sprintf("%s BETWEEN %s AND %s", unit, start, end)
+	b.data.Frame = frame
+	return b
+}
+
+// overExpr renders "fn OVER window"
+type overExpr struct {
+	fn     Sqlizer
+	window interface{}
+}
+
+// Over builds a window function call, "fn OVER window".
+//
+// fn may be a string or a Sqlizer. window may be a WindowBuilder, which is
+// rendered inline, or the name of a window defined with SelectBuilder.Window.
+//
+// Ex:
+//     Over("SUM(amount)", Window().PartitionBy("account_id").OrderBy("created"))
+//     // SUM(amount) OVER (PARTITION BY account_id ORDER BY created)
+func Over(fn interface{}, window interface{}) Sqlizer {
+	return overExpr{fn: newPart(fn), window: window}
+}
+
+func (e overExpr) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = e.fn.ToSql()
+	if err != nil {
+		return
+	}
+
+	buf := bytes.NewBufferString(sql)
+	buf.WriteString(" OVER ")
+
+	switch w := e.window.(type) {
+	case string:
+		buf.WriteString(w)
+	case WindowBuilder:
+		var wSql string
+		var wArgs []interface{}
+		wSql, wArgs, err = w.ToSql()
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(buf, "(%s)", wSql)
+		args = append(args, wArgs...)
+	default:
+		err = fmt.Errorf("expected window name or WindowBuilder, not %T", w)
+		return
+	}
+
+	sql = buf.String()
+	return
+}
+
+// namedWindow renders "name AS (window)" for the WINDOW clause of a select
+type namedWindow struct {
+	name   string
+	window WindowBuilder
+}
+
+func (w namedWindow) ToSql() (sql string, args []interface{}, err error) {
+	sql, args, err = w.window.ToSql()
+	if err == nil {
+		sql = fmt.Sprintf("%s AS (%s)", w.name, sql)
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package squirrel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowBuilder(t *testing.T) {
+	w := Window().
+		PartitionBy("a", "b").
+		OrderBy("c DESC").
+		Rows("UNBOUNDED PRECEDING", "CURRENT ROW")
+
+	sql, args, err := w.ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "PARTITION BY a, b ORDER BY c DESC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW"
+	assert.Equal(t, expectedSql, sql)
+	assert.Empty(t, args)
+
+	sql, _, err = Window().OrderBy("day").Range("1 PRECEDING", "1 FOLLOWING").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ORDER BY day RANGE BETWEEN 1 PRECEDING AND 1 FOLLOWING", sql)
+}
+
+func TestOver(t *testing.T) {
+	sql, args, err := Over(Expr("COALESCE(amount, ?)", 0), Window().PartitionBy("account")).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "COALESCE(amount, ?) OVER (PARTITION BY account)", sql)
+	assert.Equal(t, []interface{}{0}, args)
+
+	sql, _, err = Over("ROW_NUMBER()", "w").ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "ROW_NUMBER() OVER w", sql)
+
+	_, _, err = Over("ROW_NUMBER()", 1).ToSql()
+	assert.Error(t, err)
+}
+
+func TestSelectBuilderWindow(t *testing.T) {
+	sql, args, err := Select("id").
+		Column(Alias(Over("SUM(amount)", "w"), "running_total")).
+		Column(Over("RANK()", Window().OrderBy("amount DESC"))).
+		From("payments").
+		Where("amount > ?", 10).
+		Window("w", Window().PartitionBy("account").OrderBy("created")).
+		OrderBy("id").
+		ToSql()
+	assert.NoError(t, err)
+
+	expectedSql := "SELECT id, (SUM(amount) OVER w) AS running_total, RANK() OVER (ORDER BY amount DESC) " +
+		"FROM payments WHERE amount > ? " +
+		"WINDOW w AS (PARTITION BY account ORDER BY created) " +
+		"ORDER BY id"
+	assert.Equal(t, expectedSql, sql)
+	assert.Equal(t, []interface{}{10}, args)
+}